@@ -0,0 +1,257 @@
+//+build windows
+
+/*
+Package assets loads textures, surfaces and WAV sounds from an fs.FS, e.g. an
+embed.FS or os.DirFS, and caches them by path so every file is only loaded once.
+
+	//go:embed data
+	var data embed.FS
+
+	cache := assets.New(data, renderer)
+	defer cache.Close()
+	for running {
+		for e := sdl.PollEvent(); e != nil; e = sdl.PollEvent() {
+			if handled, err := cache.HandleEvent(e); handled {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			// handle other events
+		}
+		player, err := cache.Texture("data/player.png")
+		if err != nil {
+			return err
+		}
+		renderer.Copy(player, nil, &sdl.Rect{X: x, Y: y, W: 32, H: 32})
+		renderer.Present()
+	}
+
+Some render drivers lose all textures when the graphics device is reset, e.g.
+after the screen was locked. The Manager then recreates its textures from the
+files, see HandleEvent. Because of this, get a texture from the Manager when
+drawing it instead of keeping the *sdl.Texture around.
+
+BMP files are loaded by SDL, all other image formats are decoded with
+image.Decode. PNG and JPEG are registered by this package, import other image
+packages like image/gif to load their formats.
+
+Like all render functions, use a Manager only from the main thread.
+*/
+package assets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/gonutz/go-sdl2/sdl"
+)
+
+// Manager loads and caches assets. It owns everything it loads, do not
+// destroy or free the returned textures, surfaces or WAVs, call Close instead.
+type Manager struct {
+	fs       fs.FS
+	renderer *sdl.Renderer
+	textures map[string]*sdl.Texture
+	surfaces map[string]*sdl.Surface
+	wavs     map[string]*WAV
+}
+
+// WAV is an audio buffer loaded from a WAV file, in the format given by Spec.
+type WAV struct {
+	Data []byte
+	Spec sdl.AudioSpec
+}
+
+// New returns a Manager that reads files from fsys and creates textures with
+// the renderer. The renderer may be nil if only surfaces and WAVs are loaded.
+func New(fsys fs.FS, renderer *sdl.Renderer) *Manager {
+	return &Manager{
+		fs:       fsys,
+		renderer: renderer,
+		textures: make(map[string]*sdl.Texture),
+		surfaces: make(map[string]*sdl.Surface),
+		wavs:     make(map[string]*WAV),
+	}
+}
+
+// Close destroys all textures and frees all surfaces and WAVs. The Manager
+// can still be used afterwards, it will load the files again.
+func (m *Manager) Close() {
+	for path, texture := range m.textures {
+		texture.Destroy()
+		delete(m.textures, path)
+	}
+	for path, surface := range m.surfaces {
+		surface.Free()
+		delete(m.surfaces, path)
+	}
+	for path, wav := range m.wavs {
+		sdl.FreeWAV(wav.Data)
+		delete(m.wavs, path)
+	}
+}
+
+// HandleEvent recreates all textures on RENDER_TARGETS_RESET and
+// RENDER_DEVICE_RESET events. It returns true if the event was one of these,
+// in which case err tells whether all textures could be reloaded. Textures
+// that fail to reload are removed from the cache so the next call to Texture
+// tries again.
+//
+// The new textures have default settings, set things like the blend mode or
+// color modulation again after a reset.
+func (m *Manager) HandleEvent(e sdl.Event) (handled bool, err error) {
+	switch e.GetType() {
+	case sdl.RENDER_TARGETS_RESET, sdl.RENDER_DEVICE_RESET:
+		return true, m.ReloadTextures()
+	}
+	return false, nil
+}
+
+// ReloadTextures destroys all cached textures and loads them again from their
+// files. It returns the first error, textures that cannot be loaded are
+// removed from the cache.
+func (m *Manager) ReloadTextures() error {
+	var firstErr error
+	for path, old := range m.textures {
+		old.Destroy()
+		texture, err := m.loadTexture(path)
+		if err != nil {
+			delete(m.textures, path)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		m.textures[path] = texture
+	}
+	return firstErr
+}
+
+// Surface returns the image file at path as a surface, loading it on the
+// first call.
+func (m *Manager) Surface(path string) (*sdl.Surface, error) {
+	if surface, ok := m.surfaces[path]; ok {
+		return surface, nil
+	}
+	surface, err := m.loadSurface(path)
+	if err != nil {
+		return nil, err
+	}
+	m.surfaces[path] = surface
+	return surface, nil
+}
+
+// Texture returns the image file at path as a texture, loading it on the
+// first call.
+func (m *Manager) Texture(path string) (*sdl.Texture, error) {
+	if texture, ok := m.textures[path]; ok {
+		return texture, nil
+	}
+	texture, err := m.loadTexture(path)
+	if err != nil {
+		return nil, err
+	}
+	m.textures[path] = texture
+	return texture, nil
+}
+
+// WAV returns the WAV file at path, loading it on the first call.
+func (m *Manager) WAV(path string) (*WAV, error) {
+	if wav, ok := m.wavs[path]; ok {
+		return wav, nil
+	}
+	rw, err := m.open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer rw.Close()
+	data, spec := sdl.LoadWAVRW(rw, false)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("assets: cannot load WAV %s: %v", path, sdlError())
+	}
+	wav := &WAV{Data: data, Spec: *spec}
+	m.wavs[path] = wav
+	return wav, nil
+}
+
+func (m *Manager) loadTexture(path string) (*sdl.Texture, error) {
+	if m.renderer == nil {
+		return nil, errors.New("assets: cannot load textures without a renderer")
+	}
+	// Use the cached surface if there is one, otherwise only keep the
+	// surface until the texture is created.
+	surface, cached := m.surfaces[path]
+	if !cached {
+		var err error
+		surface, err = m.loadSurface(path)
+		if err != nil {
+			return nil, err
+		}
+		defer surface.Free()
+	}
+	texture, err := m.renderer.CreateTextureFromSurface(surface)
+	if err != nil {
+		return nil, fmt.Errorf("assets: cannot create texture for %s: %v", path, err)
+	}
+	return texture, nil
+}
+
+func (m *Manager) loadSurface(filePath string) (*sdl.Surface, error) {
+	if strings.EqualFold(path.Ext(filePath), ".bmp") {
+		rw, err := m.open(filePath)
+		if err != nil {
+			return nil, err
+		}
+		defer rw.Close()
+		surface, err := sdl.LoadBMPRW(rw, false)
+		if err != nil {
+			return nil, fmt.Errorf("assets: cannot load BMP %s: %v", filePath, err)
+		}
+		return surface, nil
+	}
+
+	data, err := fs.ReadFile(m.fs, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("assets: %v", err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("assets: cannot decode image %s: %v", filePath, err)
+	}
+	surface, err := sdl.CreateSurfaceFromImage(img)
+	if err != nil {
+		return nil, fmt.Errorf("assets: cannot create surface for %s: %v", filePath, err)
+	}
+	return surface, nil
+}
+
+// open reads the whole file into memory and returns an RWops on it, which the
+// caller must close.
+func (m *Manager) open(path string) (*sdl.RWops, error) {
+	data, err := fs.ReadFile(m.fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("assets: %v", err)
+	}
+	rw, err := sdl.RWFromBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("assets: cannot read %s: %v", path, err)
+	}
+	return rw, nil
+}
+
+// sdlError returns SDL's error message or a generic error if SDL did not set
+// one.
+func sdlError() error {
+	if err := sdl.GetError(); err != nil {
+		return err
+	}
+	return errors.New("unknown SDL error")
+}
@@ -0,0 +1,194 @@
+//+build windows
+
+package assets_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gonutz/check"
+	"github.com/gonutz/go-sdl2/sdl"
+	"github.com/gonutz/go-sdl2/sdl/assets"
+)
+
+func test(f func()) {
+	sdl.Main(func() {
+		sdl.Do(f)
+	})
+}
+
+var (
+	red   = color.RGBA{R: 255, A: 255}
+	green = color.RGBA{G: 255, A: 255}
+	blue  = color.RGBA{B: 255, A: 255}
+)
+
+func TestSurfacesAreLoadedFromBMPAndPNG(t *testing.T) {
+	test(func() {
+		fsys := fstest.MapFS{
+			"a.bmp": {Data: bmpFile(red, green)},
+			"b.png": {Data: pngFile(t, blue, green)},
+		}
+		m := assets.New(fsys, nil)
+		defer m.Close()
+
+		fromBMP, err := m.Surface("a.bmp")
+		check.Eq(t, err, nil)
+		check.Eq(t, pixels(t, fromBMP), []color.RGBA{red, green})
+
+		fromPNG, err := m.Surface("b.png")
+		check.Eq(t, err, nil)
+		check.Eq(t, pixels(t, fromPNG), []color.RGBA{blue, green})
+
+		_, err = m.Surface("missing.png")
+		check.Neq(t, err, nil)
+		_, err = m.Texture("a.bmp")
+		check.Neq(t, err, nil) // there is no renderer
+	})
+}
+
+func TestAssetsAreCachedByPath(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(2, 1)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+
+		fsys := fstest.MapFS{"a.png": {Data: pngFile(t, red, green)}}
+		m := assets.New(fsys, renderer)
+		defer m.Close()
+
+		s1, err := m.Surface("a.png")
+		check.Eq(t, err, nil)
+		t1, err := m.Texture("a.png")
+		check.Eq(t, err, nil)
+
+		// Cached assets do not read the file again.
+		delete(fsys, "a.png")
+		s2, err := m.Surface("a.png")
+		check.Eq(t, err, nil)
+		check.Eq(t, s2 == s1, true)
+		t2, err := m.Texture("a.png")
+		check.Eq(t, err, nil)
+		check.Eq(t, t2 == t1, true)
+
+		check.Eq(t, renderer.Copy(t2, nil, nil), nil)
+		renderer.Present()
+		check.Eq(t, pixels(t, surface), []color.RGBA{red, green})
+	})
+}
+
+func TestReloadTexturesDropsTexturesThatFailToLoad(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(2, 1)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+
+		fsys := fstest.MapFS{
+			"a.png": {Data: pngFile(t, red, red)},
+			"b.bmp": {Data: bmpFile(blue, blue)},
+		}
+		m := assets.New(fsys, renderer)
+		defer m.Close()
+		_, err = m.Texture("a.png")
+		check.Eq(t, err, nil)
+		_, err = m.Texture("b.bmp")
+		check.Eq(t, err, nil)
+
+		delete(fsys, "a.png")
+		fsys["b.bmp"] = &fstest.MapFile{Data: bmpFile(green, green)}
+		check.Neq(t, m.ReloadTextures(), nil)
+
+		// The failed texture is not cached anymore, the other one was
+		// reloaded from the changed file.
+		_, err = m.Texture("a.png")
+		check.Neq(t, err, nil)
+		b, err := m.Texture("b.bmp")
+		check.Eq(t, err, nil)
+		check.Eq(t, renderer.Copy(b, nil, nil), nil)
+		renderer.Present()
+		check.Eq(t, pixels(t, surface), []color.RGBA{green, green})
+
+		handled, err := m.HandleEvent(&sdl.RenderEvent{Type: sdl.RENDER_TARGETS_RESET})
+		check.Eq(t, handled, true)
+		check.Eq(t, err, nil)
+		handled, _ = m.HandleEvent(&sdl.QuitEvent{Type: sdl.QUIT})
+		check.Eq(t, handled, false)
+	})
+}
+
+func TestAssetsCanBeLoadedAgainAfterClose(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(2, 1)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+
+		fsys := fstest.MapFS{"a.bmp": {Data: bmpFile(red, blue)}}
+		m := assets.New(fsys, renderer)
+		defer m.Close()
+		_, err = m.Texture("a.bmp")
+		check.Eq(t, err, nil)
+		m.Close()
+
+		fsys["a.bmp"] = &fstest.MapFile{Data: bmpFile(blue, red)}
+		texture, err := m.Texture("a.bmp")
+		check.Eq(t, err, nil)
+		check.Eq(t, renderer.Copy(texture, nil, nil), nil)
+		renderer.Present()
+		check.Eq(t, pixels(t, surface), []color.RGBA{blue, red})
+	})
+}
+
+// pixels returns the first row of the surface.
+func pixels(t *testing.T, surface *sdl.Surface) []color.RGBA {
+	img, err := surface.ToRGBA()
+	check.Eq(t, err, nil)
+	var row []color.RGBA
+	for x := 0; x < img.Bounds().Dx(); x++ {
+		row = append(row, img.RGBAAt(x, 0))
+	}
+	return row
+}
+
+// bmpFile encodes the colors as a one pixel high, 24 bit BMP.
+func bmpFile(colors ...color.RGBA) []byte {
+	const headerSize = 14 + 40
+	rowSize := (len(colors)*3 + 3) / 4 * 4
+	var buf bytes.Buffer
+	write := func(v interface{}) { binary.Write(&buf, binary.LittleEndian, v) }
+	buf.WriteString("BM")
+	write(uint32(headerSize + rowSize)) // file size
+	write(uint32(0))                    // reserved
+	write(uint32(headerSize))           // pixel offset
+	write(uint32(40))                   // info header size
+	write(int32(len(colors)))           // width
+	write(int32(1))                     // height
+	write(uint16(1))                    // planes
+	write(uint16(24))                   // bits per pixel
+	write(uint32(0))                    // no compression
+	write(uint32(rowSize))              // image size
+	write([4]uint32{2835, 2835, 0, 0})  // resolution and palette
+	for _, c := range colors {
+		buf.Write([]byte{c.B, c.G, c.R})
+	}
+	buf.Write(make([]byte, rowSize-len(colors)*3))
+	return buf.Bytes()
+}
+
+// pngFile encodes the colors as a one pixel high PNG.
+func pngFile(t *testing.T, colors ...color.RGBA) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, len(colors), 1))
+	for x, c := range colors {
+		img.SetRGBA(x, 0, c)
+	}
+	var buf bytes.Buffer
+	check.Eq(t, png.Encode(&buf, img), nil)
+	return buf.Bytes()
+}
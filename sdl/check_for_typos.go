@@ -26,6 +26,7 @@ func main() {
 	checkFile("sdl_windows.go")
 	checkFile("sdl_windows_386.go")
 	checkFile("sdl_windows_amd64.go")
+	checkFile("procs_generated_windows.go")
 }
 
 func checkFile(path string) {
@@ -194,10 +195,16 @@ func checkFile(path string) {
 
 			// here are some special cases which are not covered by the above rules
 			special := [][3]string{
+				{"AudioDeviceID", "Dequeue", "dequeueAudio"},
+				{"Cond", "WaitContext", "condWaitTimeout"},
+				{"", "LogResetOutputFunction", "logSetOutputFunction"},
+				{"", "MixAudioFormatRaw", "mixAudioFormat"},
+				{"", "MixAudioRaw", "mixAudio"},
 				{"PixelFormat", "Free", "freeFormat"},
 				{"RWops", "Close", "rwClose"},
 				{"RWops", "Free", "freeRW"},
 				{"Sem", "Destroy", "destroySemaphore"},
+				{"Sem", "TryWaitTimeout", "semWaitTimeout"},
 				{"SharedObject", "Unload", "unloadObject"},
 				{"Texture", "UpdateRGBA", "updateTexture"},
 			}
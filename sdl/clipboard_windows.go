@@ -0,0 +1,310 @@
+//+build windows
+
+package sdl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"reflect"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// SDL only supports text on the clipboard. Images are put on and taken off
+// the Windows clipboard directly, as a device independent bitmap (CF_DIB) and
+// as PNG which keeps the alpha channel intact for programs that support it.
+
+var (
+	user32   = syscall.NewLazyDLL("user32.dll")
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+
+	openClipboard              = user32.NewProc("OpenClipboard")
+	closeClipboard             = user32.NewProc("CloseClipboard")
+	emptyClipboard             = user32.NewProc("EmptyClipboard")
+	getClipboardData           = user32.NewProc("GetClipboardData")
+	setClipboardData           = user32.NewProc("SetClipboardData")
+	isClipboardFormatAvailable = user32.NewProc("IsClipboardFormatAvailable")
+	registerClipboardFormat    = user32.NewProc("RegisterClipboardFormatW")
+	globalAlloc                = kernel32.NewProc("GlobalAlloc")
+	globalFree                 = kernel32.NewProc("GlobalFree")
+	globalLock                 = kernel32.NewProc("GlobalLock")
+	globalUnlock               = kernel32.NewProc("GlobalUnlock")
+	globalSize                 = kernel32.NewProc("GlobalSize")
+)
+
+const (
+	cfDIB        = 8
+	gmemMoveable = 0x0002
+	biRGB        = 0
+	biBitfields  = 3
+)
+
+// ErrNoClipboardImage is returned by GetClipboardImage if the clipboard does
+// not contain an image in a supported format.
+var ErrNoClipboardImage = errors.New("sdl: the clipboard does not contain an image")
+
+var pngClipboardFormat struct {
+	once   sync.Once
+	format uintptr
+}
+
+// pngFormat returns the registered "PNG" clipboard format or 0 if it could
+// not be registered.
+func pngFormat() uintptr {
+	pngClipboardFormat.once.Do(func() {
+		name, _ := syscall.UTF16PtrFromString("PNG")
+		pngClipboardFormat.format, _, _ = registerClipboardFormat.Call(
+			uintptr(unsafe.Pointer(name)),
+		)
+	})
+	return pngClipboardFormat.format
+}
+
+// GetClipboardImage returns the image on the clipboard. The window is needed
+// to open the clipboard. PNG data is preferred over bitmaps since it contains
+// the alpha channel. If there is no image on the clipboard, the error is
+// ErrNoClipboardImage.
+func GetClipboardImage(window *Window) (image.Image, error) {
+	if err := openWindowClipboard(window); err != nil {
+		return nil, err
+	}
+	defer closeClipboard.Call()
+
+	if format := pngFormat(); format != 0 && clipboardHasFormat(format) {
+		data, err := clipboardBytes(format)
+		if err != nil {
+			return nil, err
+		}
+		return png.Decode(bytes.NewReader(data))
+	}
+	if clipboardHasFormat(cfDIB) {
+		data, err := clipboardBytes(cfDIB)
+		if err != nil {
+			return nil, err
+		}
+		return decodeDIB(data)
+	}
+	return nil, ErrNoClipboardImage
+}
+
+// SetClipboardImage puts the image on the clipboard, replacing its contents.
+// The window is needed to open the clipboard and becomes its owner.
+func SetClipboardImage(window *Window, img image.Image) error {
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, img); err != nil {
+		return err
+	}
+	dib := encodeDIB(img)
+
+	if err := openWindowClipboard(window); err != nil {
+		return err
+	}
+	defer closeClipboard.Call()
+
+	if ret, _, err := emptyClipboard.Call(); ret == 0 {
+		return fmt.Errorf("sdl: EmptyClipboard failed: %v", err)
+	}
+	if err := setClipboardBytes(cfDIB, dib); err != nil {
+		return err
+	}
+	if format := pngFormat(); format != 0 {
+		return setClipboardBytes(format, pngData.Bytes())
+	}
+	return nil
+}
+
+func openWindowClipboard(window *Window) error {
+	info, err := window.GetWMInfo()
+	if err != nil {
+		return err
+	}
+	hwnd := info.GetWindowsInfo().Window
+	if ret, _, err := openClipboard.Call(uintptr(hwnd)); ret == 0 {
+		return fmt.Errorf("sdl: OpenClipboard failed: %v", err)
+	}
+	return nil
+}
+
+func clipboardHasFormat(format uintptr) bool {
+	ret, _, _ := isClipboardFormatAvailable.Call(format)
+	return ret != 0
+}
+
+// clipboardBytes returns a copy of the clipboard data in the given format.
+// The clipboard must be open.
+func clipboardBytes(format uintptr) ([]byte, error) {
+	h, _, err := getClipboardData.Call(format)
+	if h == 0 {
+		return nil, fmt.Errorf("sdl: GetClipboardData failed: %v", err)
+	}
+	size, _, _ := globalSize.Call(h)
+	p, _, err := globalLock.Call(h)
+	if p == 0 {
+		return nil, fmt.Errorf("sdl: GlobalLock failed: %v", err)
+	}
+	defer globalUnlock.Call(h)
+	return append([]byte(nil), globalBytes(p, int(size))...), nil
+}
+
+// setClipboardBytes copies data into global memory and hands it to the
+// clipboard. The clipboard must be open.
+func setClipboardBytes(format uintptr, data []byte) error {
+	h, _, err := globalAlloc.Call(gmemMoveable, uintptr(len(data)))
+	if h == 0 {
+		return fmt.Errorf("sdl: GlobalAlloc failed: %v", err)
+	}
+	p, _, err := globalLock.Call(h)
+	if p == 0 {
+		globalFree.Call(h)
+		return fmt.Errorf("sdl: GlobalLock failed: %v", err)
+	}
+	copy(globalBytes(p, len(data)), data)
+	globalUnlock.Call(h)
+	if ret, _, err := setClipboardData.Call(format, h); ret == 0 {
+		// The clipboard only owns the memory if SetClipboardData succeeds.
+		globalFree.Call(h)
+		return fmt.Errorf("sdl: SetClipboardData failed: %v", err)
+	}
+	return nil
+}
+
+func globalBytes(p uintptr, size int) []byte {
+	var b []byte
+	sliceHeader := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	sliceHeader.Cap = size
+	sliceHeader.Len = size
+	sliceHeader.Data = p
+	return b
+}
+
+// decodeDIB decodes a packed device independent bitmap, i.e. a
+// BITMAPINFOHEADER followed by the pixels. Uncompressed 24 and 32 bit images
+// are supported.
+func decodeDIB(data []byte) (image.Image, error) {
+	if len(data) < 40 {
+		return nil, errors.New("sdl: clipboard bitmap is too short")
+	}
+	le := binary.LittleEndian
+	headerSize := int(le.Uint32(data[0:]))
+	width := int(int32(le.Uint32(data[4:])))
+	height := int(int32(le.Uint32(data[8:])))
+	bitCount := int(le.Uint16(data[14:]))
+	compression := le.Uint32(data[16:])
+
+	topDown := height < 0
+	if topDown {
+		height = -height
+	}
+	if width <= 0 || height <= 0 || headerSize < 40 || headerSize > len(data) {
+		return nil, errors.New("sdl: invalid clipboard bitmap header")
+	}
+	if !(bitCount == 24 && compression == biRGB) &&
+		!(bitCount == 32 && (compression == biRGB || compression == biBitfields)) {
+		return nil, fmt.Errorf(
+			"sdl: unsupported clipboard bitmap (%d bits, compression %d)",
+			bitCount, compression,
+		)
+	}
+
+	offset := headerSize
+	rMask, gMask, bMask := uint32(0xFF0000), uint32(0xFF00), uint32(0xFF)
+	if compression == biBitfields {
+		if headerSize == 40 {
+			// The color masks follow a BITMAPINFOHEADER, newer headers
+			// contain them.
+			offset += 12
+		}
+		if len(data) < 52 {
+			return nil, errors.New("sdl: clipboard bitmap is too short")
+		}
+		rMask, gMask, bMask = le.Uint32(data[40:]), le.Uint32(data[44:]), le.Uint32(data[48:])
+	}
+
+	stride := (width*bitCount + 31) / 32 * 4
+	if len(data) < offset+stride*height {
+		return nil, errors.New("sdl: clipboard bitmap is too short")
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	hasAlpha := false
+	for y := 0; y < height; y++ {
+		row := data[offset+y*stride:]
+		dstY := height - 1 - y
+		if topDown {
+			dstY = y
+		}
+		for x := 0; x < width; x++ {
+			var c color.NRGBA
+			if bitCount == 24 {
+				p := row[x*3:]
+				c = color.NRGBA{R: p[2], G: p[1], B: p[0], A: 255}
+			} else {
+				v := le.Uint32(row[x*4:])
+				c = color.NRGBA{
+					R: maskedByte(v, rMask),
+					G: maskedByte(v, gMask),
+					B: maskedByte(v, bMask),
+					A: byte(v >> 24),
+				}
+				hasAlpha = hasAlpha || c.A != 0
+			}
+			img.SetNRGBA(x, dstY, c)
+		}
+	}
+	if bitCount == 32 && !hasAlpha {
+		// Most programs leave the fourth byte at 0 and mean opaque pixels.
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 255
+		}
+	}
+	return img, nil
+}
+
+func maskedByte(v, mask uint32) byte {
+	if mask == 0 {
+		return 0
+	}
+	for mask&1 == 0 {
+		mask >>= 1
+		v >>= 1
+	}
+	return byte(v & mask)
+}
+
+// encodeDIB returns the image as a packed, bottom-up, 32 bit device
+// independent bitmap.
+func encodeDIB(img image.Image) []byte {
+	b := img.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), img, b.Min, draw.Src)
+
+	width, height := b.Dx(), b.Dy()
+	data := make([]byte, 40+width*height*4)
+	le := binary.LittleEndian
+	le.PutUint32(data[0:], 40)
+	le.PutUint32(data[4:], uint32(width))
+	le.PutUint32(data[8:], uint32(height))
+	le.PutUint16(data[12:], 1)
+	le.PutUint16(data[14:], 32)
+	le.PutUint32(data[16:], biRGB)
+	le.PutUint32(data[20:], uint32(width*height*4))
+	pixels := data[40:]
+	for y := 0; y < height; y++ {
+		src := nrgba.Pix[y*nrgba.Stride:]
+		dst := pixels[(height-1-y)*width*4:]
+		for x := 0; x < width; x++ {
+			dst[x*4+0] = src[x*4+2]
+			dst[x*4+1] = src[x*4+1]
+			dst[x*4+2] = src[x*4+0]
+			dst[x*4+3] = src[x*4+3]
+		}
+	}
+	return data
+}
@@ -0,0 +1,278 @@
+//+build windows
+
+/*
+Package controllertest draws a diagnostic view of all connected game
+controllers, showing which buttons are pressed and where the sticks and
+triggers are. Applications can embed it in their settings screen so players
+can check that their controller works and is mapped correctly.
+
+Initialize the INIT_GAMECONTROLLER subsystem, create a Scene and pass it all
+events, then draw it each frame:
+
+	scene := controllertest.New()
+	defer scene.Close()
+	for running {
+		for e := sdl.PollEvent(); e != nil; e = sdl.PollEvent() {
+			scene.HandleEvent(e)
+		}
+		renderer.Clear()
+		scene.Draw(renderer, sdl.Rect{X: 0, Y: 0, W: 640, H: 480})
+		renderer.Present()
+	}
+
+Each controller gets a panel of its own, stacked from top to bottom in the
+order they were connected. The panels are drawn with rectangles only, in a
+layout resembling an Xbox controller: shoulder buttons and triggers at the
+top, the left stick and D-pad on the left, the face buttons and right stick on
+the right and Back, Guide and Start in the middle.
+*/
+package controllertest
+
+import (
+	"github.com/gonutz/go-sdl2/sdl"
+)
+
+// The panel layout is designed in this virtual size and then scaled to the
+// actual panel size.
+const (
+	panelW = 320
+	panelH = 160
+)
+
+var (
+	backgroundColor = sdl.Color{R: 32, G: 32, B: 40, A: 255}
+	outlineColor    = sdl.Color{R: 160, G: 160, B: 170, A: 255}
+	pressedColor    = sdl.Color{R: 80, G: 220, B: 100, A: 255}
+	axisColor       = sdl.Color{R: 80, G: 150, B: 240, A: 255}
+)
+
+// buttonRects are the positions of the buttons in the virtual panel.
+var buttonRects = map[sdl.GameControllerButton]sdl.Rect{
+	sdl.CONTROLLER_BUTTON_LEFTSHOULDER:  {X: 20, Y: 5, W: 60, H: 12},
+	sdl.CONTROLLER_BUTTON_RIGHTSHOULDER: {X: 240, Y: 5, W: 60, H: 12},
+	sdl.CONTROLLER_BUTTON_DPAD_UP:       {X: 80, Y: 95, W: 20, H: 20},
+	sdl.CONTROLLER_BUTTON_DPAD_DOWN:     {X: 80, Y: 135, W: 20, H: 20},
+	sdl.CONTROLLER_BUTTON_DPAD_LEFT:     {X: 60, Y: 115, W: 20, H: 20},
+	sdl.CONTROLLER_BUTTON_DPAD_RIGHT:    {X: 100, Y: 115, W: 20, H: 20},
+	sdl.CONTROLLER_BUTTON_A:             {X: 250, Y: 75, W: 20, H: 20},
+	sdl.CONTROLLER_BUTTON_B:             {X: 275, Y: 52, W: 20, H: 20},
+	sdl.CONTROLLER_BUTTON_X:             {X: 225, Y: 52, W: 20, H: 20},
+	sdl.CONTROLLER_BUTTON_Y:             {X: 250, Y: 29, W: 20, H: 20},
+	sdl.CONTROLLER_BUTTON_BACK:          {X: 125, Y: 50, W: 18, H: 10},
+	sdl.CONTROLLER_BUTTON_GUIDE:         {X: 151, Y: 46, W: 18, H: 18},
+	sdl.CONTROLLER_BUTTON_START:         {X: 177, Y: 50, W: 18, H: 10},
+}
+
+// stick describes where an analog stick is drawn in the virtual panel.
+type stick struct {
+	x, y   sdl.GameControllerAxis
+	button sdl.GameControllerButton
+	rect   sdl.Rect
+}
+
+var sticks = []stick{
+	{
+		x:      sdl.CONTROLLER_AXIS_LEFTX,
+		y:      sdl.CONTROLLER_AXIS_LEFTY,
+		button: sdl.CONTROLLER_BUTTON_LEFTSTICK,
+		rect:   sdl.Rect{X: 40, Y: 28, W: 60, H: 60},
+	},
+	{
+		x:      sdl.CONTROLLER_AXIS_RIGHTX,
+		y:      sdl.CONTROLLER_AXIS_RIGHTY,
+		button: sdl.CONTROLLER_BUTTON_RIGHTSTICK,
+		rect:   sdl.Rect{X: 190, Y: 95, W: 60, H: 60},
+	},
+}
+
+// triggers maps the trigger axes to their bars in the virtual panel.
+var triggers = map[sdl.GameControllerAxis]sdl.Rect{
+	sdl.CONTROLLER_AXIS_TRIGGERLEFT:  {X: 5, Y: 5, W: 10, H: 60},
+	sdl.CONTROLLER_AXIS_TRIGGERRIGHT: {X: 305, Y: 5, W: 10, H: 60},
+}
+
+// Scene shows the state of all connected game controllers. Create it with
+// New.
+type Scene struct {
+	controllers []*sdl.GameController
+}
+
+// New opens all game controllers that are connected right now. Controllers
+// that are connected later are added by HandleEvent.
+func New() *Scene {
+	s := &Scene{}
+	for i := 0; i < sdl.NumJoysticks(); i++ {
+		s.open(i)
+	}
+	return s
+}
+
+// Close closes all controllers that the scene opened.
+func (s *Scene) Close() {
+	for _, c := range s.controllers {
+		c.Close()
+	}
+	s.controllers = nil
+}
+
+// Controllers returns the controllers that are shown, in the order of their
+// panels.
+func (s *Scene) Controllers() []*sdl.GameController {
+	return append([]*sdl.GameController(nil), s.controllers...)
+}
+
+// HandleEvent adds and removes controllers when they are connected or
+// disconnected. It returns true for all controller events, i.e. if the scene
+// should be redrawn.
+func (s *Scene) HandleEvent(e sdl.Event) bool {
+	switch e := e.(type) {
+	case *sdl.ControllerDeviceEvent:
+		switch e.Type {
+		case sdl.CONTROLLERDEVICEADDED:
+			s.open(int(e.Which))
+		case sdl.CONTROLLERDEVICEREMOVED:
+			s.remove(e.Which)
+		}
+		return true
+	case *sdl.ControllerAxisEvent, *sdl.ControllerButtonEvent:
+		return true
+	}
+	return false
+}
+
+// open opens the controller at the device index unless it is already shown.
+func (s *Scene) open(index int) {
+	if !sdl.IsGameController(index) {
+		return
+	}
+	c := sdl.GameControllerOpen(index)
+	if c == nil {
+		return
+	}
+	id := c.Joystick().InstanceID()
+	for _, old := range s.controllers {
+		if old.Joystick().InstanceID() == id {
+			// Opening a controller again only increments its reference
+			// count, undo that.
+			c.Close()
+			return
+		}
+	}
+	s.controllers = append(s.controllers, c)
+}
+
+func (s *Scene) remove(id sdl.JoystickID) {
+	for i, c := range s.controllers {
+		if c.Joystick().InstanceID() == id {
+			c.Close()
+			s.controllers = append(s.controllers[:i], s.controllers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Draw draws one panel per controller into the area, dividing it evenly.
+// Nothing is drawn if no controller is connected. The renderer's draw state is
+// restored afterwards.
+func (s *Scene) Draw(r *sdl.Renderer, area sdl.Rect) error {
+	if len(s.controllers) == 0 || area.W <= 0 || area.H <= 0 {
+		return nil
+	}
+	if err := r.PushState(); err != nil {
+		return err
+	}
+	defer r.PopState()
+	if err := r.SetDrawBlendMode(sdl.BLENDMODE_NONE); err != nil {
+		return err
+	}
+
+	n := int32(len(s.controllers))
+	for i, c := range s.controllers {
+		y0 := area.Y + area.H*int32(i)/n
+		y1 := area.Y + area.H*int32(i+1)/n
+		p := panel{r: r, area: sdl.Rect{X: area.X, Y: y0, W: area.W, H: y1 - y0}}
+		if err := p.draw(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// panel draws a controller into an area of the renderer, scaling the virtual
+// panel layout to it.
+type panel struct {
+	r    *sdl.Renderer
+	area sdl.Rect
+	err  error
+}
+
+// controllerState is the part of *sdl.GameController that a panel reads.
+type controllerState interface {
+	Button(sdl.GameControllerButton) byte
+	Axis(sdl.GameControllerAxis) int16
+}
+
+func (p *panel) draw(c controllerState) error {
+	// Keep a small gap between the panels.
+	p.fill(sdl.Rect{X: 1, Y: 1, W: panelW - 2, H: panelH - 2}, backgroundColor)
+
+	for button, rect := range buttonRects {
+		p.button(rect, c.Button(button) != 0)
+	}
+
+	for _, s := range sticks {
+		p.button(s.rect, c.Button(s.button) != 0)
+		inner := sdl.Rect{X: s.rect.X + 2, Y: s.rect.Y + 2, W: s.rect.W - 4, H: s.rect.H - 4}
+		p.fill(inner, backgroundColor)
+		state := sdl.NewStickState(c.Axis(s.x), c.Axis(s.y), 0)
+		const dot = 8
+		cx := inner.X + inner.W/2 + int32(state.X*float32(inner.W-dot)/2)
+		cy := inner.Y + inner.H/2 + int32(state.Y*float32(inner.H-dot)/2)
+		p.fill(sdl.Rect{X: cx - dot/2, Y: cy - dot/2, W: dot, H: dot}, axisColor)
+	}
+
+	for axis, rect := range triggers {
+		p.outline(rect, outlineColor)
+		v := sdl.NormalizeAxis(c.Axis(axis), 0)
+		if v < 0 {
+			v = 0
+		}
+		h := int32(v * float32(rect.H))
+		p.fill(sdl.Rect{X: rect.X, Y: rect.Y + rect.H - h, W: rect.W, H: h}, axisColor)
+	}
+	return p.err
+}
+
+// button draws a button outline that is filled while it is pressed.
+func (p *panel) button(rect sdl.Rect, pressed bool) {
+	if pressed {
+		p.fill(rect, pressedColor)
+	} else {
+		p.outline(rect, outlineColor)
+	}
+}
+
+func (p *panel) fill(rect sdl.Rect, c sdl.Color) {
+	p.rect(rect, c, p.r.FillRect)
+}
+
+func (p *panel) outline(rect sdl.Rect, c sdl.Color) {
+	p.rect(rect, c, p.r.DrawRect)
+}
+
+// rect scales the virtual rect to the panel and draws it. The first error is
+// kept in p.err and further drawing is skipped.
+func (p *panel) rect(rect sdl.Rect, c sdl.Color, draw func(*sdl.Rect) error) {
+	if p.err != nil || rect.W <= 0 || rect.H <= 0 {
+		return
+	}
+	x0 := p.area.X + rect.X*p.area.W/panelW
+	y0 := p.area.Y + rect.Y*p.area.H/panelH
+	x1 := p.area.X + (rect.X+rect.W)*p.area.W/panelW
+	y1 := p.area.Y + (rect.Y+rect.H)*p.area.H/panelH
+	if err := p.r.SetDrawColor(c.R, c.G, c.B, c.A); err != nil {
+		p.err = err
+		return
+	}
+	p.err = draw(&sdl.Rect{X: x0, Y: y0, W: x1 - x0, H: y1 - y0})
+}
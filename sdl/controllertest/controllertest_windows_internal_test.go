@@ -0,0 +1,113 @@
+//+build windows
+
+package controllertest
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+
+	"github.com/gonutz/check"
+	"github.com/gonutz/go-sdl2/sdl"
+)
+
+func test(f func()) {
+	sdl.Main(func() {
+		sdl.Do(f)
+	})
+}
+
+type fakeController struct {
+	buttons map[sdl.GameControllerButton]byte
+	axes    map[sdl.GameControllerAxis]int16
+}
+
+func (c fakeController) Button(b sdl.GameControllerButton) byte {
+	return c.buttons[b]
+}
+
+func (c fakeController) Axis(a sdl.GameControllerAxis) int16 {
+	return c.axes[a]
+}
+
+func TestPanelRectIsScaledToArea(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(8, 8)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+
+		// The area is twice as wide and half as high as the virtual panel.
+		p := panel{r: renderer, area: sdl.Rect{X: 10, Y: 20, W: 2 * panelW, H: panelH / 2}}
+		var drawn []sdl.Rect
+		draw := func(r *sdl.Rect) error {
+			drawn = append(drawn, *r)
+			return nil
+		}
+		p.rect(sdl.Rect{X: 20, Y: 10, W: 60, H: 12}, outlineColor, draw)
+		p.rect(sdl.Rect{X: 0, Y: 0, W: panelW, H: panelH}, outlineColor, draw)
+		p.rect(sdl.Rect{X: 5, Y: 5, W: 0, H: 10}, outlineColor, draw) // empty
+		check.Eq(t, drawn, []sdl.Rect{
+			{X: 50, Y: 25, W: 120, H: 6},
+			{X: 10, Y: 20, W: 2 * panelW, H: panelH / 2},
+		})
+		check.Eq(t, p.err, nil)
+
+		// After an error nothing is drawn anymore.
+		failure := errors.New("draw failed")
+		p.rect(sdl.Rect{X: 1, Y: 1, W: 1, H: 1}, outlineColor, func(*sdl.Rect) error {
+			return failure
+		})
+		p.rect(sdl.Rect{X: 1, Y: 1, W: 1, H: 1}, outlineColor, draw)
+		check.Eq(t, p.err, failure)
+		check.Eq(t, len(drawn), 2)
+	})
+}
+
+func TestPanelDrawsControllerState(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(panelW, panelH)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+		check.Eq(t, renderer.SetDrawColor(0, 0, 0, 255), nil)
+		check.Eq(t, renderer.Clear(), nil)
+
+		c := fakeController{
+			buttons: map[sdl.GameControllerButton]byte{sdl.CONTROLLER_BUTTON_A: 1},
+			axes: map[sdl.GameControllerAxis]int16{
+				sdl.CONTROLLER_AXIS_TRIGGERLEFT: 32767,
+				sdl.CONTROLLER_AXIS_LEFTX:       32767,
+			},
+		}
+		p := panel{r: renderer, area: sdl.Rect{X: 0, Y: 0, W: panelW, H: panelH}}
+		check.Eq(t, p.draw(c), nil)
+		renderer.Present()
+		img, err := surface.ToRGBA()
+		check.Eq(t, err, nil)
+
+		rgba := func(c sdl.Color) color.RGBA {
+			return color.RGBA{R: c.R, G: c.G, B: c.B, A: c.A}
+		}
+		pixels := []struct {
+			x, y int
+			want sdl.Color
+		}{
+			{0, 0, sdl.Color{A: 255}},  // the gap around the panel
+			{2, 2, backgroundColor},    // the panel background
+			{260, 85, pressedColor},    // A is pressed
+			{285, 62, backgroundColor}, // B is not pressed
+			{275, 52, outlineColor},    // but has an outline
+			{10, 35, axisColor},        // the left trigger is pulled all the way
+			{310, 35, backgroundColor}, // the right trigger is released
+			{93, 57, axisColor},        // the left stick points right
+			{69, 57, backgroundColor},  // so its center is empty
+			{219, 124, axisColor},      // the right stick is centered
+		}
+		for _, p := range pixels {
+			if got := img.RGBAAt(p.x, p.y); got != rgba(p.want) {
+				t.Errorf("pixel %d,%d is %v but should be %v", p.x, p.y, got, rgba(p.want))
+			}
+		}
+	})
+}
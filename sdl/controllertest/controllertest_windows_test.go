@@ -0,0 +1,63 @@
+//+build windows
+
+package controllertest_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/gonutz/check"
+	"github.com/gonutz/go-sdl2/sdl"
+	"github.com/gonutz/go-sdl2/sdl/controllertest"
+)
+
+func test(f func()) {
+	sdl.Main(func() {
+		sdl.Do(f)
+	})
+}
+
+func TestSceneWithoutControllersDrawsNothing(t *testing.T) {
+	test(func() {
+		// The game controller subsystem is not initialized so there are no
+		// controllers.
+		scene := controllertest.New()
+		defer scene.Close()
+		check.Eq(t, len(scene.Controllers()), 0)
+
+		renderer, surface, err := sdl.NewSoftwareRenderer(32, 32)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+		check.Eq(t, renderer.SetDrawColor(1, 2, 3, 255), nil)
+		check.Eq(t, renderer.Clear(), nil)
+
+		check.Eq(t, scene.Draw(renderer, sdl.Rect{X: 0, Y: 0, W: 32, H: 32}), nil)
+		renderer.Present()
+
+		img, err := surface.ToRGBA()
+		check.Eq(t, err, nil)
+		for y := 0; y < 32; y++ {
+			for x := 0; x < 32; x++ {
+				if img.RGBAAt(x, y) != (color.RGBA{R: 1, G: 2, B: 3, A: 255}) {
+					t.Errorf("pixel %d,%d was drawn: %v", x, y, img.RGBAAt(x, y))
+					return
+				}
+			}
+		}
+	})
+}
+
+func TestRemovingUnknownControllerIsIgnored(t *testing.T) {
+	test(func() {
+		scene := controllertest.New()
+		defer scene.Close()
+
+		handled := scene.HandleEvent(&sdl.ControllerDeviceEvent{
+			Type:  sdl.CONTROLLERDEVICEREMOVED,
+			Which: 12345,
+		})
+		check.Eq(t, handled, true)
+		check.Eq(t, len(scene.Controllers()), 0)
+	})
+}
@@ -0,0 +1,321 @@
+//+build windows
+
+/*
+Package debugui draws simple debug overlays on top of a game: text in a built-in
+8x8 pixel font, a graph of recent frame times and a list of watched variables.
+SDL itself cannot draw text, this package needs nothing but a Renderer.
+
+	overlay, err := debugui.New(renderer)
+	if err != nil {
+		return err
+	}
+	defer overlay.Destroy()
+	last := time.Now()
+	for running {
+		now := time.Now()
+		overlay.AddFrameTime(now.Sub(last))
+		last = now
+		overlay.Watch("player", player.Pos)
+
+		// draw the game
+
+		overlay.DrawText(10, 10, "debug mode", sdl.Color{R: 255, G: 255, B: 0, A: 255})
+		overlay.DrawFrameTimeGraph(sdl.Rect{X: 10, Y: 30, W: 240, H: 60}, 50*time.Millisecond)
+		overlay.DrawWatches(10, 100)
+		renderer.Present()
+	}
+
+The font only contains the printable ASCII characters, all others are drawn as
+'?'.
+*/
+package debugui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gonutz/go-sdl2/sdl"
+)
+
+// CharSize is the width and height of a character in pixels, at scale 1.
+const CharSize = 8
+
+// maxFrameTimes is the number of frame times that the graph remembers.
+const maxFrameTimes = 240
+
+var (
+	// White is the default text color.
+	White = sdl.Color{R: 255, G: 255, B: 255, A: 255}
+
+	backgroundColor = sdl.Color{R: 0, G: 0, B: 0, A: 160}
+	goodFrameColor  = sdl.Color{R: 80, G: 220, B: 100, A: 255}
+	slowFrameColor  = sdl.Color{R: 240, G: 200, B: 60, A: 255}
+	badFrameColor   = sdl.Color{R: 240, G: 70, B: 60, A: 255}
+	targetLineColor = sdl.Color{R: 255, G: 255, B: 255, A: 128}
+)
+
+// Overlay draws debug information with a renderer. Create it with New.
+type Overlay struct {
+	// Scale is the integer factor by which text is enlarged, e.g. 2 for high
+	// DPI screens. Values below 1 are treated as 1.
+	Scale int32
+	// TargetFrameTime is marked with a line in the frame time graph. Frames
+	// up to twice as long are drawn in yellow, longer frames in red. It
+	// defaults to 60 frames per second.
+	TargetFrameTime time.Duration
+
+	renderer   *sdl.Renderer
+	font       *sdl.Texture
+	frameTimes []time.Duration // a ring buffer
+	nextFrame  int             // the index in frameTimes for the next frame
+	watches    []watch
+}
+
+type watch struct {
+	name  string
+	value interface{}
+}
+
+// New creates the font texture for the renderer.
+func New(renderer *sdl.Renderer) (*Overlay, error) {
+	const w, h = len(font8x8) * CharSize, CharSize
+	font, err := renderer.CreateTexture(
+		sdl.PIXELFORMAT_ARGB8888, sdl.TEXTUREACCESS_STATIC, int32(w), int32(h),
+	)
+	if err != nil {
+		return nil, err
+	}
+	pixels := make([]uint32, w*h)
+	for i, glyph := range font8x8 {
+		for y, row := range glyph {
+			for x := 0; x < CharSize; x++ {
+				if row&(0x80>>uint(x)) != 0 {
+					pixels[y*w+i*CharSize+x] = 0xFFFFFFFF
+				}
+			}
+		}
+	}
+	if err := font.UpdateRGBA(nil, pixels, w); err != nil {
+		font.Destroy()
+		return nil, err
+	}
+	if err := font.SetBlendMode(sdl.BLENDMODE_BLEND); err != nil {
+		font.Destroy()
+		return nil, err
+	}
+	return &Overlay{
+		Scale:           1,
+		TargetFrameTime: time.Second / 60,
+		renderer:        renderer,
+		font:            font,
+	}, nil
+}
+
+// AddFrameTime appends the duration of a frame to the frame time graph. Call
+// it once per frame.
+func (o *Overlay) AddFrameTime(d time.Duration) {
+	if len(o.frameTimes) < maxFrameTimes {
+		o.frameTimes = append(o.frameTimes, d)
+	} else {
+		o.frameTimes[o.nextFrame] = d
+	}
+	o.nextFrame = (o.nextFrame + 1) % maxFrameTimes
+}
+
+// Destroy destroys the font texture. The overlay cannot be used afterwards.
+func (o *Overlay) Destroy() {
+	if o.font != nil {
+		o.font.Destroy()
+		o.font = nil
+	}
+}
+
+// DrawFrameTimeGraph draws the recent frame times as bars, the newest at the
+// right. Bars are scaled so that max fills the whole height, longer frames are
+// cut off.
+func (o *Overlay) DrawFrameTimeGraph(area sdl.Rect, max time.Duration) error {
+	if area.W <= 0 || area.H <= 0 || max <= 0 {
+		return nil
+	}
+	return o.withState(func(r *sdl.Renderer) error {
+		if err := fillRect(r, area, backgroundColor); err != nil {
+			return err
+		}
+		barW := area.W / maxFrameTimes
+		if barW < 1 {
+			barW = 1
+		}
+		count := len(o.frameTimes)
+		if visible := int(area.W / barW); count > visible {
+			count = visible
+		}
+		for i := 0; i < count; i++ {
+			// Go back in time from the newest frame.
+			index := (o.nextFrame - 1 - i + 2*maxFrameTimes) % maxFrameTimes
+			d := o.frameTimes[index]
+			if d > max {
+				d = max
+			}
+			h := int32(int64(area.H) * int64(d) / int64(max))
+			bar := sdl.Rect{
+				X: area.X + area.W - int32(i+1)*barW,
+				Y: area.Y + area.H - h,
+				W: barW,
+				H: h,
+			}
+			if err := fillRect(r, bar, o.frameColor(o.frameTimes[index])); err != nil {
+				return err
+			}
+		}
+		if o.TargetFrameTime > 0 && o.TargetFrameTime <= max {
+			y := area.Y + area.H - int32(int64(area.H)*int64(o.TargetFrameTime)/int64(max))
+			line := sdl.Rect{X: area.X, Y: y, W: area.W, H: 1}
+			if err := fillRect(r, line, targetLineColor); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (o *Overlay) frameColor(d time.Duration) sdl.Color {
+	switch {
+	case o.TargetFrameTime <= 0 || d <= o.TargetFrameTime:
+		return goodFrameColor
+	case d <= 2*o.TargetFrameTime:
+		return slowFrameColor
+	default:
+		return badFrameColor
+	}
+}
+
+// DrawText draws the text with its top-left corner at x, y. Line breaks start
+// a new line below x.
+func (o *Overlay) DrawText(x, y int32, text string, c sdl.Color) error {
+	if err := o.font.SetColorMod(c.R, c.G, c.B); err != nil {
+		return err
+	}
+	if err := o.font.SetAlphaMod(c.A); err != nil {
+		return err
+	}
+	size := CharSize * o.scale()
+	startX := x
+	for _, r := range text {
+		if r == '\n' {
+			x = startX
+			y += size
+			continue
+		}
+		if r != ' ' {
+			src := sdl.Rect{X: glyphIndex(r) * CharSize, Y: 0, W: CharSize, H: CharSize}
+			dst := sdl.Rect{X: x, Y: y, W: size, H: size}
+			if err := o.renderer.Copy(o.font, &src, &dst); err != nil {
+				return err
+			}
+		}
+		x += size
+	}
+	return nil
+}
+
+// glyphIndex returns the index of the character in font8x8.
+func glyphIndex(r rune) int32 {
+	if r < ' ' || r > '~' {
+		r = '?'
+	}
+	return int32(r - ' ')
+}
+
+// DrawWatches draws the watched variables as "name: value" lines, in the
+// order they were first watched, on a dark background for readability.
+func (o *Overlay) DrawWatches(x, y int32) error {
+	if len(o.watches) == 0 {
+		return nil
+	}
+	var text string
+	for i, w := range o.watches {
+		if i > 0 {
+			text += "\n"
+		}
+		text += fmt.Sprintf("%s: %v", w.name, w.value)
+	}
+	w, h := o.TextSize(text)
+	padding := 2 * o.scale()
+	background := sdl.Rect{X: x, Y: y, W: w + 2*padding, H: h + 2*padding}
+	err := o.withState(func(r *sdl.Renderer) error {
+		return fillRect(r, background, backgroundColor)
+	})
+	if err != nil {
+		return err
+	}
+	return o.DrawText(x+padding, y+padding, text, White)
+}
+
+// TextSize returns the size in pixels that DrawText needs for the text.
+func (o *Overlay) TextSize(text string) (w, h int32) {
+	var lineLen, maxLen, lines int32 = 0, 0, 1
+	for _, r := range text {
+		if r == '\n' {
+			lines++
+			lineLen = 0
+			continue
+		}
+		lineLen++
+		if lineLen > maxLen {
+			maxLen = lineLen
+		}
+	}
+	size := CharSize * o.scale()
+	return maxLen * size, lines * size
+}
+
+// Unwatch removes the variable from the watch list.
+func (o *Overlay) Unwatch(name string) {
+	for i, w := range o.watches {
+		if w.name == name {
+			o.watches = append(o.watches[:i], o.watches[i+1:]...)
+			return
+		}
+	}
+}
+
+// Watch sets the value shown for the variable by DrawWatches. New names are
+// appended to the list, known names are updated in place. Values are
+// formatted with fmt's %v.
+func (o *Overlay) Watch(name string, value interface{}) {
+	for i := range o.watches {
+		if o.watches[i].name == name {
+			o.watches[i].value = value
+			return
+		}
+	}
+	o.watches = append(o.watches, watch{name: name, value: value})
+}
+
+func (o *Overlay) scale() int32 {
+	if o.Scale < 1 {
+		return 1
+	}
+	return o.Scale
+}
+
+// withState calls draw with blending enabled and restores the renderer's draw
+// state afterwards.
+func (o *Overlay) withState(draw func(r *sdl.Renderer) error) error {
+	r := o.renderer
+	if err := r.PushState(); err != nil {
+		return err
+	}
+	defer r.PopState()
+	if err := r.SetDrawBlendMode(sdl.BLENDMODE_BLEND); err != nil {
+		return err
+	}
+	return draw(r)
+}
+
+func fillRect(r *sdl.Renderer, rect sdl.Rect, c sdl.Color) error {
+	if err := r.SetDrawColor(c.R, c.G, c.B, c.A); err != nil {
+		return err
+	}
+	return r.FillRect(&rect)
+}
@@ -0,0 +1,90 @@
+//+build windows
+
+package debugui
+
+import (
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/gonutz/check"
+	"github.com/gonutz/go-sdl2/sdl"
+)
+
+func test(f func()) {
+	sdl.Main(func() {
+		sdl.Do(f)
+	})
+}
+
+func TestTextSizeCountsTheLongestLineAndAllLines(t *testing.T) {
+	o := &Overlay{Scale: 1}
+	check.Eq(t, sizeOf(o, ""), [2]int32{0, 8})
+	check.Eq(t, sizeOf(o, "abc"), [2]int32{24, 8})
+	check.Eq(t, sizeOf(o, "a\nbcd\n"), [2]int32{24, 24})
+	check.Eq(t, sizeOf(o, "ä"), [2]int32{8, 8})
+
+	o.Scale = 2
+	check.Eq(t, sizeOf(o, "ab\nc"), [2]int32{32, 32})
+
+	o.Scale = 0
+	check.Eq(t, sizeOf(o, "ab"), [2]int32{16, 8})
+}
+
+func sizeOf(o *Overlay, text string) [2]int32 {
+	w, h := o.TextSize(text)
+	return [2]int32{w, h}
+}
+
+func TestGlyphIndexFallsBackToQuestionMark(t *testing.T) {
+	check.Eq(t, glyphIndex(' '), int32(0))
+	check.Eq(t, glyphIndex('A'), int32('A'-' '))
+	check.Eq(t, glyphIndex('~'), int32(len(font8x8)-1))
+	check.Eq(t, glyphIndex('\t'), int32('?'-' '))
+	check.Eq(t, glyphIndex(127), int32('?'-' '))
+	check.Eq(t, glyphIndex('ä'), int32('?'-' '))
+}
+
+func TestFrameTimesWrapAroundAfterMaxFrameTimes(t *testing.T) {
+	var o Overlay
+	for i := 0; i < maxFrameTimes+10; i++ {
+		o.AddFrameTime(time.Duration(i))
+	}
+	check.Eq(t, len(o.frameTimes), maxFrameTimes)
+	check.Eq(t, o.nextFrame, 10)
+	// The oldest frames were overwritten by the newest ones.
+	check.Eq(t, o.frameTimes[0], time.Duration(maxFrameTimes))
+	check.Eq(t, o.frameTimes[9], time.Duration(maxFrameTimes+9))
+	check.Eq(t, o.frameTimes[10], time.Duration(10))
+	check.Eq(t, o.frameTimes[maxFrameTimes-1], time.Duration(maxFrameTimes-1))
+}
+
+func TestDrawTextSetsGlyphPixels(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(16, 8)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+		check.Eq(t, renderer.SetDrawColor(0, 0, 0, 255), nil)
+		check.Eq(t, renderer.Clear(), nil)
+
+		o, err := New(renderer)
+		check.Eq(t, err, nil)
+		defer o.Destroy()
+		check.Eq(t, o.DrawText(0, 0, "A", White), nil)
+		renderer.Present()
+
+		img, err := surface.ToRGBA()
+		check.Eq(t, err, nil)
+		white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+		black := color.RGBA{A: 255}
+		// The top row of 'A' is 0x38, the fifth row 0x7C.
+		check.Eq(t, img.RGBAAt(0, 0), black)
+		check.Eq(t, img.RGBAAt(2, 0), white)
+		check.Eq(t, img.RGBAAt(4, 0), white)
+		check.Eq(t, img.RGBAAt(5, 0), black)
+		check.Eq(t, img.RGBAAt(1, 4), white)
+		// Nothing is drawn right of the character.
+		check.Eq(t, img.RGBAAt(12, 4), black)
+	})
+}
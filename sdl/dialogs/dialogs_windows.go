@@ -0,0 +1,273 @@
+//+build windows
+
+/*
+Package dialogs shows the native Windows dialogs for opening and saving files
+and for selecting a folder. SDL itself does not provide these.
+
+The dialogs are modal and block until the user closes them. Like all window
+functions, call them from the main thread, e.g. inside sdl.Do.
+*/
+package dialogs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/gonutz/go-sdl2/sdl"
+)
+
+var (
+	comdlg32 = syscall.NewLazyDLL("comdlg32.dll")
+	shell32  = syscall.NewLazyDLL("shell32.dll")
+	ole32    = syscall.NewLazyDLL("ole32.dll")
+
+	getOpenFileName      = comdlg32.NewProc("GetOpenFileNameW")
+	getSaveFileName      = comdlg32.NewProc("GetSaveFileNameW")
+	commDlgExtendedError = comdlg32.NewProc("CommDlgExtendedError")
+	shBrowseForFolder    = shell32.NewProc("SHBrowseForFolderW")
+	shGetPathFromIDList  = shell32.NewProc("SHGetPathFromIDListW")
+	coInitializeEx       = ole32.NewProc("CoInitializeEx")
+	coUninitialize       = ole32.NewProc("CoUninitialize")
+	coTaskMemFree        = ole32.NewProc("CoTaskMemFree")
+)
+
+const (
+	maxPath               = 260
+	maxFileNameBufferSize = 32768
+
+	ofnOverwritePrompt  = 0x00000002
+	ofnNoChangeDir      = 0x00000008
+	ofnPathMustExist    = 0x00000800
+	ofnFileMustExist    = 0x00001000
+	ofnExplorer         = 0x00080000
+	bifReturnOnlyFSDirs = 0x00000001
+
+	coinitApartmentThreaded = 0x2
+	sFalse                  = 1
+	rpcEChangedMode         = 0x80010106
+)
+
+// Filter restricts the files shown in a file dialog, e.g.
+//
+//	dialogs.Filter{Name: "Images", Patterns: []string{"*.png", "*.bmp"}}
+type Filter struct {
+	Name     string   // the text shown in the file type list
+	Patterns []string // wildcard patterns like "*.txt"
+}
+
+// openFileName is the OPENFILENAMEW structure.
+type openFileName struct {
+	structSize    uint32
+	owner         uintptr
+	instance      uintptr
+	filter        *uint16
+	customFilter  *uint16
+	maxCustFilter uint32
+	filterIndex   uint32
+	file          *uint16
+	maxFile       uint32
+	fileTitle     *uint16
+	maxFileTitle  uint32
+	initialDir    *uint16
+	title         *uint16
+	flags         uint32
+	fileOffset    uint16
+	fileExtension uint16
+	defExt        *uint16
+	custData      uintptr
+	hook          uintptr
+	templateName  *uint16
+	reserved      uintptr
+	reserved2     uint32
+	flagsEx       uint32
+}
+
+// browseInfo is the BROWSEINFOW structure.
+type browseInfo struct {
+	owner       uintptr
+	root        uintptr
+	displayName *uint16
+	title       *uint16
+	flags       uint32
+	callback    uintptr
+	param       uintptr
+	image       int32
+}
+
+// OpenFileDialog lets the user select an existing file. The parent window
+// may be nil. If the user cancels the dialog, ok is false and err is nil. The
+// title and filters must not contain NUL characters.
+func OpenFileDialog(parent *sdl.Window, title string, filters ...Filter) (path string, ok bool, err error) {
+	return fileDialog(
+		getOpenFileName,
+		parent, title, "", filters,
+		ofnFileMustExist|ofnPathMustExist|ofnNoChangeDir|ofnExplorer,
+	)
+}
+
+// SaveFileDialog lets the user select a file name to save to. The dialog
+// starts with defaultName, which may be empty, and asks before overwriting an
+// existing file. The parent window may be nil. If the user cancels the
+// dialog, ok is false and err is nil. The strings must not contain NUL
+// characters.
+func SaveFileDialog(parent *sdl.Window, title, defaultName string, filters ...Filter) (path string, ok bool, err error) {
+	return fileDialog(
+		getSaveFileName,
+		parent, title, defaultName, filters,
+		ofnOverwritePrompt|ofnPathMustExist|ofnNoChangeDir|ofnExplorer,
+	)
+}
+
+// SelectFolder lets the user select a folder. The parent window may be nil.
+// If the user cancels the dialog, ok is false and err is nil. The title must
+// not contain NUL characters.
+func SelectFolder(parent *sdl.Window, title string) (path string, ok bool, err error) {
+	owner, err := windowHandle(parent)
+	if err != nil {
+		return "", false, err
+	}
+	titlePtr, err := utf16Ptr(title)
+	if err != nil {
+		return "", false, err
+	}
+	// SHBrowseForFolder needs COM in a single-threaded apartment.
+	uninit, err := initCOM()
+	if err != nil {
+		return "", false, err
+	}
+	defer uninit()
+
+	displayName := make([]uint16, maxPath)
+	info := browseInfo{
+		owner:       owner,
+		displayName: &displayName[0],
+		title:       titlePtr,
+		flags:       bifReturnOnlyFSDirs,
+	}
+	idList, _, _ := shBrowseForFolder.Call(uintptr(unsafe.Pointer(&info)))
+	if idList == 0 {
+		return "", false, nil
+	}
+	defer coTaskMemFree.Call(idList)
+
+	buf := make([]uint16, maxFileNameBufferSize)
+	ret, _, _ := shGetPathFromIDList.Call(idList, uintptr(unsafe.Pointer(&buf[0])))
+	if ret == 0 {
+		return "", false, errors.New("dialogs: the selected item is not a file system folder")
+	}
+	return syscall.UTF16ToString(buf), true, nil
+}
+
+func fileDialog(
+	show *syscall.LazyProc,
+	parent *sdl.Window,
+	title, defaultName string,
+	filters []Filter,
+	flags uint32,
+) (string, bool, error) {
+	owner, err := windowHandle(parent)
+	if err != nil {
+		return "", false, err
+	}
+	name, err := syscall.UTF16FromString(defaultName)
+	if err != nil {
+		return "", false, fmt.Errorf("dialogs: invalid default file name: %v", err)
+	}
+	if len(name) > maxFileNameBufferSize {
+		return "", false, fmt.Errorf(
+			"dialogs: the default file name is longer than %d characters",
+			maxFileNameBufferSize-1,
+		)
+	}
+	titlePtr, err := utf16Ptr(title)
+	if err != nil {
+		return "", false, err
+	}
+	file := make([]uint16, maxFileNameBufferSize)
+	copy(file, name)
+	ofn := openFileName{
+		owner:   owner,
+		file:    &file[0],
+		maxFile: uint32(len(file)),
+		title:   titlePtr,
+		flags:   flags,
+	}
+	ofn.structSize = uint32(unsafe.Sizeof(ofn))
+	if len(filters) > 0 {
+		filter, err := filterString(filters)
+		if err != nil {
+			return "", false, err
+		}
+		ofn.filter = &filter[0]
+		ofn.filterIndex = 1
+	}
+
+	ret, _, _ := show.Call(uintptr(unsafe.Pointer(&ofn)))
+	if ret == 0 {
+		code, _, _ := commDlgExtendedError.Call()
+		if code == 0 {
+			return "", false, nil // the user cancelled the dialog
+		}
+		return "", false, fmt.Errorf("dialogs: common dialog error 0x%X", code)
+	}
+	return syscall.UTF16ToString(file), true, nil
+}
+
+// filterString encodes the filters as pairs of zero-terminated strings,
+// followed by an extra zero.
+func filterString(filters []Filter) ([]uint16, error) {
+	var s []uint16
+	for _, f := range filters {
+		for _, part := range []string{f.Name, strings.Join(f.Patterns, ";")} {
+			u, err := syscall.UTF16FromString(part)
+			if err != nil {
+				return nil, fmt.Errorf("dialogs: invalid filter %q: %v", f.Name, err)
+			}
+			s = append(s, u...)
+		}
+	}
+	return append(s, 0), nil
+}
+
+// initCOM initializes COM as a single-threaded apartment on the calling
+// thread. If COM was already initialized, even in another mode, this is not
+// an error. Call the returned function when done.
+func initCOM() (uninit func(), err error) {
+	ret, _, _ := coInitializeEx.Call(0, coinitApartmentThreaded)
+	switch uint32(ret) {
+	case 0, sFalse:
+		// Every successful call must be balanced, even if COM was already
+		// initialized.
+		return func() { coUninitialize.Call() }, nil
+	case rpcEChangedMode:
+		// COM is initialized as multi-threaded on this thread, use it as is.
+		return func() {}, nil
+	}
+	return nil, fmt.Errorf("dialogs: cannot initialize COM: HRESULT 0x%X", uint32(ret))
+}
+
+func windowHandle(window *sdl.Window) (uintptr, error) {
+	if window == nil {
+		return 0, nil
+	}
+	info, err := window.GetWMInfo()
+	if err != nil {
+		return 0, err
+	}
+	return uintptr(info.GetWindowsInfo().Window), nil
+}
+
+// utf16Ptr returns nil for empty strings so the dialog uses its default.
+func utf16Ptr(s string) (*uint16, error) {
+	if s == "" {
+		return nil, nil
+	}
+	p, err := syscall.UTF16PtrFromString(s)
+	if err != nil {
+		return nil, fmt.Errorf("dialogs: invalid text %q: %v", s, err)
+	}
+	return p, nil
+}
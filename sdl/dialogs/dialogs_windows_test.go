@@ -0,0 +1,45 @@
+//+build windows
+
+package dialogs
+
+import (
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/gonutz/check"
+)
+
+func TestFilterStringEncodesZeroTerminatedPairs(t *testing.T) {
+	s, err := filterString([]Filter{
+		{Name: "Images", Patterns: []string{"*.png", "*.bmp"}},
+		{Name: "All", Patterns: []string{"*.*"}},
+	})
+	check.Eq(t, err, nil)
+	want := syscall.StringToUTF16("Images")
+	want = append(want, syscall.StringToUTF16("*.png;*.bmp")...)
+	want = append(want, syscall.StringToUTF16("All")...)
+	want = append(want, syscall.StringToUTF16("*.*")...)
+	want = append(want, 0)
+	check.Eq(t, s, want)
+}
+
+func TestFilterStringRejectsNUL(t *testing.T) {
+	_, err := filterString([]Filter{{Name: "a\x00b", Patterns: []string{"*.a"}}})
+	check.Neq(t, err, nil)
+	_, err = filterString([]Filter{{Name: "a", Patterns: []string{"*.a\x00"}}})
+	check.Neq(t, err, nil)
+}
+
+func TestUTF16PtrRejectsNUL(t *testing.T) {
+	p, err := utf16Ptr("")
+	check.Eq(t, err, nil)
+	check.Eq(t, p == nil, true)
+
+	p, err = utf16Ptr("Title")
+	check.Eq(t, err, nil)
+	check.Eq(t, syscall.UTF16ToString((*[6]uint16)(unsafe.Pointer(p))[:]), "Title")
+
+	_, err = utf16Ptr("Ti\x00tle")
+	check.Neq(t, err, nil)
+}
@@ -0,0 +1,213 @@
+//+build windows
+
+/*
+Package eventrec records SDL events and plays them back later, which makes it
+possible to write regression tests for game logic that depends on user input.
+
+While playing, record the events as they come in:
+
+	rec := eventrec.NewRecorder(file)
+	for e := sdl.PollEvent(); e != nil; e = sdl.PollEvent() {
+		rec.Record(e)
+		// handle the event
+	}
+
+To replay them, push them back into the event queue at the times they were
+recorded, relative to the start of the recording:
+
+	player, err := eventrec.NewPlayer(file)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	for !player.Done() {
+		if _, err := player.PushUntil(time.Since(start)); err != nil {
+			return err
+		}
+		for e := sdl.PollEvent(); e != nil; e = sdl.PollEvent() {
+			// handle the event
+		}
+	}
+
+For deterministic tests, record with RecordAt and replay with PushUntil using
+the simulated game time instead of the wall clock.
+
+The events are stored as one line of JSON each. Events that contain pointers,
+i.e. DropEvent, SysWMEvent and UserEvent, cannot be pushed back into the event
+queue and are not recorded.
+*/
+package eventrec
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/gonutz/go-sdl2/sdl"
+)
+
+// record is one line in the recording.
+type record struct {
+	Time  time.Duration   `json:"time"`  // since the start of the recording
+	Type  string          `json:"type"`  // the Go type name, e.g. "KeyboardEvent"
+	Event json.RawMessage `json:"event"` // the event struct
+}
+
+// eventTypes are the events that can be recorded, by their type names.
+var eventTypes = typesByName(
+	&sdl.AudioDeviceEvent{},
+	&sdl.ClipboardEvent{},
+	&sdl.CommonEvent{},
+	&sdl.ControllerAxisEvent{},
+	&sdl.ControllerButtonEvent{},
+	&sdl.ControllerDeviceEvent{},
+	&sdl.DollarGestureEvent{},
+	&sdl.JoyAxisEvent{},
+	&sdl.JoyBallEvent{},
+	&sdl.JoyBatteryEvent{},
+	&sdl.JoyButtonEvent{},
+	&sdl.JoyDeviceAddedEvent{},
+	&sdl.JoyDeviceRemovedEvent{},
+	&sdl.JoyHatEvent{},
+	&sdl.KeyboardEvent{},
+	&sdl.MouseButtonEvent{},
+	&sdl.MouseMotionEvent{},
+	&sdl.MouseWheelEvent{},
+	&sdl.MultiGestureEvent{},
+	&sdl.QuitEvent{},
+	&sdl.RenderEvent{},
+	&sdl.SensorEvent{},
+	&sdl.TextEditingEvent{},
+	&sdl.TextInputEvent{},
+	&sdl.TouchFingerEvent{},
+	&sdl.WindowEvent{},
+)
+
+func typesByName(events ...sdl.Event) map[string]reflect.Type {
+	types := make(map[string]reflect.Type)
+	for _, e := range events {
+		t := reflect.TypeOf(e).Elem()
+		types[t.Name()] = t
+	}
+	return types
+}
+
+// Recorder writes events to a stream.
+type Recorder struct {
+	enc   *json.Encoder
+	start time.Time
+}
+
+// NewRecorder creates a Recorder writing to w. The times of the events
+// recorded with Record are relative to the call to NewRecorder.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w), start: time.Now()}
+}
+
+// Record writes the event with the time that passed since the Recorder was
+// created. Events that cannot be replayed are skipped and nil is returned.
+func (r *Recorder) Record(e sdl.Event) error {
+	return r.RecordAt(time.Since(r.start), e)
+}
+
+// RecordAt writes the event with the given time since the start of the
+// recording. Use it to record events with a simulated clock. Events that
+// cannot be replayed are skipped and nil is returned.
+func (r *Recorder) RecordAt(t time.Duration, e sdl.Event) error {
+	v := reflect.ValueOf(e)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+	name := v.Elem().Type().Name()
+	if eventTypes[name] != v.Elem().Type() {
+		return nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return r.enc.Encode(record{Time: t, Type: name, Event: data})
+}
+
+// Player pushes recorded events back into the SDL event queue.
+type Player struct {
+	records []record
+	next    int
+}
+
+// NewPlayer reads a whole recording.
+func NewPlayer(r io.Reader) (*Player, error) {
+	var p Player
+	scanner := bufio.NewScanner(r)
+	// Lines are short but the default limit should not be what breaks a
+	// recording.
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("eventrec: line %d: %v", line, err)
+		}
+		if _, ok := eventTypes[rec.Type]; !ok {
+			return nil, fmt.Errorf("eventrec: line %d: unknown event type %q", line, rec.Type)
+		}
+		p.records = append(p.records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Done returns true once all events were pushed.
+func (p *Player) Done() bool {
+	return p.next >= len(p.records)
+}
+
+// PushUntil pushes all events recorded up to and including time t that were
+// not pushed yet. It returns the number of events pushed.
+func (p *Player) PushUntil(t time.Duration) (int, error) {
+	n := 0
+	for !p.Done() && p.records[p.next].Time <= t {
+		if err := push(p.records[p.next]); err != nil {
+			return n, err
+		}
+		p.next++
+		n++
+	}
+	return n, nil
+}
+
+// PushAll pushes all remaining events, regardless of their times.
+func (p *Player) PushAll() (int, error) {
+	n := 0
+	for !p.Done() {
+		if err := push(p.records[p.next]); err != nil {
+			return n, err
+		}
+		p.next++
+		n++
+	}
+	return n, nil
+}
+
+// Rewind starts the playback over.
+func (p *Player) Rewind() {
+	p.next = 0
+}
+
+func push(rec record) error {
+	// sdl.PushEvent copies the event into an SDL_Event itself, so a new
+	// value of the Go event type is enough.
+	event := reflect.New(eventTypes[rec.Type]).Interface()
+	if err := json.Unmarshal(rec.Event, event); err != nil {
+		return fmt.Errorf("eventrec: invalid %s: %v", rec.Type, err)
+	}
+	_, err := sdl.PushEvent(event.(sdl.Event))
+	return err
+}
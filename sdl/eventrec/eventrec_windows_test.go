@@ -0,0 +1,100 @@
+//+build windows
+
+package eventrec_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gonutz/check"
+	"github.com/gonutz/go-sdl2/sdl"
+	"github.com/gonutz/go-sdl2/sdl/eventrec"
+)
+
+func test(f func()) {
+	sdl.Main(func() {
+		sdl.Do(f)
+	})
+}
+
+func TestRecordedEventsArePlayedBack(t *testing.T) {
+	test(func() {
+		check.Eq(t, sdl.Init(sdl.INIT_EVENTS), nil)
+		defer sdl.Quit()
+		sdl.FlushEvents(sdl.FIRSTEVENT, sdl.LASTEVENT)
+
+		key := &sdl.KeyboardEvent{
+			Type:     sdl.KEYDOWN,
+			WindowID: 1,
+			State:    sdl.PRESSED,
+			Keysym:   sdl.Keysym{Scancode: sdl.SCANCODE_A, Sym: sdl.K_a},
+		}
+		motion := &sdl.MouseMotionEvent{
+			Type:     sdl.MOUSEMOTION,
+			WindowID: 1,
+			X:        10,
+			Y:        20,
+			XRel:     -3,
+			YRel:     4,
+		}
+		var buf bytes.Buffer
+		rec := eventrec.NewRecorder(&buf)
+		check.Eq(t, rec.RecordAt(100*time.Millisecond, key), nil)
+		check.Eq(t, rec.RecordAt(200*time.Millisecond, motion), nil)
+
+		player, err := eventrec.NewPlayer(&buf)
+		check.Eq(t, err, nil)
+		check.Eq(t, player.Done(), false)
+
+		n, err := player.PushUntil(50 * time.Millisecond)
+		check.Eq(t, err, nil)
+		check.Eq(t, n, 0)
+		check.Eq(t, sdl.PollEvent(), nil)
+
+		n, err = player.PushUntil(100 * time.Millisecond)
+		check.Eq(t, err, nil)
+		check.Eq(t, n, 1)
+		gotKey, ok := sdl.PollEvent().(*sdl.KeyboardEvent)
+		check.Eq(t, ok, true)
+		key.Timestamp = gotKey.Timestamp // set by SDL when pushing
+		check.Eq(t, gotKey, key)
+
+		n, err = player.PushAll()
+		check.Eq(t, err, nil)
+		check.Eq(t, n, 1)
+		check.Eq(t, player.Done(), true)
+		gotMotion, ok := sdl.PollEvent().(*sdl.MouseMotionEvent)
+		check.Eq(t, ok, true)
+		motion.Timestamp = gotMotion.Timestamp
+		check.Eq(t, gotMotion, motion)
+		check.Eq(t, sdl.PollEvent(), nil)
+
+		player.Rewind()
+		check.Eq(t, player.Done(), false)
+		n, err = player.PushAll()
+		check.Eq(t, err, nil)
+		check.Eq(t, n, 2)
+		sdl.FlushEvents(sdl.FIRSTEVENT, sdl.LASTEVENT)
+	})
+}
+
+func TestEventsWithPointersAreNotRecorded(t *testing.T) {
+	var buf bytes.Buffer
+	rec := eventrec.NewRecorder(&buf)
+	check.Eq(t, rec.RecordAt(0, &sdl.DropEvent{Type: sdl.DROPFILE, File: "a.txt"}), nil)
+	check.Eq(t, rec.RecordAt(0, sdl.NewUserEvent(sdl.USEREVENT, 1)), nil)
+	check.Eq(t, buf.Len(), 0)
+}
+
+func TestPlayerRejectsUnknownEventTypes(t *testing.T) {
+	_, err := eventrec.NewPlayer(strings.NewReader(
+		`{"time":0,"type":"UserEvent","event":{}}` + "\n",
+	))
+	check.Neq(t, err, nil)
+	check.Eq(t, strings.Contains(err.Error(), `unknown event type "UserEvent"`), true)
+
+	_, err = eventrec.NewPlayer(strings.NewReader("not JSON\n"))
+	check.Neq(t, err, nil)
+}
@@ -0,0 +1,418 @@
+//+build ignore
+
+/*
+This script generates procs_generated_windows.go from the function manifest in
+procs.txt. Run it with
+
+	go generate
+
+from the sdl folder after editing the manifest.
+
+Most of the wrappers in this package are thin: they convert the Go arguments to
+uintptrs, call the DLL function and convert the result back. Writing these by
+hand for every new SDL release is tedious and error-prone, so instead new
+functions are listed in the manifest and this script emits the NewProc
+declarations, the loading code in LoadDLL and the wrappers, all in the same
+style.
+
+Each entry in the manifest is a paragraph of the form
+
+	SDL_HasColorKey 2.0.9
+	// HasColorKey returns whether the surface has a color key.
+	func (surface *Surface) HasColorKey() bool
+
+The first line names the SDL function and the SDL version that introduced it.
+The comment lines become the documentation of the wrapper, the link to the SDL
+wiki and the version are appended automatically. The last line is the Go
+declaration, without a body. Lines starting with # are ignored.
+
+An entry that only has the first line declares the proc and records its
+version but generates no wrapper. Use this for functions that need a
+hand-written wrapper in sdl_windows.go, e.g. to convert or free the result,
+so that every function introduced after SDL 2.0.10 is listed in the manifest
+and its *ProcError names the required version.
+
+The wrapper body is derived from the declaration:
+
+	- The receiver and parameters are passed to SDL in the order they are
+	  declared. Pointers are passed as is, bools as 0 or 1, float32s as their
+	  bits, strings as zero-terminated UTF-8 and everything else as an integer.
+	- A single result is converted from the return value, a bool is true for
+	  non-zero values and a string is copied from the returned char*.
+	- A single error result means that SDL returns a negative int on failure.
+	- A pointer and an error result means that SDL returns NULL on failure.
+	- Other results, i.e. more than one non-error result, are output arguments
+	  which are passed as pointers after the parameters. If the last result is
+	  an error, SDL returns a negative int on failure.
+
+Wrappers that return an error first check that the loaded DLL has the
+function and return its *ProcError if not. All other wrappers panic with the
+*ProcError when called.
+
+Functions that do not fit these rules still have to be written by hand in
+sdl_windows.go.
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	manifestPath = "procs.txt"
+	outputPath   = "procs_generated_windows.go"
+)
+
+type proc struct {
+	line    int      // line in the manifest, for error messages
+	sdlName string   // e.g. "SDL_HasColorKey"
+	since   string   // e.g. "2.0.9"
+	doc     []string // the comment lines
+	decl    string   // the Go declaration without body
+}
+
+func main() {
+	procs, err := parseManifest(manifestPath)
+	if err != nil {
+		fail(err)
+	}
+	code, err := generate(procs)
+	if err != nil {
+		fail(err)
+	}
+	if err := ioutil.WriteFile(outputPath, code, 0666); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
+func parseManifest(path string) ([]proc, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var procs []proc
+	var cur *proc
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "#"):
+		case line == "":
+			cur = nil
+		case cur == nil:
+			fields := strings.Fields(line)
+			if len(fields) != 2 || !strings.HasPrefix(fields[0], "SDL_") {
+				return nil, fmt.Errorf(
+					"%s:%d: expected an SDL function name and version, e.g. SDL_IsTablet 2.0.9",
+					path, lineNumber,
+				)
+			}
+			procs = append(procs, proc{line: lineNumber, sdlName: fields[0], since: fields[1]})
+			cur = &procs[len(procs)-1]
+		case strings.HasPrefix(line, "//"):
+			cur.doc = append(cur.doc, line)
+		case strings.HasPrefix(line, "func "):
+			if cur.decl != "" {
+				return nil, fmt.Errorf("%s:%d: %s has two declarations", path, lineNumber, cur.sdlName)
+			}
+			cur.decl = line
+		default:
+			return nil, fmt.Errorf("%s:%d: unexpected line %q", path, lineNumber, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, p := range procs {
+		if p.decl == "" && len(p.doc) > 0 {
+			return nil, fmt.Errorf("%s:%d: %s has documentation but no declaration", path, p.line, p.sdlName)
+		}
+		if seen[p.sdlName] {
+			return nil, fmt.Errorf("%s:%d: %s is listed twice", path, p.line, p.sdlName)
+		}
+		seen[p.sdlName] = true
+	}
+	sort.Slice(procs, func(i, j int) bool {
+		return procVarName(procs[i].sdlName) < procVarName(procs[j].sdlName)
+	})
+	return procs, nil
+}
+
+// procVarName returns the name of the variable holding the proc, which is the
+// SDL name without the SDL_ prefix and with a lower case first letter.
+func procVarName(sdlName string) string {
+	name := strings.TrimPrefix(sdlName, "SDL_")
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+func generate(procs []proc) ([]byte, error) {
+	var buf bytes.Buffer
+	w := func(format string, a ...interface{}) {
+		fmt.Fprintf(&buf, format, a...)
+	}
+
+	var wrappers []string
+	for _, p := range procs {
+		if p.decl == "" {
+			continue // the wrapper is written by hand
+		}
+		wrapper, err := generateWrapper(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s: %v", manifestPath, p.line, p.sdlName, err)
+		}
+		wrappers = append(wrappers, wrapper)
+	}
+	allWrappers := strings.Join(wrappers, "")
+
+	w("// Code generated by generate_procs.go from procs.txt; DO NOT EDIT.\n\n")
+	w("//+build windows\n\n")
+	w("package sdl\n\n")
+	w("import (\n")
+	for _, pkg := range []string{"math", "unsafe"} {
+		if strings.Contains(allWrappers, pkg+".") {
+			w("\t%q\n", pkg)
+		}
+	}
+	w(")\n\n")
+
+	w("var (\n")
+	for _, p := range procs {
+		w("\t%s = dll.NewProc(%q)\n", procVarName(p.sdlName), p.sdlName)
+	}
+	w(")\n\n")
+
+	w("// loadGeneratedProcs is called by LoadDLL.\n")
+	w("func loadGeneratedProcs() {\n")
+	for _, p := range procs {
+		w("\t%s = dll.NewProc(%q)\n", procVarName(p.sdlName), p.sdlName)
+	}
+	w("}\n\n")
+
+	w("// procVersions maps the SDL functions in the manifest to the SDL version\n")
+	w("// that introduced them.\n")
+	w("var procVersions = map[string]string{\n")
+	for _, p := range procs {
+		w("\t%q: %q,\n", p.sdlName, p.since)
+	}
+	w("}\n")
+
+	for _, wrapper := range wrappers {
+		w("\n%s", wrapper)
+	}
+
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated code does not compile: %v\n%s", err, buf.Bytes())
+	}
+	// Newer versions of gofmt add a //go:build line, keep the build tag in
+	// the style of the other files.
+	code = bytes.Replace(code, []byte("//go:build windows\n// +build windows\n"), []byte("//+build windows\n"), 1)
+	return code, nil
+}
+
+func generateWrapper(p proc) (string, error) {
+	var fs token.FileSet
+	file, err := parser.ParseFile(&fs, "", "package p\n"+p.decl+" {}", 0)
+	if err != nil {
+		return "", err
+	}
+	f := file.Decls[0].(*ast.FuncDecl)
+	typeString := func(e ast.Expr) string {
+		var b bytes.Buffer
+		format.Node(&b, &fs, e)
+		return b.String()
+	}
+
+	var args []string
+	if f.Recv != nil {
+		recv := f.Recv.List[0]
+		if len(recv.Names) != 1 {
+			return "", fmt.Errorf("the receiver must be named")
+		}
+		arg, err := argument(recv.Names[0].Name, recv.Type, typeString)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, arg)
+	}
+	var prelude []string
+	for _, param := range f.Type.Params.List {
+		if len(param.Names) == 0 {
+			return "", fmt.Errorf("all parameters must be named")
+		}
+		for _, name := range param.Names {
+			if typeString(param.Type) == "string" {
+				prelude = append(prelude, fmt.Sprintf("%sBytes := append([]byte(%s), 0)", name.Name, name.Name))
+				args = append(args, fmt.Sprintf("uintptr(unsafe.Pointer(&%sBytes[0]))", name.Name))
+				continue
+			}
+			arg, err := argument(name.Name, param.Type, typeString)
+			if err != nil {
+				return "", err
+			}
+			args = append(args, arg)
+		}
+	}
+
+	type result struct{ name, typ string }
+	var results []result
+	if f.Type.Results != nil {
+		for _, r := range f.Type.Results.List {
+			if len(r.Names) == 0 {
+				results = append(results, result{typ: typeString(r.Type)})
+			}
+			for _, name := range r.Names {
+				results = append(results, result{name: name.Name, typ: typeString(r.Type)})
+			}
+		}
+	}
+	returnsError := len(results) > 0 && results[len(results)-1].typ == "error"
+
+	var guard, body []string
+	if returnsError {
+		// Report a missing function as an error instead of panicking in Call.
+		errName := results[len(results)-1].name
+		switch {
+		case errName != "":
+			guard = append(guard, fmt.Sprintf("if %s = %s.Find(); %s != nil {", errName, procVarName(p.sdlName), errName), "return", "}")
+		case len(results) == 1:
+			guard = append(guard, fmt.Sprintf("if err := %s.Find(); err != nil {", procVarName(p.sdlName)), "return err", "}")
+		case len(results) == 2 && strings.HasPrefix(results[0].typ, "*"):
+			guard = append(guard, fmt.Sprintf("if err := %s.Find(); err != nil {", procVarName(p.sdlName)), "return nil, err", "}")
+		}
+	}
+	call := func(assign string) {
+		c := procVarName(p.sdlName) + ".Call(" + strings.Join(args, ", ") + ")"
+		if len(args) > 2 {
+			// Put long argument lists on separate lines like the hand-written
+			// wrappers do.
+			c = procVarName(p.sdlName) + ".Call(\n" + strings.Join(args, ",\n") + ",\n)"
+		}
+		if assign != "" {
+			c = assign + " " + c
+		}
+		body = append(body, c)
+	}
+	switch {
+	case len(results) == 0:
+		call("")
+	case len(results) == 1 && returnsError:
+		call("ret, _, _ :=")
+		body = append(body, "return errorFromInt(int(ret))")
+	case len(results) == 1:
+		conv, err := convertResult("ret", results[0].typ)
+		if err != nil {
+			return "", err
+		}
+		call("ret, _, _ :=")
+		body = append(body, "return "+conv)
+	case len(results) == 2 && returnsError && strings.HasPrefix(results[0].typ, "*"):
+		call("ret, _, _ :=")
+		body = append(body,
+			"if ret == 0 {",
+			"return nil, GetError()",
+			"}",
+			fmt.Sprintf("return (%s)(unsafe.Pointer(ret)), nil", results[0].typ),
+		)
+	default:
+		outs := results
+		if returnsError {
+			outs = results[:len(results)-1]
+		}
+		for _, out := range outs {
+			if out.name == "" {
+				return "", fmt.Errorf("output arguments must be named results")
+			}
+			if strings.HasPrefix(out.typ, "*") || out.typ == "string" || out.typ == "bool" {
+				return "", fmt.Errorf("output argument %s has unsupported type %s", out.name, out.typ)
+			}
+			args = append(args, fmt.Sprintf("uintptr(unsafe.Pointer(&%s))", out.name))
+		}
+		if returnsError {
+			call("ret, _, _ :=")
+			body = append(body, "err = errorFromInt(int(ret))", "return")
+		} else {
+			call("")
+			body = append(body, "return")
+		}
+	}
+
+	var b strings.Builder
+	for _, line := range p.doc {
+		b.WriteString(line + "\n")
+	}
+	fmt.Fprintf(&b, "// This function requires SDL %s or newer.\n", p.since)
+	fmt.Fprintf(&b, "// (https://wiki.libsdl.org/%s)\n", p.sdlName)
+	b.WriteString(p.decl + " {\n")
+	for _, line := range guard {
+		b.WriteString(line + "\n")
+	}
+	for _, line := range prelude {
+		b.WriteString(line + "\n")
+	}
+	for _, line := range body {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+// argument returns the expression that converts the named Go value to a
+// uintptr for the DLL call.
+func argument(name string, typ ast.Expr, typeString func(ast.Expr) string) (string, error) {
+	t := typeString(typ)
+	switch {
+	case strings.HasPrefix(t, "*") || t == "unsafe.Pointer":
+		return fmt.Sprintf("uintptr(unsafe.Pointer(%s))", name), nil
+	case t == "uintptr":
+		return name, nil
+	case t == "bool":
+		return fmt.Sprintf("uintptr(Btoi(%s))", name), nil
+	case t == "float32":
+		return fmt.Sprintf("uintptr(math.Float32bits(%s))", name), nil
+	case t == "float64" || t == "int64" || t == "uint64":
+		return "", fmt.Errorf("%s is a %s which does not fit into a uintptr on 32 bit Windows", name, t)
+	case strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") ||
+		strings.HasPrefix(t, "func") || strings.HasPrefix(t, "chan") ||
+		strings.HasPrefix(t, "interface"):
+		return "", fmt.Errorf("%s has unsupported type %s", name, t)
+	default:
+		return fmt.Sprintf("uintptr(%s)", name), nil
+	}
+}
+
+// convertResult returns the expression that converts the uintptr returned
+// from the DLL to the Go result type.
+func convertResult(ret, typ string) (string, error) {
+	switch {
+	case strings.HasPrefix(typ, "*"):
+		return fmt.Sprintf("(%s)(unsafe.Pointer(%s))", typ, ret), nil
+	case typ == "bool":
+		return ret + " != 0", nil
+	case typ == "string":
+		return "sdlToGoString(" + ret + ")", nil
+	case typ == "uintptr":
+		return ret, nil
+	case typ == "float32" || typ == "float64" || typ == "int64" || typ == "uint64":
+		return "", fmt.Errorf("a %s result is not returned in a register that Call returns on all platforms", typ)
+	default:
+		return typ + "(" + ret + ")", nil
+	}
+}
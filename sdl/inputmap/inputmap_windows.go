@@ -0,0 +1,382 @@
+//+build windows
+
+/*
+Package inputmap binds logical actions like "jump" or "fire" to keyboard keys,
+mouse buttons and game controller buttons and axes. Game code then asks for
+actions instead of physical inputs, which makes it easy to support several
+input devices and to let the player remap the controls.
+
+	m := inputmap.New()
+	m.Bind("jump", inputmap.Key(sdl.SCANCODE_SPACE), inputmap.Button(sdl.CONTROLLER_BUTTON_A))
+	m.Bind("left", inputmap.Key(sdl.SCANCODE_A), inputmap.Axis(sdl.CONTROLLER_AXIS_LEFTX, -1))
+	m.Bind("right", inputmap.Key(sdl.SCANCODE_D), inputmap.Axis(sdl.CONTROLLER_AXIS_LEFTX, 1))
+	m.Controller = controller
+
+Once per frame, after polling the events, call Update and then query the
+actions:
+
+	m.Update()
+	if m.JustPressed("jump") {
+		player.Jump()
+	}
+	player.X += speed * m.Axis("left", "right")
+
+A Map is stored as JSON with readable names for the keys, buttons and axes,
+e.g. to save the player's controls in a settings file.
+
+Like the SDL input functions, use a Map only from the main thread.
+*/
+package inputmap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gonutz/go-sdl2/sdl"
+)
+
+// DefaultDeadZone is the dead zone of a new Map.
+const DefaultDeadZone = 0.2
+
+// Binding is a physical input that triggers an action. Create it with Key,
+// MouseButton, Button or Axis.
+type Binding struct {
+	kind      bindingKind
+	scancode  sdl.Scancode
+	mouse     uint8
+	button    sdl.GameControllerButton
+	axis      sdl.GameControllerAxis
+	direction int
+}
+
+type bindingKind int
+
+const (
+	keyBinding bindingKind = iota + 1
+	mouseBinding
+	buttonBinding
+	axisBinding
+)
+
+// Key binds a keyboard key. Scancodes name the physical key position so the
+// binding works for all keyboard layouts.
+func Key(code sdl.Scancode) Binding {
+	return Binding{kind: keyBinding, scancode: code}
+}
+
+// MouseButton binds one of sdl.BUTTON_LEFT, BUTTON_MIDDLE, BUTTON_RIGHT,
+// BUTTON_X1 or BUTTON_X2.
+func MouseButton(button uint8) Binding {
+	return Binding{kind: mouseBinding, mouse: button}
+}
+
+// Button binds a game controller button.
+func Button(button sdl.GameControllerButton) Binding {
+	return Binding{kind: buttonBinding, button: button}
+}
+
+// Axis binds one direction of a game controller axis. A negative direction
+// binds e.g. the left half of CONTROLLER_AXIS_LEFTX, a positive direction the
+// right half. Triggers only have a positive direction.
+func Axis(axis sdl.GameControllerAxis, direction int) Binding {
+	if direction < 0 {
+		direction = -1
+	} else {
+		direction = 1
+	}
+	return Binding{kind: axisBinding, axis: axis, direction: direction}
+}
+
+// String returns a readable description of the binding, e.g. "key Space" or
+// "axis -leftx".
+func (b Binding) String() string {
+	switch b.kind {
+	case keyBinding:
+		return "key " + sdl.GetScancodeName(b.scancode)
+	case mouseBinding:
+		return "mouse " + mouseButtonName(b.mouse)
+	case buttonBinding:
+		return "button " + sdl.GameControllerGetStringForButton(b.button)
+	case axisBinding:
+		return "axis " + directionSign(b.direction) + sdl.GameControllerGetStringForAxis(b.axis)
+	}
+	return "none"
+}
+
+// bindingJSON is the JSON form of a Binding. Exactly one of the inputs is set.
+type bindingJSON struct {
+	Key    string `json:"key,omitempty"`
+	Mouse  string `json:"mouse,omitempty"`
+	Button string `json:"button,omitempty"`
+	Axis   string `json:"axis,omitempty"` // the axis name prefixed with + or -
+}
+
+// MarshalJSON encodes the binding with the SDL names of its key, button or
+// axis, e.g. {"key":"Space"} or {"axis":"-leftx"}.
+func (b Binding) MarshalJSON() ([]byte, error) {
+	var j bindingJSON
+	switch b.kind {
+	case keyBinding:
+		j.Key = sdl.GetScancodeName(b.scancode)
+		if j.Key == "" {
+			return nil, fmt.Errorf("inputmap: scancode %d has no name", b.scancode)
+		}
+	case mouseBinding:
+		j.Mouse = mouseButtonName(b.mouse)
+	case buttonBinding:
+		j.Button = sdl.GameControllerGetStringForButton(b.button)
+		if j.Button == "" {
+			return nil, fmt.Errorf("inputmap: invalid controller button %d", b.button)
+		}
+	case axisBinding:
+		name := sdl.GameControllerGetStringForAxis(b.axis)
+		if name == "" {
+			return nil, fmt.Errorf("inputmap: invalid controller axis %d", b.axis)
+		}
+		j.Axis = directionSign(b.direction) + name
+	default:
+		return nil, errors.New("inputmap: empty binding")
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes a binding written by MarshalJSON.
+func (b *Binding) UnmarshalJSON(data []byte) error {
+	var j bindingJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	switch {
+	case j.Key != "":
+		code := sdl.GetScancodeFromName(j.Key)
+		if code == sdl.SCANCODE_UNKNOWN {
+			return fmt.Errorf("inputmap: unknown key %q", j.Key)
+		}
+		*b = Key(code)
+	case j.Mouse != "":
+		for i, name := range mouseButtonNames {
+			if name == j.Mouse {
+				*b = MouseButton(uint8(i + 1))
+				return nil
+			}
+		}
+		return fmt.Errorf("inputmap: unknown mouse button %q", j.Mouse)
+	case j.Button != "":
+		button := sdl.GameControllerGetButtonFromString(j.Button)
+		if button < 0 || button >= sdl.CONTROLLER_BUTTON_MAX {
+			return fmt.Errorf("inputmap: unknown controller button %q", j.Button)
+		}
+		*b = Button(button)
+	case j.Axis != "":
+		if len(j.Axis) < 2 || (j.Axis[0] != '+' && j.Axis[0] != '-') {
+			return fmt.Errorf("inputmap: axis %q must start with + or -", j.Axis)
+		}
+		axis := sdl.GameControllerGetAxisFromString(j.Axis[1:])
+		if axis < 0 || axis >= sdl.CONTROLLER_AXIS_MAX {
+			return fmt.Errorf("inputmap: unknown controller axis %q", j.Axis[1:])
+		}
+		direction := 1
+		if j.Axis[0] == '-' {
+			direction = -1
+		}
+		*b = Axis(axis, direction)
+	default:
+		return errors.New("inputmap: empty binding")
+	}
+	return nil
+}
+
+var mouseButtonNames = []string{"left", "middle", "right", "x1", "x2"}
+
+func mouseButtonName(button uint8) string {
+	if 1 <= button && int(button) <= len(mouseButtonNames) {
+		return mouseButtonNames[button-1]
+	}
+	return fmt.Sprint(button)
+}
+
+func directionSign(direction int) string {
+	if direction < 0 {
+		return "-"
+	}
+	return "+"
+}
+
+// Map maps action names to their bindings. Create it with New.
+type Map struct {
+	// DeadZone is the part of a controller axis' range, between 0 and 1,
+	// around the center that is ignored so worn out sticks do not trigger
+	// actions. Values outside of it are rescaled to start at 0.
+	DeadZone float32
+	// Controller is the game controller whose buttons and axes are read. If
+	// it is nil, controller bindings are ignored.
+	Controller *sdl.GameController
+
+	actions map[string][]Binding
+	values  map[string]float32
+	last    map[string]float32
+}
+
+// New returns an empty Map with the DefaultDeadZone.
+func New() *Map {
+	return &Map{
+		DeadZone: DefaultDeadZone,
+		actions:  make(map[string][]Binding),
+		values:   make(map[string]float32),
+		last:     make(map[string]float32),
+	}
+}
+
+// Actions returns the sorted names of all actions that have bindings.
+func (m *Map) Actions() []string {
+	var names []string
+	for name := range m.actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Bind adds bindings to the action. An action can have any number of
+// bindings, it is active if any of them is.
+func (m *Map) Bind(action string, bindings ...Binding) {
+	m.actions[action] = append(m.actions[action], bindings...)
+}
+
+// Bindings returns the bindings of the action.
+func (m *Map) Bindings(action string) []Binding {
+	return append([]Binding(nil), m.actions[action]...)
+}
+
+// Unbind removes all bindings from the action.
+func (m *Map) Unbind(action string) {
+	delete(m.actions, action)
+	delete(m.values, action)
+	delete(m.last, action)
+}
+
+// Update reads the current keyboard, mouse and controller state. Call it once
+// per frame after polling the events. The action queries return the state of
+// the last Update.
+func (m *Map) Update() {
+	var in inputState
+	in.keys = sdl.GetKeyboardState()
+	_, _, in.mouse = sdl.GetMouseState()
+	if m.Controller != nil {
+		in.controller = true
+		for b := sdl.GameControllerButton(0); b < sdl.CONTROLLER_BUTTON_MAX; b++ {
+			in.buttons[b] = m.Controller.Button(b) != 0
+		}
+		for a := sdl.GameControllerAxis(0); a < sdl.CONTROLLER_AXIS_MAX; a++ {
+			in.axes[a] = m.Controller.Axis(a)
+		}
+	}
+	m.update(&in)
+}
+
+// inputState is a snapshot of all inputs that bindings can refer to.
+type inputState struct {
+	keys       []uint8
+	mouse      uint32
+	controller bool
+	buttons    [sdl.CONTROLLER_BUTTON_MAX]bool
+	axes       [sdl.CONTROLLER_AXIS_MAX]int16
+}
+
+func (m *Map) update(in *inputState) {
+	for action, bindings := range m.actions {
+		m.last[action] = m.values[action]
+		var value float32
+		for _, b := range bindings {
+			if v := m.bindingValue(b, in); v > value {
+				value = v
+			}
+		}
+		m.values[action] = value
+	}
+}
+
+func (m *Map) bindingValue(b Binding, in *inputState) float32 {
+	switch b.kind {
+	case keyBinding:
+		if int(b.scancode) < len(in.keys) && in.keys[b.scancode] != 0 {
+			return 1
+		}
+	case mouseBinding:
+		if b.mouse != 0 && in.mouse&sdl.Button(uint32(b.mouse)) != 0 {
+			return 1
+		}
+	case buttonBinding:
+		if in.controller && 0 <= b.button && b.button < sdl.CONTROLLER_BUTTON_MAX && in.buttons[b.button] {
+			return 1
+		}
+	case axisBinding:
+		if in.controller && 0 <= b.axis && b.axis < sdl.CONTROLLER_AXIS_MAX {
+			v := sdl.NormalizeAxis(in.axes[b.axis], m.DeadZone) * float32(b.direction)
+			if v > 0 {
+				return v
+			}
+		}
+	}
+	return 0
+}
+
+// Value returns how strongly the action is triggered, from 0 to 1. Keys and
+// buttons are either 0 or 1, axes are in between. If several bindings are
+// active, the strongest wins.
+func (m *Map) Value(action string) float32 {
+	return m.values[action]
+}
+
+// Pressed returns whether any binding of the action is active.
+func (m *Map) Pressed(action string) bool {
+	return m.values[action] > 0
+}
+
+// JustPressed returns whether the action became active in the last Update.
+func (m *Map) JustPressed(action string) bool {
+	return m.values[action] > 0 && m.last[action] == 0
+}
+
+// JustReleased returns whether the action stopped being active in the last
+// Update.
+func (m *Map) JustReleased(action string) bool {
+	return m.values[action] == 0 && m.last[action] > 0
+}
+
+// Axis combines two opposite actions into a value from -1 to 1, e.g.
+// m.Axis("left", "right") for horizontal movement.
+func (m *Map) Axis(negative, positive string) float32 {
+	return m.values[positive] - m.values[negative]
+}
+
+// mapJSON is the JSON form of a Map.
+type mapJSON struct {
+	DeadZone float32              `json:"deadZone"`
+	Actions  map[string][]Binding `json:"actions"`
+}
+
+// MarshalJSON encodes the dead zone and the bindings of all actions. The
+// Controller is not stored.
+func (m *Map) MarshalJSON() ([]byte, error) {
+	return json.Marshal(mapJSON{DeadZone: m.DeadZone, Actions: m.actions})
+}
+
+// UnmarshalJSON replaces the dead zone and all bindings with the ones encoded
+// by MarshalJSON. The Controller is kept.
+func (m *Map) UnmarshalJSON(data []byte) error {
+	j := mapJSON{DeadZone: DefaultDeadZone}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Actions == nil {
+		j.Actions = make(map[string][]Binding)
+	}
+	m.DeadZone = j.DeadZone
+	m.actions = j.Actions
+	m.values = make(map[string]float32)
+	m.last = make(map[string]float32)
+	return nil
+}
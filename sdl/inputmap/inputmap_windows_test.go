@@ -0,0 +1,123 @@
+//+build windows
+
+package inputmap
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gonutz/check"
+	"github.com/gonutz/go-sdl2/sdl"
+)
+
+func TestMapSurvivesJSONRoundTrip(t *testing.T) {
+	m := New()
+	m.DeadZone = 0.25
+	m.Bind("jump", Key(sdl.SCANCODE_SPACE), Button(sdl.CONTROLLER_BUTTON_A))
+	m.Bind("fire", MouseButton(sdl.BUTTON_LEFT))
+	m.Bind("left", Axis(sdl.CONTROLLER_AXIS_LEFTX, -1))
+	m.Bind("brake", Axis(sdl.CONTROLLER_AXIS_TRIGGERLEFT, 1))
+
+	data, err := json.Marshal(m)
+	check.Eq(t, err, nil)
+	text := string(data)
+	check.Eq(t, strings.Contains(text, `{"key":"Space"}`), true)
+	check.Eq(t, strings.Contains(text, `{"button":"a"}`), true)
+	check.Eq(t, strings.Contains(text, `{"mouse":"left"}`), true)
+	check.Eq(t, strings.Contains(text, `{"axis":"-leftx"}`), true)
+	check.Eq(t, strings.Contains(text, `{"axis":"+lefttrigger"}`), true)
+
+	loaded := New()
+	check.Eq(t, json.Unmarshal(data, loaded), nil)
+	check.Eq(t, loaded.DeadZone, float32(0.25))
+	check.Eq(t, loaded.Actions(), []string{"brake", "fire", "jump", "left"})
+	for _, action := range m.Actions() {
+		check.Eq(t, loaded.Bindings(action), m.Bindings(action))
+	}
+}
+
+func TestUnknownBindingNamesAreRejected(t *testing.T) {
+	var b Binding
+	check.Neq(t, json.Unmarshal([]byte(`{"key":"NoSuchKey"}`), &b), nil)
+	check.Neq(t, json.Unmarshal([]byte(`{"mouse":"x3"}`), &b), nil)
+	check.Neq(t, json.Unmarshal([]byte(`{"button":"z"}`), &b), nil)
+	check.Neq(t, json.Unmarshal([]byte(`{"axis":"leftx"}`), &b), nil)
+	check.Neq(t, json.Unmarshal([]byte(`{}`), &b), nil)
+}
+
+func TestActionCanBeRebound(t *testing.T) {
+	keys := make([]uint8, sdl.NUM_SCANCODES)
+	keys[sdl.SCANCODE_SPACE] = 1
+	in := inputState{keys: keys, controller: true}
+
+	m := New()
+	m.Bind("jump", Key(sdl.SCANCODE_SPACE))
+	m.update(&in)
+	check.Eq(t, m.Pressed("jump"), true)
+	check.Eq(t, m.JustPressed("jump"), true)
+
+	m.Unbind("jump")
+	check.Eq(t, m.Pressed("jump"), false)
+	check.Eq(t, len(m.Bindings("jump")), 0)
+
+	m.Bind("jump", Button(sdl.CONTROLLER_BUTTON_A))
+	m.update(&in)
+	check.Eq(t, m.Pressed("jump"), false)
+
+	in.buttons[sdl.CONTROLLER_BUTTON_A] = true
+	m.update(&in)
+	check.Eq(t, m.JustPressed("jump"), true)
+
+	in.buttons[sdl.CONTROLLER_BUTTON_A] = false
+	m.update(&in)
+	check.Eq(t, m.JustReleased("jump"), true)
+}
+
+func TestAxisTriggersActionsOutsideTheDeadZone(t *testing.T) {
+	m := New()
+	m.DeadZone = 0.2
+	m.Bind("left", Axis(sdl.CONTROLLER_AXIS_LEFTX, -1))
+	m.Bind("right", Axis(sdl.CONTROLLER_AXIS_LEFTX, 1))
+
+	value := func(axis int16) (left, right float32) {
+		in := inputState{controller: true}
+		in.axes[sdl.CONTROLLER_AXIS_LEFTX] = axis
+		m.update(&in)
+		return m.Value("left"), m.Value("right")
+	}
+
+	left, right := value(6000) // below 0.2 * 32767
+	check.Eq(t, left, float32(0))
+	check.Eq(t, right, float32(0))
+	check.Eq(t, m.Pressed("right"), false)
+
+	left, right = value(7000)
+	check.Eq(t, left, float32(0))
+	check.Eq(t, right > 0, true)
+	check.Eq(t, m.JustPressed("right"), true)
+
+	left, right = value(32767)
+	check.Eq(t, left, float32(0))
+	check.Eq(t, right, float32(1))
+	check.Eq(t, m.Axis("left", "right"), float32(1))
+
+	left, right = value(-32768)
+	check.Eq(t, left, float32(1))
+	check.Eq(t, right, float32(0))
+	check.Eq(t, m.JustReleased("right"), true)
+	check.Eq(t, m.Axis("left", "right"), float32(-1))
+}
+
+func TestControllerBindingsAreIgnoredWithoutController(t *testing.T) {
+	m := New()
+	m.Bind("jump", Button(sdl.CONTROLLER_BUTTON_A))
+	m.Bind("right", Axis(sdl.CONTROLLER_AXIS_LEFTX, 1))
+
+	var in inputState
+	in.buttons[sdl.CONTROLLER_BUTTON_A] = true
+	in.axes[sdl.CONTROLLER_AXIS_LEFTX] = 32767
+	m.update(&in)
+	check.Eq(t, m.Pressed("jump"), false)
+	check.Eq(t, m.Pressed("right"), false)
+}
@@ -0,0 +1,44 @@
+//+build windows,go1.21
+
+package sdl
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// LogSetOutputHandler replaces the default log output function with one that
+// passes every message to h, so SDL's messages end up in the application's
+// structured log. The log category is added as the "category" attribute.
+func LogSetOutputHandler(h slog.Handler) {
+	LogSetOutputFunction(func(_ interface{}, category LogCategory, pri LogPriority, message string) {
+		ctx := context.Background()
+		level := pri.Level()
+		if !h.Enabled(ctx, level) {
+			return
+		}
+		r := slog.NewRecord(time.Now(), level, message, 0)
+		r.AddAttrs(slog.String("category", category.String()))
+		h.Handle(ctx, r)
+	}, nil)
+}
+
+// Level returns the slog level matching the priority. Verbose messages are
+// below slog.LevelDebug and critical ones above slog.LevelError.
+func (p LogPriority) Level() slog.Level {
+	switch p {
+	case LOG_PRIORITY_VERBOSE:
+		return slog.LevelDebug - 4
+	case LOG_PRIORITY_DEBUG:
+		return slog.LevelDebug
+	case LOG_PRIORITY_INFO:
+		return slog.LevelInfo
+	case LOG_PRIORITY_WARN:
+		return slog.LevelWarn
+	case LOG_PRIORITY_ERROR:
+		return slog.LevelError
+	default:
+		return slog.LevelError + 4
+	}
+}
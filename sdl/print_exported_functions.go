@@ -20,6 +20,7 @@ func main() {
 	checkFile("sdl_windows.go", funcs)
 	checkFile("sdl_windows_386.go", funcs)
 	checkFile("sdl_windows_amd64.go", funcs)
+	checkFile("procs_generated_windows.go", funcs)
 	var list []string
 	for name := range funcs {
 		list = append(list, name)
@@ -10,16 +10,30 @@ mouse, joystick, and graphics hardware via OpenGL and Direct3D.
 package sdl
 
 import (
+	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"image"
 	"image/color"
+	"image/draw"
+	"io"
+	"io/ioutil"
 	"math"
+	"os"
+	"os/signal"
+	"path/filepath"
 	"reflect"
 	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
+	"unicode/utf8"
 	"unsafe"
 )
 
@@ -169,13 +183,14 @@ const (
 	MOUSEWHEEL      = 0x400 + 3 // mouse wheel motion
 
 	// Joystick events
-	JOYAXISMOTION    = 0x600     // joystick axis motion
-	JOYBALLMOTION    = 0x600 + 1 // joystick trackball motion
-	JOYHATMOTION     = 0x600 + 2 // joystick hat position change
-	JOYBUTTONDOWN    = 0x600 + 3 // joystick button pressed
-	JOYBUTTONUP      = 0x600 + 4 // joystick button released
-	JOYDEVICEADDED   = 0x600 + 5 // joystick connected
-	JOYDEVICEREMOVED = 0x600 + 6 // joystick disconnected
+	JOYAXISMOTION     = 0x600     // joystick axis motion
+	JOYBALLMOTION     = 0x600 + 1 // joystick trackball motion
+	JOYHATMOTION      = 0x600 + 2 // joystick hat position change
+	JOYBUTTONDOWN     = 0x600 + 3 // joystick button pressed
+	JOYBUTTONUP       = 0x600 + 4 // joystick button released
+	JOYDEVICEADDED    = 0x600 + 5 // joystick connected
+	JOYDEVICEREMOVED  = 0x600 + 6 // joystick disconnected
+	JOYBATTERYUPDATED = 0x600 + 7 // joystick battery level changed (>= SDL 2.24.0)
 
 	// Game controller events
 	CONTROLLERAXISMOTION     = 0x650     // controller axis motion
@@ -236,6 +251,16 @@ const (
 	ENABLE  = 1
 )
 
+// The possible results of an assertion handler.
+// (https://wiki.libsdl.org/SDL_AssertState)
+const (
+	ASSERTION_RETRY         AssertState = iota // retry the assert immediately
+	ASSERTION_BREAK                            // make the debugger trigger a breakpoint
+	ASSERTION_ABORT                            // terminate the program
+	ASSERTION_IGNORE                           // ignore the assert
+	ASSERTION_ALWAYS_IGNORE                    // ignore the assert from now on
+)
+
 // Types of game controller inputs.
 const (
 	CONTROLLER_BINDTYPE_NONE = iota
@@ -279,6 +304,33 @@ const (
 	CONTROLLER_BUTTON_MAX
 )
 
+// An enumeration of game controller types, see GameController.Type.
+// (https://wiki.libsdl.org/SDL_GameControllerType)
+const (
+	CONTROLLER_TYPE_UNKNOWN                      = iota
+	CONTROLLER_TYPE_XBOX360                      // Xbox 360 controller
+	CONTROLLER_TYPE_XBOXONE                      // Xbox One or Series controller
+	CONTROLLER_TYPE_PS3                          // PlayStation 3 controller
+	CONTROLLER_TYPE_PS4                          // PlayStation 4 controller
+	CONTROLLER_TYPE_NINTENDO_SWITCH_PRO          // Nintendo Switch Pro controller
+	CONTROLLER_TYPE_VIRTUAL                      // virtual controller (>= SDL 2.0.14)
+	CONTROLLER_TYPE_PS5                          // PlayStation 5 controller (>= SDL 2.0.14)
+	CONTROLLER_TYPE_AMAZON_LUNA                  // Amazon Luna controller (>= SDL 2.0.16)
+	CONTROLLER_TYPE_GOOGLE_STADIA                // Google Stadia controller (>= SDL 2.0.16)
+	CONTROLLER_TYPE_NVIDIA_SHIELD                // NVIDIA Shield controller (>= SDL 2.24.0)
+	CONTROLLER_TYPE_NINTENDO_SWITCH_JOYCON_LEFT  // left Joy-Con (>= SDL 2.24.0)
+	CONTROLLER_TYPE_NINTENDO_SWITCH_JOYCON_RIGHT // right Joy-Con (>= SDL 2.24.0)
+	CONTROLLER_TYPE_NINTENDO_SWITCH_JOYCON_PAIR  // pair of Joy-Cons (>= SDL 2.24.0)
+)
+
+// Window flash operations, see Window.Flash.
+// (https://wiki.libsdl.org/SDL_FlashOperation)
+const (
+	FLASH_CANCEL        FlashOperation = iota // cancel any flashing
+	FLASH_BRIEFLY                             // flash the window briefly to get attention
+	FLASH_UNTIL_FOCUSED                       // flash the window until it gets focus
+)
+
 // Haptic effects.
 // (https://wiki.libsdl.org/SDL_HapticEffect)
 const (
@@ -339,6 +391,7 @@ const (
 	HINT_XINPUT_ENABLED                           = "SDL_XINPUT_ENABLED"                           // specifies if Xinput gamepad devices are detected
 	HINT_XINPUT_USE_OLD_JOYSTICK_MAPPING          = "SDL_XINPUT_USE_OLD_JOYSTICK_MAPPING"          // specifies that SDL should use the old axis and button mapping for XInput devices
 	HINT_GAMECONTROLLERCONFIG                     = "SDL_GAMECONTROLLERCONFIG"                     // specifies extra gamecontroller db entries
+	HINT_GAMECONTROLLER_USE_BUTTON_LABELS         = "SDL_GAMECONTROLLER_USE_BUTTON_LABELS"         // specifies whether Nintendo face buttons are reported by their labels (the default) or positions (>= SDL 2.0.12)
 	HINT_JOYSTICK_ALLOW_BACKGROUND_EVENTS         = "SDL_JOYSTICK_ALLOW_BACKGROUND_EVENTS"         // specifies if joystick (and gamecontroller) events are enabled even when the application is in the background
 	HINT_ALLOW_TOPMOST                            = "SDL_ALLOW_TOPMOST"                            // specifies if top most bit on an SDL Window can be set
 	HINT_THREAD_STACK_SIZE                        = "SDL_THREAD_STACK_SIZE"                        // specifies a variable specifying SDL's threads stack size in bytes or "0" for the backend's default size
@@ -368,6 +421,7 @@ const (
 	HINT_WINDOWS_INTRESOURCE_ICON_SMALL           = "SDL_WINDOWS_INTRESOURCE_ICON_SMALL"           // specifies a variable to specify custom icon resource id from RC file on Windows platform
 	HINT_IOS_HIDE_HOME_INDICATOR                  = "SDL_IOS_HIDE_HOME_INDICATOR"                  // specifies a variable controlling whether the home indicator bar on iPhone X should be hidden.
 	HINT_RETURN_KEY_HIDES_IME                     = "SDL_RETURN_KEY_HIDES_IME"                     // specifies a variable to control whether the return key on the soft keyboard should hide the soft keyboard on Android and iOS.
+	HINT_ENABLE_SCREEN_KEYBOARD                   = "SDL_ENABLE_SCREEN_KEYBOARD"                   // specifies whether the on-screen keyboard is shown when text input starts, on platforms that support one (>= SDL 2.28.0, older versions ignore it)
 	HINT_TV_REMOTE_AS_JOYSTICK                    = "SDL_TV_REMOTE_AS_JOYSTICK"                    // specifies a variable controlling whether the Android / tvOS remotes  should be listed as joystick devices, instead of sending keyboard events.
 	HINT_VIDEO_X11_NET_WM_BYPASS_COMPOSITOR       = "SDL_VIDEO_X11_NET_WM_BYPASS_COMPOSITOR"       // specifies a variable controlling whether the X11 _NET_WM_BYPASS_COMPOSITOR hint should be used.
 	HINT_VIDEO_DOUBLE_BUFFER                      = "SDL_VIDEO_DOUBLE_BUFFER"                      // specifies a variable that tells the video driver that we only want a double buffer.
@@ -381,6 +435,20 @@ const (
 	HINT_OVERRIDE        // high priority
 )
 
+// Animation modes, see Animation.
+const (
+	ANIMATION_ONCE      AnimationMode = iota // play once and stop at the last frame
+	ANIMATION_LOOP                           // start over after the last frame
+	ANIMATION_PING_PONG                      // play forward, then backward, then forward again
+)
+
+// Values for HINT_RENDER_SCALE_QUALITY, see Config.
+const (
+	SCALE_QUALITY_NEAREST ScaleQuality = "nearest" // nearest pixel sampling
+	SCALE_QUALITY_LINEAR  ScaleQuality = "linear"  // linear filtering (supported by OpenGL and Direct3D)
+	SCALE_QUALITY_BEST    ScaleQuality = "best"    // anisotropic filtering (supported by Direct3D)
+)
+
 // Hat positions.
 // (https://wiki.libsdl.org/SDL_JoystickGetHat)
 const (
@@ -708,38 +776,38 @@ const (
 // An enumeration of the predefined log categories.
 // (https://wiki.libsdl.org/SDL_LOG_CATEGORY)
 const (
-	LOG_CATEGORY_APPLICATION = iota // application log
-	LOG_CATEGORY_ERROR              // error log
-	LOG_CATEGORY_ASSERT             // assert log
-	LOG_CATEGORY_SYSTEM             // system log
-	LOG_CATEGORY_AUDIO              // audio log
-	LOG_CATEGORY_VIDEO              // video log
-	LOG_CATEGORY_RENDER             // render log
-	LOG_CATEGORY_INPUT              // input log
-	LOG_CATEGORY_TEST               // test log
-	LOG_CATEGORY_RESERVED1          // reserved for future SDL library use
-	LOG_CATEGORY_RESERVED2          // reserved for future SDL library use
-	LOG_CATEGORY_RESERVED3          // reserved for future SDL library use
-	LOG_CATEGORY_RESERVED4          // reserved for future SDL library use
-	LOG_CATEGORY_RESERVED5          // reserved for future SDL library use
-	LOG_CATEGORY_RESERVED6          // reserved for future SDL library use
-	LOG_CATEGORY_RESERVED7          // reserved for future SDL library use
-	LOG_CATEGORY_RESERVED8          // reserved for future SDL library use
-	LOG_CATEGORY_RESERVED9          // reserved for future SDL library use
-	LOG_CATEGORY_RESERVED10         // reserved for future SDL library use
-	LOG_CATEGORY_CUSTOM             // reserved for application use
+	LOG_CATEGORY_APPLICATION LogCategory = iota // application log
+	LOG_CATEGORY_ERROR                          // error log
+	LOG_CATEGORY_ASSERT                         // assert log
+	LOG_CATEGORY_SYSTEM                         // system log
+	LOG_CATEGORY_AUDIO                          // audio log
+	LOG_CATEGORY_VIDEO                          // video log
+	LOG_CATEGORY_RENDER                         // render log
+	LOG_CATEGORY_INPUT                          // input log
+	LOG_CATEGORY_TEST                           // test log
+	LOG_CATEGORY_RESERVED1                      // reserved for future SDL library use
+	LOG_CATEGORY_RESERVED2                      // reserved for future SDL library use
+	LOG_CATEGORY_RESERVED3                      // reserved for future SDL library use
+	LOG_CATEGORY_RESERVED4                      // reserved for future SDL library use
+	LOG_CATEGORY_RESERVED5                      // reserved for future SDL library use
+	LOG_CATEGORY_RESERVED6                      // reserved for future SDL library use
+	LOG_CATEGORY_RESERVED7                      // reserved for future SDL library use
+	LOG_CATEGORY_RESERVED8                      // reserved for future SDL library use
+	LOG_CATEGORY_RESERVED9                      // reserved for future SDL library use
+	LOG_CATEGORY_RESERVED10                     // reserved for future SDL library use
+	LOG_CATEGORY_CUSTOM                         // reserved for application use
 )
 
 // An enumeration of the predefined log priorities.
 // (https://wiki.libsdl.org/SDL_LogPriority)
 const (
-	LOG_PRIORITY_VERBOSE  = iota + 1 // verbose
-	LOG_PRIORITY_DEBUG               // debug
-	LOG_PRIORITY_INFO                // info
-	LOG_PRIORITY_WARN                // warn
-	LOG_PRIORITY_ERROR               // error
-	LOG_PRIORITY_CRITICAL            // critical
-	NUM_LOG_PRIORITIES               // (internal use)
+	LOG_PRIORITY_VERBOSE  LogPriority = iota + 1 // verbose
+	LOG_PRIORITY_DEBUG                           // debug
+	LOG_PRIORITY_INFO                            // info
+	LOG_PRIORITY_WARN                            // warn
+	LOG_PRIORITY_ERROR                           // error
+	LOG_PRIORITY_CRITICAL                        // critical
+	NUM_LOG_PRIORITIES                           // (internal use)
 )
 
 // Cursor types for CreateSystemCursor()
@@ -774,6 +842,12 @@ const (
 	BUTTON_X2     = 5 // x2 mouse button
 )
 
+// Return values of Mutex.TryLock, Sem.TryWait and the WaitTimeout functions.
+const (
+	MUTEX_TIMEDOUT = 1          // the mutex was locked or the time ran out
+	MUTEX_MAXWAIT  = ^uint32(0) // wait forever in the WaitTimeout functions
+)
+
 // Pixel types.
 const (
 	PIXELTYPE_UNKNOWN = iota
@@ -1291,28 +1365,32 @@ const (
 	WINDOW_TOOLTIP            = 0x00040000                     // window should be treated as a tooltip (X11 only, >= SDL 2.0.5)
 	WINDOW_POPUP_MENU         = 0x00080000                     // window should be treated as a popup menu (X11 only, >= SDL 2.0.5)
 	WINDOW_VULKAN             = 0x10000000                     // window usable for Vulkan surface (>= SDL 2.0.6)
+	WINDOW_MOUSE_GRABBED      = WINDOW_INPUT_GRABBED           // window has grabbed mouse input (>= SDL 2.0.16)
+	WINDOW_KEYBOARD_GRABBED   = 0x00100000                     // window has grabbed keyboard input (>= SDL 2.0.16)
 )
 
 // An enumeration of window events.
 // (https://wiki.libsdl.org/SDL_WindowEventID)
 const (
-	WINDOWEVENT_NONE         = iota // (never used)
-	WINDOWEVENT_SHOWN               // window has been shown
-	WINDOWEVENT_HIDDEN              // window has been hidden
-	WINDOWEVENT_EXPOSED             // window has been exposed and should be redrawn
-	WINDOWEVENT_MOVED               // window has been moved to data1, data2
-	WINDOWEVENT_RESIZED             // window has been resized to data1xdata2; this event is always preceded by WINDOWEVENT_SIZE_CHANGED
-	WINDOWEVENT_SIZE_CHANGED        // window size has changed, either as a result of an API call or through the system or user changing the window size; this event is followed by WINDOWEVENT_RESIZED if the size was changed by an external event, i.e. the user or the window manager
-	WINDOWEVENT_MINIMIZED           // window has been minimized
-	WINDOWEVENT_MAXIMIZED           // window has been maximized
-	WINDOWEVENT_RESTORED            // window has been restored to normal size and position
-	WINDOWEVENT_ENTER               // window has gained mouse focus
-	WINDOWEVENT_LEAVE               // window has lost mouse focus
-	WINDOWEVENT_FOCUS_GAINED        // window has gained keyboard focus
-	WINDOWEVENT_FOCUS_LOST          // window has lost keyboard focus
-	WINDOWEVENT_CLOSE               // the window manager requests that the window be closed
-	WINDOWEVENT_TAKE_FOCUS          // window is being offered a focus (should SDL_SetWindowInputFocus() on itself or a subwindow, or ignore) (>= SDL 2.0.5)
-	WINDOWEVENT_HIT_TEST            // window had a hit test that wasn't SDL_HITTEST_NORMAL (>= SDL 2.0.5)
+	WINDOWEVENT_NONE            = iota // (never used)
+	WINDOWEVENT_SHOWN                  // window has been shown
+	WINDOWEVENT_HIDDEN                 // window has been hidden
+	WINDOWEVENT_EXPOSED                // window has been exposed and should be redrawn
+	WINDOWEVENT_MOVED                  // window has been moved to data1, data2
+	WINDOWEVENT_RESIZED                // window has been resized to data1xdata2; this event is always preceded by WINDOWEVENT_SIZE_CHANGED
+	WINDOWEVENT_SIZE_CHANGED           // window size has changed, either as a result of an API call or through the system or user changing the window size; this event is followed by WINDOWEVENT_RESIZED if the size was changed by an external event, i.e. the user or the window manager
+	WINDOWEVENT_MINIMIZED              // window has been minimized
+	WINDOWEVENT_MAXIMIZED              // window has been maximized
+	WINDOWEVENT_RESTORED               // window has been restored to normal size and position
+	WINDOWEVENT_ENTER                  // window has gained mouse focus
+	WINDOWEVENT_LEAVE                  // window has lost mouse focus
+	WINDOWEVENT_FOCUS_GAINED           // window has gained keyboard focus
+	WINDOWEVENT_FOCUS_LOST             // window has lost keyboard focus
+	WINDOWEVENT_CLOSE                  // the window manager requests that the window be closed
+	WINDOWEVENT_TAKE_FOCUS             // window is being offered a focus (should SDL_SetWindowInputFocus() on itself or a subwindow, or ignore) (>= SDL 2.0.5)
+	WINDOWEVENT_HIT_TEST               // window had a hit test that wasn't SDL_HITTEST_NORMAL (>= SDL 2.0.5)
+	WINDOWEVENT_ICCPROF_CHANGED        // the ICC profile of the window's display has changed (>= SDL 2.0.18)
+	WINDOWEVENT_DISPLAY_CHANGED        // window has been moved to display data1 (>= SDL 2.0.18)
 )
 
 // Window position flags.
@@ -1421,516 +1499,761 @@ const (
 
 var ErrInvalidParameters = errors.New("Invalid Parameters")
 
+// These errors can be compared to the errors returned by this package with
+// errors.Is, e.g.
+//
+// 	if errors.Is(err, sdl.ErrUnsupported) { ... }
 var (
-	dll = syscall.NewLazyDLL("SDL2.dll")
-
-	addHintCallback                   = dll.NewProc("SDL_AddHintCallback")
-	audioInit                         = dll.NewProc("SDL_AudioInit")
-	audioQuit                         = dll.NewProc("SDL_AudioQuit")
-	buildAudioCVT                     = dll.NewProc("SDL_BuildAudioCVT")
-	calculateGammaRamp                = dll.NewProc("SDL_CalculateGammaRamp")
-	captureMouse                      = dll.NewProc("SDL_CaptureMouse")
-	clearError                        = dll.NewProc("SDL_ClearError")
-	clearHints                        = dll.NewProc("SDL_ClearHints")
-	clearQueuedAudio                  = dll.NewProc("SDL_ClearQueuedAudio")
-	getError                          = dll.NewProc("SDL_GetError")
-	closeAudio                        = dll.NewProc("SDL_CloseAudio")
-	closeAudioDevice                  = dll.NewProc("SDL_CloseAudioDevice")
-	convertAudio                      = dll.NewProc("SDL_ConvertAudio")
-	convertPixels                     = dll.NewProc("SDL_ConvertPixels")
-	createWindowAndRenderer           = dll.NewProc("SDL_CreateWindowAndRenderer")
-	threadID                          = dll.NewProc("SDL_ThreadID")
-	delEventWatch                     = dll.NewProc("SDL_DelEventWatch")
-	delay                             = dll.NewProc("SDL_Delay")
-	dequeueAudio                      = dll.NewProc("SDL_DequeueAudio")
-	disableScreenSaver                = dll.NewProc("SDL_DisableScreenSaver")
-	enableScreenSaver                 = dll.NewProc("SDL_EnableScreenSaver")
-	sdlError                          = dll.NewProc("SDL_Error")
-	flushEvent                        = dll.NewProc("SDL_FlushEvent")
-	flushEvents                       = dll.NewProc("SDL_FlushEvents")
-	freeCursor                        = dll.NewProc("SDL_FreeCursor")
-	freeWAV                           = dll.NewProc("SDL_FreeWAV")
-	gl_DeleteContext                  = dll.NewProc("SDL_GL_DeleteContext")
-	gl_ExtensionSupported             = dll.NewProc("SDL_GL_ExtensionSupported")
-	gl_GetAttribute                   = dll.NewProc("SDL_GL_GetAttribute")
-	gl_GetProcAddress                 = dll.NewProc("SDL_GL_GetProcAddress")
-	gl_GetSwapInterval                = dll.NewProc("SDL_GL_GetSwapInterval")
-	gl_LoadLibrary                    = dll.NewProc("SDL_GL_LoadLibrary")
-	gl_SetAttribute                   = dll.NewProc("SDL_GL_SetAttribute")
-	gl_SetSwapInterval                = dll.NewProc("SDL_GL_SetSwapInterval")
-	gl_UnloadLibrary                  = dll.NewProc("SDL_GL_UnloadLibrary")
-	gameControllerAddMapping          = dll.NewProc("SDL_GameControllerAddMapping")
-	gameControllerEventState          = dll.NewProc("SDL_GameControllerEventState")
-	gameControllerGetStringForAxis    = dll.NewProc("SDL_GameControllerGetStringForAxis")
-	gameControllerGetStringForButton  = dll.NewProc("SDL_GameControllerGetStringForButton")
-	gameControllerMappingForGUID      = dll.NewProc("SDL_GameControllerMappingForGUID")
-	gameControllerMappingForIndex     = dll.NewProc("SDL_GameControllerMappingForIndex")
-	gameControllerNameForIndex        = dll.NewProc("SDL_GameControllerNameForIndex")
-	gameControllerNumMappings         = dll.NewProc("SDL_GameControllerNumMappings")
-	gameControllerUpdate              = dll.NewProc("SDL_GameControllerUpdate")
-	getAudioDeviceName                = dll.NewProc("SDL_GetAudioDeviceName")
-	getAudioDriver                    = dll.NewProc("SDL_GetAudioDriver")
-	getBasePath                       = dll.NewProc("SDL_GetBasePath")
-	getCPUCacheLineSize               = dll.NewProc("SDL_GetCPUCacheLineSize")
-	getCPUCount                       = dll.NewProc("SDL_GetCPUCount")
-	getClipboardText                  = dll.NewProc("SDL_GetClipboardText")
-	getCurrentAudioDriver             = dll.NewProc("SDL_GetCurrentAudioDriver")
-	getCurrentVideoDriver             = dll.NewProc("SDL_GetCurrentVideoDriver")
-	getDisplayDPI                     = dll.NewProc("SDL_GetDisplayDPI")
-	getDisplayName                    = dll.NewProc("SDL_GetDisplayName")
-	eventState                        = dll.NewProc("SDL_EventState")
-	getGlobalMouseState               = dll.NewProc("SDL_GetGlobalMouseState")
-	filterEvents                      = dll.NewProc("SDL_FilterEvents")
-	getHint                           = dll.NewProc("SDL_GetHint")
-	getKeyName                        = dll.NewProc("SDL_GetKeyName")
-	getKeyboardState                  = dll.NewProc("SDL_GetKeyboardState")
-	getMouseState                     = dll.NewProc("SDL_GetMouseState")
-	getNumAudioDevices                = dll.NewProc("SDL_GetNumAudioDevices")
-	getNumAudioDrivers                = dll.NewProc("SDL_GetNumAudioDrivers")
-	getNumDisplayModes                = dll.NewProc("SDL_GetNumDisplayModes")
-	getNumRenderDrivers               = dll.NewProc("SDL_GetNumRenderDrivers")
-	getNumTouchDevices                = dll.NewProc("SDL_GetNumTouchDevices")
-	getNumTouchFingers                = dll.NewProc("SDL_GetNumTouchFingers")
-	getNumVideoDisplays               = dll.NewProc("SDL_GetNumVideoDisplays")
-	getNumVideoDrivers                = dll.NewProc("SDL_GetNumVideoDrivers")
-	getPerformanceCounter             = dll.NewProc("SDL_GetPerformanceCounter")
-	getPerformanceFrequency           = dll.NewProc("SDL_GetPerformanceFrequency")
-	getPixelFormatName                = dll.NewProc("SDL_GetPixelFormatName")
-	getPlatform                       = dll.NewProc("SDL_GetPlatform")
-	getPowerInfo                      = dll.NewProc("SDL_GetPowerInfo")
-	getPrefPath                       = dll.NewProc("SDL_GetPrefPath")
-	getQueuedAudioSize                = dll.NewProc("SDL_GetQueuedAudioSize")
-	getRGB                            = dll.NewProc("SDL_GetRGB")
-	getRGBA                           = dll.NewProc("SDL_GetRGBA")
-	getRelativeMouseMode              = dll.NewProc("SDL_GetRelativeMouseMode")
-	getRelativeMouseState             = dll.NewProc("SDL_GetRelativeMouseState")
-	getRenderDriverInfo               = dll.NewProc("SDL_GetRenderDriverInfo")
-	getRevision                       = dll.NewProc("SDL_GetRevision")
-	getRevisionNumber                 = dll.NewProc("SDL_GetRevisionNumber")
-	getScancodeName                   = dll.NewProc("SDL_GetScancodeName")
-	getSystemRAM                      = dll.NewProc("SDL_GetSystemRAM")
-	getTicks                          = dll.NewProc("SDL_GetTicks")
-	getVersion                        = dll.NewProc("SDL_GetVersion")
-	getVideoDriver                    = dll.NewProc("SDL_GetVideoDriver")
-	hapticIndex                       = dll.NewProc("SDL_HapticIndex")
-	hapticName                        = dll.NewProc("SDL_HapticName")
-	hapticOpened                      = dll.NewProc("SDL_HapticOpened")
-	has3DNow                          = dll.NewProc("SDL_Has3DNow")
-	hasAVX                            = dll.NewProc("SDL_HasAVX")
-	hasAVX2                           = dll.NewProc("SDL_HasAVX2")
-	hasAltiVec                        = dll.NewProc("SDL_HasAltiVec")
-	hasClipboardText                  = dll.NewProc("SDL_HasClipboardText")
-	hasEvent                          = dll.NewProc("SDL_HasEvent")
-	hasEvents                         = dll.NewProc("SDL_HasEvents")
-	hasMMX                            = dll.NewProc("SDL_HasMMX")
-	hasNEON                           = dll.NewProc("SDL_HasNEON")
-	hasRDTSC                          = dll.NewProc("SDL_HasRDTSC")
-	hasSSE                            = dll.NewProc("SDL_HasSSE")
-	hasSSE2                           = dll.NewProc("SDL_HasSSE2")
-	hasSSE3                           = dll.NewProc("SDL_HasSSE3")
-	hasSSE41                          = dll.NewProc("SDL_HasSSE41")
-	hasSSE42                          = dll.NewProc("SDL_HasSSE42")
-	hasScreenKeyboardSupport          = dll.NewProc("SDL_HasScreenKeyboardSupport")
-	sdlInit                           = dll.NewProc("SDL_Init")
-	initSubSystem                     = dll.NewProc("SDL_InitSubSystem")
-	isGameController                  = dll.NewProc("SDL_IsGameController")
-	isScreenKeyboardShown             = dll.NewProc("SDL_IsScreenKeyboardShown")
-	isScreenSaverEnabled              = dll.NewProc("SDL_IsScreenSaverEnabled")
-	isTextInputActive                 = dll.NewProc("SDL_IsTextInputActive")
-	joystickEventState                = dll.NewProc("SDL_JoystickEventState")
-	joystickGetDeviceProduct          = dll.NewProc("SDL_JoystickGetDeviceProduct")
-	joystickGetDeviceProductVersion   = dll.NewProc("SDL_JoystickGetDeviceProductVersion")
-	joystickGetDeviceVendor           = dll.NewProc("SDL_JoystickGetDeviceVendor")
-	joystickGetGUIDString             = dll.NewProc("SDL_JoystickGetGUIDString")
-	joystickIsHaptic                  = dll.NewProc("SDL_JoystickIsHaptic")
-	joystickNameForIndex              = dll.NewProc("SDL_JoystickNameForIndex")
-	joystickUpdate                    = dll.NewProc("SDL_JoystickUpdate")
-	loadDollarTemplates               = dll.NewProc("SDL_LoadDollarTemplates")
-	loadFile                          = dll.NewProc("SDL_LoadFile")
-	lockAudio                         = dll.NewProc("SDL_LockAudio")
-	lockAudioDevice                   = dll.NewProc("SDL_LockAudioDevice")
-	lockJoysticks                     = dll.NewProc("SDL_LockJoysticks")
-	log                               = dll.NewProc("SDL_Log")
-	logCritical                       = dll.NewProc("SDL_LogCritical")
-	logDebug                          = dll.NewProc("SDL_LogDebug")
-	logError                          = dll.NewProc("SDL_LogError")
-	logInfo                           = dll.NewProc("SDL_LogInfo")
-	logMessage                        = dll.NewProc("SDL_LogMessage")
-	logResetPriorities                = dll.NewProc("SDL_LogResetPriorities")
-	logSetAllPriority                 = dll.NewProc("SDL_LogSetAllPriority")
-	logSetOutputFunction              = dll.NewProc("SDL_LogSetOutputFunction")
-	logSetPriority                    = dll.NewProc("SDL_LogSetPriority")
-	logVerbose                        = dll.NewProc("SDL_LogVerbose")
-	logWarn                           = dll.NewProc("SDL_LogWarn")
-	mapRGB                            = dll.NewProc("SDL_MapRGB")
-	mapRGBA                           = dll.NewProc("SDL_MapRGBA")
-	masksToPixelFormatEnum            = dll.NewProc("SDL_MasksToPixelFormatEnum")
-	mixAudio                          = dll.NewProc("SDL_MixAudio")
-	mixAudioFormat                    = dll.NewProc("SDL_MixAudioFormat")
-	mouseIsHaptic                     = dll.NewProc("SDL_MouseIsHaptic")
-	numHaptics                        = dll.NewProc("SDL_NumHaptics")
-	numJoysticks                      = dll.NewProc("SDL_NumJoysticks")
-	numSensors                        = dll.NewProc("SDL_NumSensors")
-	openAudio                         = dll.NewProc("SDL_OpenAudio")
-	pauseAudio                        = dll.NewProc("SDL_PauseAudio")
-	pauseAudioDevice                  = dll.NewProc("SDL_PauseAudioDevice")
-	peepEvents                        = dll.NewProc("SDL_PeepEvents")
-	pixelFormatEnumToMasks            = dll.NewProc("SDL_PixelFormatEnumToMasks")
-	pumpEvents                        = dll.NewProc("SDL_PumpEvents")
-	pushEvent                         = dll.NewProc("SDL_PushEvent")
-	queueAudio                        = dll.NewProc("SDL_QueueAudio")
-	quit                              = dll.NewProc("SDL_Quit")
-	quitSubSystem                     = dll.NewProc("SDL_QuitSubSystem")
-	recordGesture                     = dll.NewProc("SDL_RecordGesture")
-	registerEvents                    = dll.NewProc("SDL_RegisterEvents")
-	saveAllDollarTemplates            = dll.NewProc("SDL_SaveAllDollarTemplates")
-	saveDollarTemplate                = dll.NewProc("SDL_SaveDollarTemplate")
-	sensorGetDeviceName               = dll.NewProc("SDL_SensorGetDeviceName")
-	sensorGetDeviceNonPortableType    = dll.NewProc("SDL_SensorGetDeviceNonPortableType")
-	sensorUpdate                      = dll.NewProc("SDL_SensorUpdate")
-	setClipboardText                  = dll.NewProc("SDL_SetClipboardText")
-	setCursor                         = dll.NewProc("SDL_SetCursor")
-	setError                          = dll.NewProc("SDL_SetError")
-	setEventFilter                    = dll.NewProc("SDL_SetEventFilter")
-	setHint                           = dll.NewProc("SDL_SetHint")
-	setHintWithPriority               = dll.NewProc("SDL_SetHintWithPriority")
-	setModState                       = dll.NewProc("SDL_SetModState")
-	setRelativeMouseMode              = dll.NewProc("SDL_SetRelativeMouseMode")
-	setTextInputRect                  = dll.NewProc("SDL_SetTextInputRect")
-	setYUVConversionMode              = dll.NewProc("SDL_SetYUVConversionMode")
-	showCursor                        = dll.NewProc("SDL_ShowCursor")
-	showMessageBox                    = dll.NewProc("SDL_ShowMessageBox")
-	showSimpleMessageBox              = dll.NewProc("SDL_ShowSimpleMessageBox")
-	startTextInput                    = dll.NewProc("SDL_StartTextInput")
-	stopTextInput                     = dll.NewProc("SDL_StopTextInput")
-	unlockAudio                       = dll.NewProc("SDL_UnlockAudio")
-	unlockAudioDevice                 = dll.NewProc("SDL_UnlockAudioDevice")
-	unlockJoysticks                   = dll.NewProc("SDL_UnlockJoysticks")
-	videoInit                         = dll.NewProc("SDL_VideoInit")
-	videoQuit                         = dll.NewProc("SDL_VideoQuit")
-	vulkan_GetVkGetInstanceProcAddr   = dll.NewProc("SDL_Vulkan_GetVkGetInstanceProcAddr")
-	vulkan_LoadLibrary                = dll.NewProc("SDL_Vulkan_LoadLibrary")
-	vulkan_UnloadLibrary              = dll.NewProc("SDL_Vulkan_UnloadLibrary")
-	warpMouseGlobal                   = dll.NewProc("SDL_WarpMouseGlobal")
-	wasInit                           = dll.NewProc("SDL_WasInit")
-	openAudioDevice                   = dll.NewProc("SDL_OpenAudioDevice")
-	loadWAV_RW                        = dll.NewProc("SDL_LoadWAV_RW")
-	getAudioDeviceStatus              = dll.NewProc("SDL_GetAudioDeviceStatus")
-	getAudioStatus                    = dll.NewProc("SDL_GetAudioStatus")
-	newAudioStream                    = dll.NewProc("SDL_NewAudioStream")
-	audioStreamAvailable              = dll.NewProc("SDL_AudioStreamAvailable")
-	audioStreamClear                  = dll.NewProc("SDL_AudioStreamClear")
-	audioStreamFlush                  = dll.NewProc("SDL_AudioStreamFlush")
-	freeAudioStream                   = dll.NewProc("SDL_FreeAudioStream")
-	audioStreamGet                    = dll.NewProc("SDL_AudioStreamGet")
-	audioStreamPut                    = dll.NewProc("SDL_AudioStreamPut")
-	composeCustomBlendMode            = dll.NewProc("SDL_ComposeCustomBlendMode")
-	createCond                        = dll.NewProc("SDL_CreateCond")
-	condBroadcast                     = dll.NewProc("SDL_CondBroadcast")
-	destroyCond                       = dll.NewProc("SDL_DestroyCond")
-	condSignal                        = dll.NewProc("SDL_CondSignal")
-	condWait                          = dll.NewProc("SDL_CondWait")
-	condWaitTimeout                   = dll.NewProc("SDL_CondWaitTimeout")
-	createColorCursor                 = dll.NewProc("SDL_CreateColorCursor")
-	createCursor                      = dll.NewProc("SDL_CreateCursor")
-	createSystemCursor                = dll.NewProc("SDL_CreateSystemCursor")
-	delHintCallback                   = dll.NewProc("SDL_DelHintCallback")
-	getCursor                         = dll.NewProc("SDL_GetCursor")
-	getDefaultCursor                  = dll.NewProc("SDL_GetDefaultCursor")
-	getClosestDisplayMode             = dll.NewProc("SDL_GetClosestDisplayMode")
-	getCurrentDisplayMode             = dll.NewProc("SDL_GetCurrentDisplayMode")
-	getDesktopDisplayMode             = dll.NewProc("SDL_GetDesktopDisplayMode")
-	getDisplayMode                    = dll.NewProc("SDL_GetDisplayMode")
-	pollEvent                         = dll.NewProc("SDL_PollEvent")
-	waitEvent                         = dll.NewProc("SDL_WaitEvent")
-	waitEventTimeout                  = dll.NewProc("SDL_WaitEventTimeout")
-	addEventWatch                     = dll.NewProc("SDL_AddEventWatch")
-	getTouchFinger                    = dll.NewProc("SDL_GetTouchFinger")
-	gameControllerFromInstanceID      = dll.NewProc("SDL_GameControllerFromInstanceID")
-	gameControllerOpen                = dll.NewProc("SDL_GameControllerOpen")
-	gameControllerGetAttached         = dll.NewProc("SDL_GameControllerGetAttached")
-	gameControllerGetAxis             = dll.NewProc("SDL_GameControllerGetAxis")
-	gameControllerGetBindForAxis      = dll.NewProc("SDL_GameControllerGetBindForAxis")
-	gameControllerGetBindForButton    = dll.NewProc("SDL_GameControllerGetBindForButton")
-	gameControllerGetButton           = dll.NewProc("SDL_GameControllerGetButton")
-	gameControllerClose               = dll.NewProc("SDL_GameControllerClose")
-	gameControllerGetJoystick         = dll.NewProc("SDL_GameControllerGetJoystick")
-	gameControllerMapping             = dll.NewProc("SDL_GameControllerMapping")
-	gameControllerName                = dll.NewProc("SDL_GameControllerName")
-	gameControllerGetProduct          = dll.NewProc("SDL_GameControllerGetProduct")
-	gameControllerGetProductVersion   = dll.NewProc("SDL_GameControllerGetProductVersion")
-	gameControllerGetVendor           = dll.NewProc("SDL_GameControllerGetVendor")
-	gameControllerGetAxisFromString   = dll.NewProc("SDL_GameControllerGetAxisFromString")
-	gameControllerGetButtonFromString = dll.NewProc("SDL_GameControllerGetButtonFromString")
-	hapticOpen                        = dll.NewProc("SDL_HapticOpen")
-	hapticOpenFromJoystick            = dll.NewProc("SDL_HapticOpenFromJoystick")
-	hapticOpenFromMouse               = dll.NewProc("SDL_HapticOpenFromMouse")
-	hapticClose                       = dll.NewProc("SDL_HapticClose")
-	hapticDestroyEffect               = dll.NewProc("SDL_HapticDestroyEffect")
-	hapticEffectSupported             = dll.NewProc("SDL_HapticEffectSupported")
-	hapticGetEffectStatus             = dll.NewProc("SDL_HapticGetEffectStatus")
-	hapticNewEffect                   = dll.NewProc("SDL_HapticNewEffect")
-	hapticNumAxes                     = dll.NewProc("SDL_HapticNumAxes")
-	hapticNumEffects                  = dll.NewProc("SDL_HapticNumEffects")
-	hapticNumEffectsPlaying           = dll.NewProc("SDL_HapticNumEffectsPlaying")
-	hapticPause                       = dll.NewProc("SDL_HapticPause")
-	hapticQuery                       = dll.NewProc("SDL_HapticQuery")
-	hapticRumbleInit                  = dll.NewProc("SDL_HapticRumbleInit")
-	hapticRumblePlay                  = dll.NewProc("SDL_HapticRumblePlay")
-	hapticRumbleStop                  = dll.NewProc("SDL_HapticRumbleStop")
-	hapticRumbleSupported             = dll.NewProc("SDL_HapticRumbleSupported")
-	hapticRunEffect                   = dll.NewProc("SDL_HapticRunEffect")
-	hapticSetAutocenter               = dll.NewProc("SDL_HapticSetAutocenter")
-	hapticSetGain                     = dll.NewProc("SDL_HapticSetGain")
-	hapticStopAll                     = dll.NewProc("SDL_HapticStopAll")
-	hapticStopEffect                  = dll.NewProc("SDL_HapticStopEffect")
-	hapticUnpause                     = dll.NewProc("SDL_HapticUnpause")
-	hapticUpdateEffect                = dll.NewProc("SDL_HapticUpdateEffect")
-	joystickFromInstanceID            = dll.NewProc("SDL_JoystickFromInstanceID")
-	joystickOpen                      = dll.NewProc("SDL_JoystickOpen")
-	joystickGetAttached               = dll.NewProc("SDL_JoystickGetAttached")
-	joystickGetAxis                   = dll.NewProc("SDL_JoystickGetAxis")
-	joystickGetAxisInitialState       = dll.NewProc("SDL_JoystickGetAxisInitialState")
-	joystickGetBall                   = dll.NewProc("SDL_JoystickGetBall")
-	joystickGetButton                 = dll.NewProc("SDL_JoystickGetButton")
-	joystickClose                     = dll.NewProc("SDL_JoystickClose")
-	joystickCurrentPowerLevel         = dll.NewProc("SDL_JoystickCurrentPowerLevel")
-	joystickGetGUID                   = dll.NewProc("SDL_JoystickGetGUID")
-	joystickGetHat                    = dll.NewProc("SDL_JoystickGetHat")
-	joystickInstanceID                = dll.NewProc("SDL_JoystickInstanceID")
-	joystickName                      = dll.NewProc("SDL_JoystickName")
-	joystickNumAxes                   = dll.NewProc("SDL_JoystickNumAxes")
-	joystickNumBalls                  = dll.NewProc("SDL_JoystickNumBalls")
-	joystickNumButtons                = dll.NewProc("SDL_JoystickNumButtons")
-	joystickNumHats                   = dll.NewProc("SDL_JoystickNumHats")
-	joystickGetProduct                = dll.NewProc("SDL_JoystickGetProduct")
-	joystickGetProductVersion         = dll.NewProc("SDL_JoystickGetProductVersion")
-	joystickGetType                   = dll.NewProc("SDL_JoystickGetType")
-	joystickGetVendor                 = dll.NewProc("SDL_JoystickGetVendor")
-	joystickGetDeviceGUID             = dll.NewProc("SDL_JoystickGetDeviceGUID")
-	joystickGetGUIDFromString         = dll.NewProc("SDL_JoystickGetGUIDFromString")
-	joystickGetDeviceInstanceID       = dll.NewProc("SDL_JoystickGetDeviceInstanceID")
-	joystickGetDeviceType             = dll.NewProc("SDL_JoystickGetDeviceType")
-	getKeyFromName                    = dll.NewProc("SDL_GetKeyFromName")
-	getKeyFromScancode                = dll.NewProc("SDL_GetKeyFromScancode")
-	getModState                       = dll.NewProc("SDL_GetModState")
-	logGetPriority                    = dll.NewProc("SDL_LogGetPriority")
-	createMutex                       = dll.NewProc("SDL_CreateMutex")
-	destroyMutex                      = dll.NewProc("SDL_DestroyMutex")
-	lockMutex                         = dll.NewProc("SDL_LockMutex")
-	tryLockMutex                      = dll.NewProc("SDL_TryLockMutex")
-	unlockMutex                       = dll.NewProc("SDL_UnlockMutex")
-	allocPalette                      = dll.NewProc("SDL_AllocPalette")
-	freePalette                       = dll.NewProc("SDL_FreePalette")
-	setPaletteColors                  = dll.NewProc("SDL_SetPaletteColors")
-	allocFormat                       = dll.NewProc("SDL_AllocFormat")
-	freeFormat                        = dll.NewProc("SDL_FreeFormat")
-	setPixelFormatPalette             = dll.NewProc("SDL_SetPixelFormatPalette")
-	allocRW                           = dll.NewProc("SDL_AllocRW")
-	rwFromFile                        = dll.NewProc("SDL_RWFromFile")
-	rwFromMem                         = dll.NewProc("SDL_RWFromMem")
-	rwClose                           = dll.NewProc("RWclose")
-	freeRW                            = dll.NewProc("SDL_FreeRW")
-	loadFile_RW                       = dll.NewProc("SDL_LoadFile_RW")
-	readBE16                          = dll.NewProc("SDL_ReadBE16")
-	readBE32                          = dll.NewProc("SDL_ReadBE32")
-	readBE64                          = dll.NewProc("SDL_ReadBE64")
-	readLE16                          = dll.NewProc("SDL_ReadLE16")
-	readLE32                          = dll.NewProc("SDL_ReadLE32")
-	readLE64                          = dll.NewProc("SDL_ReadLE64")
-	readU8                            = dll.NewProc("SDL_ReadU8")
-	writeBE16                         = dll.NewProc("SDL_WriteBE16")
-	writeBE32                         = dll.NewProc("SDL_WriteBE32")
-	writeBE64                         = dll.NewProc("SDL_WriteBE64")
-	writeLE16                         = dll.NewProc("SDL_WriteLE16")
-	writeLE32                         = dll.NewProc("SDL_WriteLE32")
-	writeLE64                         = dll.NewProc("SDL_WriteLE64")
-	writeU8                           = dll.NewProc("SDL_WriteU8")
-	getDisplayBounds                  = dll.NewProc("SDL_GetDisplayBounds")
-	getDisplayUsableBounds            = dll.NewProc("SDL_GetDisplayUsableBounds")
-	createRenderer                    = dll.NewProc("SDL_CreateRenderer")
-	createSoftwareRenderer            = dll.NewProc("SDL_CreateSoftwareRenderer")
-	renderClear                       = dll.NewProc("SDL_RenderClear")
-	renderCopy                        = dll.NewProc("SDL_RenderCopy")
-	renderCopyF                       = dll.NewProc("SDL_RenderCopyF")
-	renderCopyEx                      = dll.NewProc("SDL_RenderCopyEx")
-	renderCopyExF                     = dll.NewProc("SDL_RenderCopyExF")
-	createTexture                     = dll.NewProc("SDL_CreateTexture")
-	createTextureFromSurface          = dll.NewProc("SDL_CreateTextureFromSurface")
-	destroyRenderer                   = dll.NewProc("SDL_DestroyRenderer")
-	renderDrawLine                    = dll.NewProc("SDL_RenderDrawLine")
-	renderDrawLineF                   = dll.NewProc("SDL_RenderDrawLineF")
-	renderDrawLines                   = dll.NewProc("SDL_RenderDrawLines")
-	renderDrawLinesF                  = dll.NewProc("SDL_RenderDrawLinesF")
-	renderDrawPoint                   = dll.NewProc("SDL_RenderDrawPoint")
-	renderDrawPointF                  = dll.NewProc("SDL_RenderDrawPointF")
-	renderDrawPoints                  = dll.NewProc("SDL_RenderDrawPoints")
-	renderDrawPointsF                 = dll.NewProc("SDL_RenderDrawPointsF")
-	renderDrawRect                    = dll.NewProc("SDL_RenderDrawRect")
-	renderDrawRectF                   = dll.NewProc("SDL_RenderDrawRectF")
-	renderDrawRects                   = dll.NewProc("SDL_RenderDrawRects")
-	renderDrawRectsF                  = dll.NewProc("SDL_RenderDrawRectsF")
-	renderFillRect                    = dll.NewProc("SDL_RenderFillRect")
-	renderFillRectF                   = dll.NewProc("SDL_RenderFillRectF")
-	renderFillRects                   = dll.NewProc("SDL_RenderFillRects")
-	renderFillRectsF                  = dll.NewProc("SDL_RenderFillRectsF")
-	renderFlush                       = dll.NewProc("SDL_RenderFlush")
-	renderGetClipRect                 = dll.NewProc("SDL_RenderGetClipRect")
-	getRenderDrawBlendMode            = dll.NewProc("SDL_GetRenderDrawBlendMode")
-	getRenderDrawColor                = dll.NewProc("SDL_GetRenderDrawColor")
-	getRendererInfo                   = dll.NewProc("SDL_GetRendererInfo")
-	renderGetIntegerScale             = dll.NewProc("SDL_RenderGetIntegerScale")
-	renderGetLogicalSize              = dll.NewProc("SDL_RenderGetLogicalSize")
-	renderGetMetalCommandEncoder      = dll.NewProc("SDL_RenderGetMetalCommandEncoder")
-	renderGetMetalLayer               = dll.NewProc("SDL_RenderGetMetalLayer")
-	getRendererOutputSize             = dll.NewProc("SDL_GetRendererOutputSize")
-	getRenderTarget                   = dll.NewProc("SDL_GetRenderTarget")
-	renderGetScale                    = dll.NewProc("SDL_RenderGetScale")
-	renderGetViewport                 = dll.NewProc("SDL_RenderGetViewport")
-	renderPresent                     = dll.NewProc("SDL_RenderPresent")
-	renderReadPixels                  = dll.NewProc("SDL_RenderReadPixels")
-	renderTargetSupported             = dll.NewProc("SDL_RenderTargetSupported")
-	renderSetClipRect                 = dll.NewProc("SDL_RenderSetClipRect")
-	setRenderDrawBlendMode            = dll.NewProc("SDL_SetRenderDrawBlendMode")
-	setRenderDrawColor                = dll.NewProc("SDL_SetRenderDrawColor")
-	renderSetIntegerScale             = dll.NewProc("SDL_RenderSetIntegerScale")
-	renderSetLogicalSize              = dll.NewProc("SDL_RenderSetLogicalSize")
-	setRenderTarget                   = dll.NewProc("SDL_SetRenderTarget")
-	renderSetScale                    = dll.NewProc("SDL_RenderSetScale")
-	renderSetViewport                 = dll.NewProc("SDL_RenderSetViewport")
-	getScancodeFromKey                = dll.NewProc("SDL_GetScancodeFromKey")
-	getScancodeFromName               = dll.NewProc("SDL_GetScancodeFromName")
-	createSemaphore                   = dll.NewProc("SDL_CreateSemaphore")
-	destroySemaphore                  = dll.NewProc("SDL_DestroySemaphore")
-	semPost                           = dll.NewProc("SDL_SemPost")
-	semTryWait                        = dll.NewProc("SDL_SemTryWait")
-	semValue                          = dll.NewProc("SDL_SemValue")
-	semWait                           = dll.NewProc("SDL_SemWait")
-	semWaitTimeout                    = dll.NewProc("SDL_SemWaitTimeout")
-	sensorFromInstanceID              = dll.NewProc("SDL_SensorFromInstanceID")
-	sensorOpen                        = dll.NewProc("SDL_SensorOpen")
-	sensorClose                       = dll.NewProc("SDL_SensorClose")
-	sensorGetData                     = dll.NewProc("SDL_SensorGetData")
-	sensorGetInstanceID               = dll.NewProc("SDL_SensorGetInstanceID")
-	sensorGetName                     = dll.NewProc("SDL_SensorGetName")
-	sensorGetNonPortableType          = dll.NewProc("SDL_SensorGetNonPortableType")
-	sensorGetType                     = dll.NewProc("SDL_SensorGetType")
-	sensorGetDeviceInstanceID         = dll.NewProc("SDL_SensorGetDeviceInstanceID")
-	sensorGetDeviceType               = dll.NewProc("SDL_SensorGetDeviceType")
-	loadObject                        = dll.NewProc("SDL_LoadObject")
-	loadFunction                      = dll.NewProc("SDL_LoadFunction")
-	unloadObject                      = dll.NewProc("SDL_UnloadObject")
-	simdAlloc                         = dll.NewProc("SDL_SIMDAlloc")
-	simdFree                          = dll.NewProc("SDL_SIMDFree")
-	simdGetAlignment                  = dll.NewProc("SDL_SIMDGetAlignment")
-	createRGBSurface                  = dll.NewProc("SDL_CreateRGBSurface")
-	createRGBSurfaceFrom              = dll.NewProc("SDL_CreateRGBSurfaceFrom")
-	createRGBSurfaceWithFormat        = dll.NewProc("SDL_CreateRGBSurfaceWithFormat")
-	createRGBSurfaceWithFormatFrom    = dll.NewProc("SDL_CreateRGBSurfaceWithFormatFrom")
-	loadBMP_RW                        = dll.NewProc("SDL_LoadBMP_RW")
-	blitSurface                       = dll.NewProc("SDL_BlitSurface")
-	blitScaled                        = dll.NewProc("SDL_BlitScaled")
-	convertSurface                    = dll.NewProc("SDL_ConvertSurface")
-	convertSurfaceFormat              = dll.NewProc("SDL_ConvertSurfaceFormat")
-	duplicateSurface                  = dll.NewProc("SDL_DuplicateSurface")
-	fillRect                          = dll.NewProc("SDL_FillRect")
-	fillRects                         = dll.NewProc("SDL_FillRects")
-	freeSurface                       = dll.NewProc("SDL_FreeSurface")
-	getSurfaceAlphaMod                = dll.NewProc("SDL_GetSurfaceAlphaMod")
-	getSurfaceBlendMode               = dll.NewProc("SDL_GetSurfaceBlendMode")
-	getClipRect                       = dll.NewProc("SDL_GetClipRect")
-	getColorKey                       = dll.NewProc("SDL_GetColorKey")
-	getSurfaceColorMod                = dll.NewProc("SDL_GetSurfaceColorMod")
-	lockSurface                       = dll.NewProc("SDL_LockSurface")
-	lowerBlit                         = dll.NewProc("SDL_LowerBlit")
-	lowerBlitScaled                   = dll.NewProc("SDL_LowerBlitScaled")
-	saveBMP_RW                        = dll.NewProc("SDL_SaveBMP_RW")
-	setSurfaceAlphaMod                = dll.NewProc("SDL_SetSurfaceAlphaMod")
-	setSurfaceBlendMode               = dll.NewProc("SDL_SetSurfaceBlendMode")
-	setClipRect                       = dll.NewProc("SDL_SetClipRect")
-	setColorKey                       = dll.NewProc("SDL_SetColorKey")
-	setSurfaceColorMod                = dll.NewProc("SDL_SetSurfaceColorMod")
-	setSurfacePalette                 = dll.NewProc("SDL_SetSurfacePalette")
-	setSurfaceRLE                     = dll.NewProc("SDL_SetSurfaceRLE")
-	softStretch                       = dll.NewProc("SDL_SoftStretch")
-	unlockSurface                     = dll.NewProc("SDL_UnlockSurface")
-	upperBlit                         = dll.NewProc("SDL_UpperBlit")
-	upperBlitScaled                   = dll.NewProc("SDL_UpperBlitScaled")
-	destroyTexture                    = dll.NewProc("SDL_DestroyTexture")
-	gl_BindTexture                    = dll.NewProc("SDL_GL_BindTexture")
-	gl_UnbindTexture                  = dll.NewProc("SDL_GL_UnbindTexture")
-	getTextureAlphaMod                = dll.NewProc("SDL_GetTextureAlphaMod")
-	getTextureBlendMode               = dll.NewProc("SDL_GetTextureBlendMode")
-	lockTexture                       = dll.NewProc("SDL_LockTexture")
-	queryTexture                      = dll.NewProc("SDL_QueryTexture")
-	setTextureAlphaMod                = dll.NewProc("SDL_SetTextureAlphaMod")
-	setTextureBlendMode               = dll.NewProc("SDL_SetTextureBlendMode")
-	setTextureColorMod                = dll.NewProc("SDL_SetTextureColorMod")
-	unlockTexture                     = dll.NewProc("SDL_UnlockTexture")
-	updateTexture                     = dll.NewProc("SDL_UpdateTexture")
-	updateYUVTexture                  = dll.NewProc("SDL_UpdateYUVTexture")
-	getTouchDevice                    = dll.NewProc("SDL_GetTouchDevice")
-	getTouchDeviceType                = dll.NewProc("SDL_GetTouchDeviceType")
-	createWindow                      = dll.NewProc("SDL_CreateWindow")
-	createWindowFrom                  = dll.NewProc("SDL_CreateWindowFrom")
-	getKeyboardFocus                  = dll.NewProc("SDL_GetKeyboardFocus")
-	getMouseFocus                     = dll.NewProc("SDL_GetMouseFocus")
-	getWindowFromID                   = dll.NewProc("SDL_GetWindowFromID")
-	destroyWindow                     = dll.NewProc("SDL_DestroyWindow")
-	gl_CreateContext                  = dll.NewProc("SDL_GL_CreateContext")
-	gl_GetDrawableSize                = dll.NewProc("SDL_GL_GetDrawableSize")
-	gl_MakeCurrent                    = dll.NewProc("SDL_GL_MakeCurrent")
-	gl_SwapWindow                     = dll.NewProc("SDL_GL_SwapWindow")
-	getWindowBrightness               = dll.NewProc("SDL_GetWindowBrightness")
-	getWindowData                     = dll.NewProc("SDL_GetWindowData")
-	getWindowDisplayIndex             = dll.NewProc("SDL_GetWindowDisplayIndex")
-	getWindowDisplayMode              = dll.NewProc("SDL_GetWindowDisplayMode")
-	getWindowFlags                    = dll.NewProc("SDL_GetWindowFlags")
-	getWindowGammaRamp                = dll.NewProc("SDL_GetWindowGammaRamp")
-	getWindowGrab                     = dll.NewProc("SDL_GetWindowGrab")
-	getWindowID                       = dll.NewProc("SDL_GetWindowID")
-	getWindowMaximumSize              = dll.NewProc("SDL_GetWindowMaximumSize")
-	getWindowMinimumSize              = dll.NewProc("SDL_GetWindowMinimumSize")
-	getWindowPixelFormat              = dll.NewProc("SDL_GetWindowPixelFormat")
-	getWindowPosition                 = dll.NewProc("SDL_GetWindowPosition")
-	getRenderer                       = dll.NewProc("SDL_GetRenderer")
-	getWindowSize                     = dll.NewProc("SDL_GetWindowSize")
-	getWindowSurface                  = dll.NewProc("SDL_GetWindowSurface")
-	getWindowTitle                    = dll.NewProc("SDL_GetWindowTitle")
-	getWindowWMInfo                   = dll.NewProc("SDL_GetWindowWMInfo")
-	getWindowOpacity                  = dll.NewProc("SDL_GetWindowOpacity")
-	hideWindow                        = dll.NewProc("SDL_HideWindow")
-	maximizeWindow                    = dll.NewProc("SDL_MaximizeWindow")
-	minimizeWindow                    = dll.NewProc("SDL_MinimizeWindow")
-	raiseWindow                       = dll.NewProc("SDL_RaiseWindow")
-	restoreWindow                     = dll.NewProc("SDL_RestoreWindow")
-	setWindowBordered                 = dll.NewProc("SDL_SetWindowBordered")
-	setWindowBrightness               = dll.NewProc("SDL_SetWindowBrightness")
-	setWindowData                     = dll.NewProc("SDL_SetWindowData")
-	setWindowDisplayMode              = dll.NewProc("SDL_SetWindowDisplayMode")
-	setWindowFullscreen               = dll.NewProc("SDL_SetWindowFullscreen")
-	setWindowGammaRamp                = dll.NewProc("SDL_SetWindowGammaRamp")
-	setWindowGrab                     = dll.NewProc("SDL_SetWindowGrab")
-	setWindowIcon                     = dll.NewProc("SDL_SetWindowIcon")
-	setWindowMaximumSize              = dll.NewProc("SDL_SetWindowMaximumSize")
-	setWindowMinimumSize              = dll.NewProc("SDL_SetWindowMinimumSize")
-	setWindowPosition                 = dll.NewProc("SDL_SetWindowPosition")
-	setWindowResizable                = dll.NewProc("SDL_SetWindowResizable")
-	setWindowSize                     = dll.NewProc("SDL_SetWindowSize")
-	setWindowTitle                    = dll.NewProc("SDL_SetWindowTitle")
-	setWindowOpacity                  = dll.NewProc("SDL_SetWindowOpacity")
-	showWindow                        = dll.NewProc("SDL_ShowWindow")
-	updateWindowSurface               = dll.NewProc("SDL_UpdateWindowSurface")
-	updateWindowSurfaceRects          = dll.NewProc("SDL_UpdateWindowSurfaceRects")
-	vulkan_GetDrawableSize            = dll.NewProc("SDL_Vulkan_GetDrawableSize")
-	vulkan_GetInstanceExtensions      = dll.NewProc("SDL_Vulkan_GetInstanceExtensions")
-	warpMouseInWindow                 = dll.NewProc("SDL_WarpMouseInWindow")
-	getYUVConversionMode              = dll.NewProc("SDL_GetYUVConversionMode")
-	getYUVConversionModeForResolution = dll.NewProc("SDL_GetYUVConversionModeForResolution")
+	ErrOutOfMemory     = &CallError{Msg: "Out of memory"}
+	ErrUnsupported     = &CallError{Msg: "That operation is not supported"}
+	ErrInvalidRenderer = &CallError{Msg: "Invalid renderer"}
+	ErrInvalidTexture  = &CallError{Msg: "Invalid texture"}
+	ErrInvalidWindow   = &CallError{Msg: "Invalid window"}
+)
+
+var (
+	dll = lazyDLL{syscall.NewLazyDLL("SDL2.dll")}
+
+	addHintCallback                     = dll.NewProc("SDL_AddHintCallback")
+	audioInit                           = dll.NewProc("SDL_AudioInit")
+	audioQuit                           = dll.NewProc("SDL_AudioQuit")
+	buildAudioCVT                       = dll.NewProc("SDL_BuildAudioCVT")
+	calculateGammaRamp                  = dll.NewProc("SDL_CalculateGammaRamp")
+	captureMouse                        = dll.NewProc("SDL_CaptureMouse")
+	clearError                          = dll.NewProc("SDL_ClearError")
+	getAssertionReport                  = dll.NewProc("SDL_GetAssertionReport")
+	resetAssertionReport                = dll.NewProc("SDL_ResetAssertionReport")
+	setAssertionHandler                 = dll.NewProc("SDL_SetAssertionHandler")
+	clearHints                          = dll.NewProc("SDL_ClearHints")
+	clearQueuedAudio                    = dll.NewProc("SDL_ClearQueuedAudio")
+	getError                            = dll.NewProc("SDL_GetError")
+	closeAudio                          = dll.NewProc("SDL_CloseAudio")
+	closeAudioDevice                    = dll.NewProc("SDL_CloseAudioDevice")
+	convertAudio                        = dll.NewProc("SDL_ConvertAudio")
+	convertPixels                       = dll.NewProc("SDL_ConvertPixels")
+	createWindowAndRenderer             = dll.NewProc("SDL_CreateWindowAndRenderer")
+	threadID                            = dll.NewProc("SDL_ThreadID")
+	delEventWatch                       = dll.NewProc("SDL_DelEventWatch")
+	delay                               = dll.NewProc("SDL_Delay")
+	dequeueAudio                        = dll.NewProc("SDL_DequeueAudio")
+	disableScreenSaver                  = dll.NewProc("SDL_DisableScreenSaver")
+	enableScreenSaver                   = dll.NewProc("SDL_EnableScreenSaver")
+	sdlError                            = dll.NewProc("SDL_Error")
+	flushEvent                          = dll.NewProc("SDL_FlushEvent")
+	flushEvents                         = dll.NewProc("SDL_FlushEvents")
+	freeCursor                          = dll.NewProc("SDL_FreeCursor")
+	freeWAV                             = dll.NewProc("SDL_FreeWAV")
+	gl_DeleteContext                    = dll.NewProc("SDL_GL_DeleteContext")
+	gl_ExtensionSupported               = dll.NewProc("SDL_GL_ExtensionSupported")
+	gl_GetAttribute                     = dll.NewProc("SDL_GL_GetAttribute")
+	gl_GetProcAddress                   = dll.NewProc("SDL_GL_GetProcAddress")
+	gl_GetSwapInterval                  = dll.NewProc("SDL_GL_GetSwapInterval")
+	gl_LoadLibrary                      = dll.NewProc("SDL_GL_LoadLibrary")
+	gl_SetAttribute                     = dll.NewProc("SDL_GL_SetAttribute")
+	gl_SetSwapInterval                  = dll.NewProc("SDL_GL_SetSwapInterval")
+	gl_UnloadLibrary                    = dll.NewProc("SDL_GL_UnloadLibrary")
+	gameControllerAddMapping            = dll.NewProc("SDL_GameControllerAddMapping")
+	gameControllerAddMappingsFromRW     = dll.NewProc("SDL_GameControllerAddMappingsFromRW")
+	gameControllerEventState            = dll.NewProc("SDL_GameControllerEventState")
+	gameControllerGetStringForAxis      = dll.NewProc("SDL_GameControllerGetStringForAxis")
+	gameControllerGetStringForButton    = dll.NewProc("SDL_GameControllerGetStringForButton")
+	gameControllerMappingForGUID        = dll.NewProc("SDL_GameControllerMappingForGUID")
+	gameControllerMappingForIndex       = dll.NewProc("SDL_GameControllerMappingForIndex")
+	gameControllerMappingForDeviceIndex = dll.NewProc("SDL_GameControllerMappingForDeviceIndex")
+	gameControllerNameForIndex          = dll.NewProc("SDL_GameControllerNameForIndex")
+	gameControllerNumMappings           = dll.NewProc("SDL_GameControllerNumMappings")
+	gameControllerUpdate                = dll.NewProc("SDL_GameControllerUpdate")
+	getAudioDeviceName                  = dll.NewProc("SDL_GetAudioDeviceName")
+	getAudioDriver                      = dll.NewProc("SDL_GetAudioDriver")
+	getBasePath                         = dll.NewProc("SDL_GetBasePath")
+	getCPUCacheLineSize                 = dll.NewProc("SDL_GetCPUCacheLineSize")
+	getCPUCount                         = dll.NewProc("SDL_GetCPUCount")
+	getClipboardText                    = dll.NewProc("SDL_GetClipboardText")
+	getCurrentAudioDriver               = dll.NewProc("SDL_GetCurrentAudioDriver")
+	getCurrentVideoDriver               = dll.NewProc("SDL_GetCurrentVideoDriver")
+	getDisplayDPI                       = dll.NewProc("SDL_GetDisplayDPI")
+	getDisplayName                      = dll.NewProc("SDL_GetDisplayName")
+	eventState                          = dll.NewProc("SDL_EventState")
+	getGlobalMouseState                 = dll.NewProc("SDL_GetGlobalMouseState")
+	filterEvents                        = dll.NewProc("SDL_FilterEvents")
+	getHint                             = dll.NewProc("SDL_GetHint")
+	getKeyName                          = dll.NewProc("SDL_GetKeyName")
+	getKeyboardState                    = dll.NewProc("SDL_GetKeyboardState")
+	getMouseState                       = dll.NewProc("SDL_GetMouseState")
+	getNumAudioDevices                  = dll.NewProc("SDL_GetNumAudioDevices")
+	getNumAudioDrivers                  = dll.NewProc("SDL_GetNumAudioDrivers")
+	getNumDisplayModes                  = dll.NewProc("SDL_GetNumDisplayModes")
+	getNumRenderDrivers                 = dll.NewProc("SDL_GetNumRenderDrivers")
+	getNumTouchDevices                  = dll.NewProc("SDL_GetNumTouchDevices")
+	getNumTouchFingers                  = dll.NewProc("SDL_GetNumTouchFingers")
+	getNumVideoDisplays                 = dll.NewProc("SDL_GetNumVideoDisplays")
+	getNumVideoDrivers                  = dll.NewProc("SDL_GetNumVideoDrivers")
+	getPerformanceCounter               = dll.NewProc("SDL_GetPerformanceCounter")
+	getPerformanceFrequency             = dll.NewProc("SDL_GetPerformanceFrequency")
+	getPixelFormatName                  = dll.NewProc("SDL_GetPixelFormatName")
+	getPlatform                         = dll.NewProc("SDL_GetPlatform")
+	getPowerInfo                        = dll.NewProc("SDL_GetPowerInfo")
+	getPrefPath                         = dll.NewProc("SDL_GetPrefPath")
+	getQueuedAudioSize                  = dll.NewProc("SDL_GetQueuedAudioSize")
+	getRGB                              = dll.NewProc("SDL_GetRGB")
+	getRGBA                             = dll.NewProc("SDL_GetRGBA")
+	getRelativeMouseMode                = dll.NewProc("SDL_GetRelativeMouseMode")
+	getRelativeMouseState               = dll.NewProc("SDL_GetRelativeMouseState")
+	getRenderDriverInfo                 = dll.NewProc("SDL_GetRenderDriverInfo")
+	getRevision                         = dll.NewProc("SDL_GetRevision")
+	getRevisionNumber                   = dll.NewProc("SDL_GetRevisionNumber")
+	getScancodeName                     = dll.NewProc("SDL_GetScancodeName")
+	getSystemRAM                        = dll.NewProc("SDL_GetSystemRAM")
+	getTicks                            = dll.NewProc("SDL_GetTicks")
+	getVersion                          = dll.NewProc("SDL_GetVersion")
+	getVideoDriver                      = dll.NewProc("SDL_GetVideoDriver")
+	hapticIndex                         = dll.NewProc("SDL_HapticIndex")
+	hapticName                          = dll.NewProc("SDL_HapticName")
+	hapticOpened                        = dll.NewProc("SDL_HapticOpened")
+	has3DNow                            = dll.NewProc("SDL_Has3DNow")
+	hasAVX                              = dll.NewProc("SDL_HasAVX")
+	hasAVX2                             = dll.NewProc("SDL_HasAVX2")
+	hasAltiVec                          = dll.NewProc("SDL_HasAltiVec")
+	hasClipboardText                    = dll.NewProc("SDL_HasClipboardText")
+	hasEvent                            = dll.NewProc("SDL_HasEvent")
+	hasEvents                           = dll.NewProc("SDL_HasEvents")
+	hasMMX                              = dll.NewProc("SDL_HasMMX")
+	hasNEON                             = dll.NewProc("SDL_HasNEON")
+	hasRDTSC                            = dll.NewProc("SDL_HasRDTSC")
+	hasSSE                              = dll.NewProc("SDL_HasSSE")
+	hasSSE2                             = dll.NewProc("SDL_HasSSE2")
+	hasSSE3                             = dll.NewProc("SDL_HasSSE3")
+	hasSSE41                            = dll.NewProc("SDL_HasSSE41")
+	hasSSE42                            = dll.NewProc("SDL_HasSSE42")
+	hasScreenKeyboardSupport            = dll.NewProc("SDL_HasScreenKeyboardSupport")
+	sdlInit                             = dll.NewProc("SDL_Init")
+	initSubSystem                       = dll.NewProc("SDL_InitSubSystem")
+	isGameController                    = dll.NewProc("SDL_IsGameController")
+	isScreenKeyboardShown               = dll.NewProc("SDL_IsScreenKeyboardShown")
+	isScreenSaverEnabled                = dll.NewProc("SDL_IsScreenSaverEnabled")
+	isTextInputActive                   = dll.NewProc("SDL_IsTextInputActive")
+	joystickEventState                  = dll.NewProc("SDL_JoystickEventState")
+	joystickGetDeviceProduct            = dll.NewProc("SDL_JoystickGetDeviceProduct")
+	joystickGetDeviceProductVersion     = dll.NewProc("SDL_JoystickGetDeviceProductVersion")
+	joystickGetDeviceVendor             = dll.NewProc("SDL_JoystickGetDeviceVendor")
+	joystickGetGUIDString               = dll.NewProc("SDL_JoystickGetGUIDString")
+	joystickIsHaptic                    = dll.NewProc("SDL_JoystickIsHaptic")
+	joystickNameForIndex                = dll.NewProc("SDL_JoystickNameForIndex")
+	joystickUpdate                      = dll.NewProc("SDL_JoystickUpdate")
+	loadDollarTemplates                 = dll.NewProc("SDL_LoadDollarTemplates")
+	loadFile                            = dll.NewProc("SDL_LoadFile")
+	lockAudio                           = dll.NewProc("SDL_LockAudio")
+	lockAudioDevice                     = dll.NewProc("SDL_LockAudioDevice")
+	lockJoysticks                       = dll.NewProc("SDL_LockJoysticks")
+	log                                 = dll.NewProc("SDL_Log")
+	logCritical                         = dll.NewProc("SDL_LogCritical")
+	logDebug                            = dll.NewProc("SDL_LogDebug")
+	logError                            = dll.NewProc("SDL_LogError")
+	logGetOutputFunction                = dll.NewProc("SDL_LogGetOutputFunction")
+	logInfo                             = dll.NewProc("SDL_LogInfo")
+	logMessage                          = dll.NewProc("SDL_LogMessage")
+	logResetPriorities                  = dll.NewProc("SDL_LogResetPriorities")
+	logSetAllPriority                   = dll.NewProc("SDL_LogSetAllPriority")
+	logSetOutputFunction                = dll.NewProc("SDL_LogSetOutputFunction")
+	logSetPriority                      = dll.NewProc("SDL_LogSetPriority")
+	logVerbose                          = dll.NewProc("SDL_LogVerbose")
+	logWarn                             = dll.NewProc("SDL_LogWarn")
+	mapRGB                              = dll.NewProc("SDL_MapRGB")
+	mapRGBA                             = dll.NewProc("SDL_MapRGBA")
+	masksToPixelFormatEnum              = dll.NewProc("SDL_MasksToPixelFormatEnum")
+	mixAudio                            = dll.NewProc("SDL_MixAudio")
+	mixAudioFormat                      = dll.NewProc("SDL_MixAudioFormat")
+	mouseIsHaptic                       = dll.NewProc("SDL_MouseIsHaptic")
+	numHaptics                          = dll.NewProc("SDL_NumHaptics")
+	numJoysticks                        = dll.NewProc("SDL_NumJoysticks")
+	numSensors                          = dll.NewProc("SDL_NumSensors")
+	openAudio                           = dll.NewProc("SDL_OpenAudio")
+	pauseAudio                          = dll.NewProc("SDL_PauseAudio")
+	pauseAudioDevice                    = dll.NewProc("SDL_PauseAudioDevice")
+	peepEvents                          = dll.NewProc("SDL_PeepEvents")
+	pixelFormatEnumToMasks              = dll.NewProc("SDL_PixelFormatEnumToMasks")
+	pumpEvents                          = dll.NewProc("SDL_PumpEvents")
+	pushEvent                           = dll.NewProc("SDL_PushEvent")
+	queueAudio                          = dll.NewProc("SDL_QueueAudio")
+	quit                                = dll.NewProc("SDL_Quit")
+	quitSubSystem                       = dll.NewProc("SDL_QuitSubSystem")
+	recordGesture                       = dll.NewProc("SDL_RecordGesture")
+	registerEvents                      = dll.NewProc("SDL_RegisterEvents")
+	saveAllDollarTemplates              = dll.NewProc("SDL_SaveAllDollarTemplates")
+	saveDollarTemplate                  = dll.NewProc("SDL_SaveDollarTemplate")
+	sensorGetDeviceName                 = dll.NewProc("SDL_SensorGetDeviceName")
+	sensorGetDeviceNonPortableType      = dll.NewProc("SDL_SensorGetDeviceNonPortableType")
+	sensorUpdate                        = dll.NewProc("SDL_SensorUpdate")
+	setClipboardText                    = dll.NewProc("SDL_SetClipboardText")
+	setCursor                           = dll.NewProc("SDL_SetCursor")
+	setError                            = dll.NewProc("SDL_SetError")
+	setEventFilter                      = dll.NewProc("SDL_SetEventFilter")
+	setHint                             = dll.NewProc("SDL_SetHint")
+	setHintWithPriority                 = dll.NewProc("SDL_SetHintWithPriority")
+	setModState                         = dll.NewProc("SDL_SetModState")
+	setRelativeMouseMode                = dll.NewProc("SDL_SetRelativeMouseMode")
+	setTextInputRect                    = dll.NewProc("SDL_SetTextInputRect")
+	setYUVConversionMode                = dll.NewProc("SDL_SetYUVConversionMode")
+	showCursor                          = dll.NewProc("SDL_ShowCursor")
+	showMessageBox                      = dll.NewProc("SDL_ShowMessageBox")
+	showSimpleMessageBox                = dll.NewProc("SDL_ShowSimpleMessageBox")
+	startTextInput                      = dll.NewProc("SDL_StartTextInput")
+	stopTextInput                       = dll.NewProc("SDL_StopTextInput")
+	unlockAudio                         = dll.NewProc("SDL_UnlockAudio")
+	unlockAudioDevice                   = dll.NewProc("SDL_UnlockAudioDevice")
+	unlockJoysticks                     = dll.NewProc("SDL_UnlockJoysticks")
+	videoInit                           = dll.NewProc("SDL_VideoInit")
+	videoQuit                           = dll.NewProc("SDL_VideoQuit")
+	vulkan_GetVkGetInstanceProcAddr     = dll.NewProc("SDL_Vulkan_GetVkGetInstanceProcAddr")
+	vulkan_LoadLibrary                  = dll.NewProc("SDL_Vulkan_LoadLibrary")
+	vulkan_UnloadLibrary                = dll.NewProc("SDL_Vulkan_UnloadLibrary")
+	warpMouseGlobal                     = dll.NewProc("SDL_WarpMouseGlobal")
+	wasInit                             = dll.NewProc("SDL_WasInit")
+	openAudioDevice                     = dll.NewProc("SDL_OpenAudioDevice")
+	loadWAV_RW                          = dll.NewProc("SDL_LoadWAV_RW")
+	getAudioDeviceStatus                = dll.NewProc("SDL_GetAudioDeviceStatus")
+	getAudioStatus                      = dll.NewProc("SDL_GetAudioStatus")
+	newAudioStream                      = dll.NewProc("SDL_NewAudioStream")
+	audioStreamAvailable                = dll.NewProc("SDL_AudioStreamAvailable")
+	audioStreamClear                    = dll.NewProc("SDL_AudioStreamClear")
+	audioStreamFlush                    = dll.NewProc("SDL_AudioStreamFlush")
+	freeAudioStream                     = dll.NewProc("SDL_FreeAudioStream")
+	audioStreamGet                      = dll.NewProc("SDL_AudioStreamGet")
+	audioStreamPut                      = dll.NewProc("SDL_AudioStreamPut")
+	composeCustomBlendMode              = dll.NewProc("SDL_ComposeCustomBlendMode")
+	createCond                          = dll.NewProc("SDL_CreateCond")
+	condBroadcast                       = dll.NewProc("SDL_CondBroadcast")
+	destroyCond                         = dll.NewProc("SDL_DestroyCond")
+	condSignal                          = dll.NewProc("SDL_CondSignal")
+	condWait                            = dll.NewProc("SDL_CondWait")
+	condWaitTimeout                     = dll.NewProc("SDL_CondWaitTimeout")
+	createColorCursor                   = dll.NewProc("SDL_CreateColorCursor")
+	createCursor                        = dll.NewProc("SDL_CreateCursor")
+	createSystemCursor                  = dll.NewProc("SDL_CreateSystemCursor")
+	delHintCallback                     = dll.NewProc("SDL_DelHintCallback")
+	getCursor                           = dll.NewProc("SDL_GetCursor")
+	getDefaultCursor                    = dll.NewProc("SDL_GetDefaultCursor")
+	getClosestDisplayMode               = dll.NewProc("SDL_GetClosestDisplayMode")
+	getCurrentDisplayMode               = dll.NewProc("SDL_GetCurrentDisplayMode")
+	getDesktopDisplayMode               = dll.NewProc("SDL_GetDesktopDisplayMode")
+	getDisplayMode                      = dll.NewProc("SDL_GetDisplayMode")
+	pollEvent                           = dll.NewProc("SDL_PollEvent")
+	waitEvent                           = dll.NewProc("SDL_WaitEvent")
+	waitEventTimeout                    = dll.NewProc("SDL_WaitEventTimeout")
+	addEventWatch                       = dll.NewProc("SDL_AddEventWatch")
+	getTouchFinger                      = dll.NewProc("SDL_GetTouchFinger")
+	gameControllerFromInstanceID        = dll.NewProc("SDL_GameControllerFromInstanceID")
+	gameControllerOpen                  = dll.NewProc("SDL_GameControllerOpen")
+	gameControllerGetAttached           = dll.NewProc("SDL_GameControllerGetAttached")
+	gameControllerGetAxis               = dll.NewProc("SDL_GameControllerGetAxis")
+	gameControllerGetBindForAxis        = dll.NewProc("SDL_GameControllerGetBindForAxis")
+	gameControllerGetBindForButton      = dll.NewProc("SDL_GameControllerGetBindForButton")
+	gameControllerGetButton             = dll.NewProc("SDL_GameControllerGetButton")
+	gameControllerClose                 = dll.NewProc("SDL_GameControllerClose")
+	gameControllerGetJoystick           = dll.NewProc("SDL_GameControllerGetJoystick")
+	gameControllerMapping               = dll.NewProc("SDL_GameControllerMapping")
+	gameControllerName                  = dll.NewProc("SDL_GameControllerName")
+	gameControllerGetProduct            = dll.NewProc("SDL_GameControllerGetProduct")
+	gameControllerGetProductVersion     = dll.NewProc("SDL_GameControllerGetProductVersion")
+	gameControllerGetVendor             = dll.NewProc("SDL_GameControllerGetVendor")
+	gameControllerGetAxisFromString     = dll.NewProc("SDL_GameControllerGetAxisFromString")
+	gameControllerGetButtonFromString   = dll.NewProc("SDL_GameControllerGetButtonFromString")
+	hapticOpen                          = dll.NewProc("SDL_HapticOpen")
+	hapticOpenFromJoystick              = dll.NewProc("SDL_HapticOpenFromJoystick")
+	hapticOpenFromMouse                 = dll.NewProc("SDL_HapticOpenFromMouse")
+	hapticClose                         = dll.NewProc("SDL_HapticClose")
+	hapticDestroyEffect                 = dll.NewProc("SDL_HapticDestroyEffect")
+	hapticEffectSupported               = dll.NewProc("SDL_HapticEffectSupported")
+	hapticGetEffectStatus               = dll.NewProc("SDL_HapticGetEffectStatus")
+	hapticNewEffect                     = dll.NewProc("SDL_HapticNewEffect")
+	hapticNumAxes                       = dll.NewProc("SDL_HapticNumAxes")
+	hapticNumEffects                    = dll.NewProc("SDL_HapticNumEffects")
+	hapticNumEffectsPlaying             = dll.NewProc("SDL_HapticNumEffectsPlaying")
+	hapticPause                         = dll.NewProc("SDL_HapticPause")
+	hapticQuery                         = dll.NewProc("SDL_HapticQuery")
+	hapticRumbleInit                    = dll.NewProc("SDL_HapticRumbleInit")
+	hapticRumblePlay                    = dll.NewProc("SDL_HapticRumblePlay")
+	hapticRumbleStop                    = dll.NewProc("SDL_HapticRumbleStop")
+	hapticRumbleSupported               = dll.NewProc("SDL_HapticRumbleSupported")
+	hapticRunEffect                     = dll.NewProc("SDL_HapticRunEffect")
+	hapticSetAutocenter                 = dll.NewProc("SDL_HapticSetAutocenter")
+	hapticSetGain                       = dll.NewProc("SDL_HapticSetGain")
+	hapticStopAll                       = dll.NewProc("SDL_HapticStopAll")
+	hapticStopEffect                    = dll.NewProc("SDL_HapticStopEffect")
+	hapticUnpause                       = dll.NewProc("SDL_HapticUnpause")
+	hapticUpdateEffect                  = dll.NewProc("SDL_HapticUpdateEffect")
+	joystickFromInstanceID              = dll.NewProc("SDL_JoystickFromInstanceID")
+	joystickOpen                        = dll.NewProc("SDL_JoystickOpen")
+	joystickGetAttached                 = dll.NewProc("SDL_JoystickGetAttached")
+	joystickGetAxis                     = dll.NewProc("SDL_JoystickGetAxis")
+	joystickGetAxisInitialState         = dll.NewProc("SDL_JoystickGetAxisInitialState")
+	joystickGetBall                     = dll.NewProc("SDL_JoystickGetBall")
+	joystickGetButton                   = dll.NewProc("SDL_JoystickGetButton")
+	joystickClose                       = dll.NewProc("SDL_JoystickClose")
+	joystickCurrentPowerLevel           = dll.NewProc("SDL_JoystickCurrentPowerLevel")
+	joystickGetGUID                     = dll.NewProc("SDL_JoystickGetGUID")
+	joystickGetHat                      = dll.NewProc("SDL_JoystickGetHat")
+	joystickInstanceID                  = dll.NewProc("SDL_JoystickInstanceID")
+	joystickName                        = dll.NewProc("SDL_JoystickName")
+	joystickNumAxes                     = dll.NewProc("SDL_JoystickNumAxes")
+	joystickNumBalls                    = dll.NewProc("SDL_JoystickNumBalls")
+	joystickNumButtons                  = dll.NewProc("SDL_JoystickNumButtons")
+	joystickNumHats                     = dll.NewProc("SDL_JoystickNumHats")
+	joystickGetProduct                  = dll.NewProc("SDL_JoystickGetProduct")
+	joystickGetProductVersion           = dll.NewProc("SDL_JoystickGetProductVersion")
+	joystickGetType                     = dll.NewProc("SDL_JoystickGetType")
+	joystickGetVendor                   = dll.NewProc("SDL_JoystickGetVendor")
+	joystickGetDeviceGUID               = dll.NewProc("SDL_JoystickGetDeviceGUID")
+	joystickGetGUIDFromString           = dll.NewProc("SDL_JoystickGetGUIDFromString")
+	joystickGetDeviceInstanceID         = dll.NewProc("SDL_JoystickGetDeviceInstanceID")
+	joystickGetDeviceType               = dll.NewProc("SDL_JoystickGetDeviceType")
+	getKeyFromName                      = dll.NewProc("SDL_GetKeyFromName")
+	getKeyFromScancode                  = dll.NewProc("SDL_GetKeyFromScancode")
+	getModState                         = dll.NewProc("SDL_GetModState")
+	logGetPriority                      = dll.NewProc("SDL_LogGetPriority")
+	createMutex                         = dll.NewProc("SDL_CreateMutex")
+	destroyMutex                        = dll.NewProc("SDL_DestroyMutex")
+	lockMutex                           = dll.NewProc("SDL_LockMutex")
+	tryLockMutex                        = dll.NewProc("SDL_TryLockMutex")
+	unlockMutex                         = dll.NewProc("SDL_UnlockMutex")
+	allocPalette                        = dll.NewProc("SDL_AllocPalette")
+	freePalette                         = dll.NewProc("SDL_FreePalette")
+	setPaletteColors                    = dll.NewProc("SDL_SetPaletteColors")
+	allocFormat                         = dll.NewProc("SDL_AllocFormat")
+	freeFormat                          = dll.NewProc("SDL_FreeFormat")
+	setPixelFormatPalette               = dll.NewProc("SDL_SetPixelFormatPalette")
+	allocRW                             = dll.NewProc("SDL_AllocRW")
+	rwFromFile                          = dll.NewProc("SDL_RWFromFile")
+	rwFromMem                           = dll.NewProc("SDL_RWFromMem")
+	rwClose                             = dll.NewProc("RWclose")
+	freeRW                              = dll.NewProc("SDL_FreeRW")
+	loadFile_RW                         = dll.NewProc("SDL_LoadFile_RW")
+	readBE16                            = dll.NewProc("SDL_ReadBE16")
+	readBE32                            = dll.NewProc("SDL_ReadBE32")
+	readBE64                            = dll.NewProc("SDL_ReadBE64")
+	readLE16                            = dll.NewProc("SDL_ReadLE16")
+	readLE32                            = dll.NewProc("SDL_ReadLE32")
+	readLE64                            = dll.NewProc("SDL_ReadLE64")
+	readU8                              = dll.NewProc("SDL_ReadU8")
+	writeBE16                           = dll.NewProc("SDL_WriteBE16")
+	writeBE32                           = dll.NewProc("SDL_WriteBE32")
+	writeBE64                           = dll.NewProc("SDL_WriteBE64")
+	writeLE16                           = dll.NewProc("SDL_WriteLE16")
+	writeLE32                           = dll.NewProc("SDL_WriteLE32")
+	writeLE64                           = dll.NewProc("SDL_WriteLE64")
+	writeU8                             = dll.NewProc("SDL_WriteU8")
+	getDisplayBounds                    = dll.NewProc("SDL_GetDisplayBounds")
+	getDisplayUsableBounds              = dll.NewProc("SDL_GetDisplayUsableBounds")
+	createRenderer                      = dll.NewProc("SDL_CreateRenderer")
+	createSoftwareRenderer              = dll.NewProc("SDL_CreateSoftwareRenderer")
+	renderClear                         = dll.NewProc("SDL_RenderClear")
+	renderCopy                          = dll.NewProc("SDL_RenderCopy")
+	renderCopyF                         = dll.NewProc("SDL_RenderCopyF")
+	renderCopyEx                        = dll.NewProc("SDL_RenderCopyEx")
+	renderCopyExF                       = dll.NewProc("SDL_RenderCopyExF")
+	createTexture                       = dll.NewProc("SDL_CreateTexture")
+	createTextureFromSurface            = dll.NewProc("SDL_CreateTextureFromSurface")
+	destroyRenderer                     = dll.NewProc("SDL_DestroyRenderer")
+	renderDrawLine                      = dll.NewProc("SDL_RenderDrawLine")
+	renderDrawLineF                     = dll.NewProc("SDL_RenderDrawLineF")
+	renderDrawLines                     = dll.NewProc("SDL_RenderDrawLines")
+	renderDrawLinesF                    = dll.NewProc("SDL_RenderDrawLinesF")
+	renderDrawPoint                     = dll.NewProc("SDL_RenderDrawPoint")
+	renderDrawPointF                    = dll.NewProc("SDL_RenderDrawPointF")
+	renderDrawPoints                    = dll.NewProc("SDL_RenderDrawPoints")
+	renderDrawPointsF                   = dll.NewProc("SDL_RenderDrawPointsF")
+	renderDrawRect                      = dll.NewProc("SDL_RenderDrawRect")
+	renderDrawRectF                     = dll.NewProc("SDL_RenderDrawRectF")
+	renderDrawRects                     = dll.NewProc("SDL_RenderDrawRects")
+	renderDrawRectsF                    = dll.NewProc("SDL_RenderDrawRectsF")
+	renderFillRect                      = dll.NewProc("SDL_RenderFillRect")
+	renderFillRectF                     = dll.NewProc("SDL_RenderFillRectF")
+	renderFillRects                     = dll.NewProc("SDL_RenderFillRects")
+	renderFillRectsF                    = dll.NewProc("SDL_RenderFillRectsF")
+	renderFlush                         = dll.NewProc("SDL_RenderFlush")
+	renderGetClipRect                   = dll.NewProc("SDL_RenderGetClipRect")
+	getRenderDrawBlendMode              = dll.NewProc("SDL_GetRenderDrawBlendMode")
+	getRenderDrawColor                  = dll.NewProc("SDL_GetRenderDrawColor")
+	getRendererInfo                     = dll.NewProc("SDL_GetRendererInfo")
+	renderGetIntegerScale               = dll.NewProc("SDL_RenderGetIntegerScale")
+	renderGetLogicalSize                = dll.NewProc("SDL_RenderGetLogicalSize")
+	renderGetMetalCommandEncoder        = dll.NewProc("SDL_RenderGetMetalCommandEncoder")
+	renderGetMetalLayer                 = dll.NewProc("SDL_RenderGetMetalLayer")
+	getRendererOutputSize               = dll.NewProc("SDL_GetRendererOutputSize")
+	getRenderTarget                     = dll.NewProc("SDL_GetRenderTarget")
+	renderGetScale                      = dll.NewProc("SDL_RenderGetScale")
+	renderGetViewport                   = dll.NewProc("SDL_RenderGetViewport")
+	renderIsClipEnabled                 = dll.NewProc("SDL_RenderIsClipEnabled")
+	renderPresent                       = dll.NewProc("SDL_RenderPresent")
+	renderReadPixels                    = dll.NewProc("SDL_RenderReadPixels")
+	renderTargetSupported               = dll.NewProc("SDL_RenderTargetSupported")
+	renderSetClipRect                   = dll.NewProc("SDL_RenderSetClipRect")
+	setRenderDrawBlendMode              = dll.NewProc("SDL_SetRenderDrawBlendMode")
+	setRenderDrawColor                  = dll.NewProc("SDL_SetRenderDrawColor")
+	renderSetIntegerScale               = dll.NewProc("SDL_RenderSetIntegerScale")
+	renderSetLogicalSize                = dll.NewProc("SDL_RenderSetLogicalSize")
+	setRenderTarget                     = dll.NewProc("SDL_SetRenderTarget")
+	renderSetScale                      = dll.NewProc("SDL_RenderSetScale")
+	renderSetViewport                   = dll.NewProc("SDL_RenderSetViewport")
+	getScancodeFromKey                  = dll.NewProc("SDL_GetScancodeFromKey")
+	getScancodeFromName                 = dll.NewProc("SDL_GetScancodeFromName")
+	createSemaphore                     = dll.NewProc("SDL_CreateSemaphore")
+	destroySemaphore                    = dll.NewProc("SDL_DestroySemaphore")
+	semPost                             = dll.NewProc("SDL_SemPost")
+	semTryWait                          = dll.NewProc("SDL_SemTryWait")
+	semValue                            = dll.NewProc("SDL_SemValue")
+	semWait                             = dll.NewProc("SDL_SemWait")
+	semWaitTimeout                      = dll.NewProc("SDL_SemWaitTimeout")
+	sensorFromInstanceID                = dll.NewProc("SDL_SensorFromInstanceID")
+	sensorOpen                          = dll.NewProc("SDL_SensorOpen")
+	sensorClose                         = dll.NewProc("SDL_SensorClose")
+	sensorGetData                       = dll.NewProc("SDL_SensorGetData")
+	sensorGetInstanceID                 = dll.NewProc("SDL_SensorGetInstanceID")
+	sensorGetName                       = dll.NewProc("SDL_SensorGetName")
+	sensorGetNonPortableType            = dll.NewProc("SDL_SensorGetNonPortableType")
+	sensorGetType                       = dll.NewProc("SDL_SensorGetType")
+	sensorGetDeviceInstanceID           = dll.NewProc("SDL_SensorGetDeviceInstanceID")
+	sensorGetDeviceType                 = dll.NewProc("SDL_SensorGetDeviceType")
+	loadObject                          = dll.NewProc("SDL_LoadObject")
+	loadFunction                        = dll.NewProc("SDL_LoadFunction")
+	unloadObject                        = dll.NewProc("SDL_UnloadObject")
+	simdAlloc                           = dll.NewProc("SDL_SIMDAlloc")
+	simdFree                            = dll.NewProc("SDL_SIMDFree")
+	simdGetAlignment                    = dll.NewProc("SDL_SIMDGetAlignment")
+	free                                = dll.NewProc("SDL_free")
+	malloc                              = dll.NewProc("SDL_malloc")
+	memcpy                              = dll.NewProc("SDL_memcpy")
+	getMemoryFunctions                  = dll.NewProc("SDL_GetMemoryFunctions")
+	setMemoryFunctions                  = dll.NewProc("SDL_SetMemoryFunctions")
+	createRGBSurface                    = dll.NewProc("SDL_CreateRGBSurface")
+	createRGBSurfaceFrom                = dll.NewProc("SDL_CreateRGBSurfaceFrom")
+	createRGBSurfaceWithFormat          = dll.NewProc("SDL_CreateRGBSurfaceWithFormat")
+	createRGBSurfaceWithFormatFrom      = dll.NewProc("SDL_CreateRGBSurfaceWithFormatFrom")
+	loadBMP_RW                          = dll.NewProc("SDL_LoadBMP_RW")
+	blitSurface                         = dll.NewProc("SDL_BlitSurface")
+	blitScaled                          = dll.NewProc("SDL_BlitScaled")
+	convertSurface                      = dll.NewProc("SDL_ConvertSurface")
+	convertSurfaceFormat                = dll.NewProc("SDL_ConvertSurfaceFormat")
+	duplicateSurface                    = dll.NewProc("SDL_DuplicateSurface")
+	fillRect                            = dll.NewProc("SDL_FillRect")
+	fillRects                           = dll.NewProc("SDL_FillRects")
+	freeSurface                         = dll.NewProc("SDL_FreeSurface")
+	getSurfaceAlphaMod                  = dll.NewProc("SDL_GetSurfaceAlphaMod")
+	getSurfaceBlendMode                 = dll.NewProc("SDL_GetSurfaceBlendMode")
+	getClipRect                         = dll.NewProc("SDL_GetClipRect")
+	getColorKey                         = dll.NewProc("SDL_GetColorKey")
+	getSurfaceColorMod                  = dll.NewProc("SDL_GetSurfaceColorMod")
+	lockSurface                         = dll.NewProc("SDL_LockSurface")
+	lowerBlit                           = dll.NewProc("SDL_LowerBlit")
+	lowerBlitScaled                     = dll.NewProc("SDL_LowerBlitScaled")
+	saveBMP_RW                          = dll.NewProc("SDL_SaveBMP_RW")
+	setSurfaceAlphaMod                  = dll.NewProc("SDL_SetSurfaceAlphaMod")
+	setSurfaceBlendMode                 = dll.NewProc("SDL_SetSurfaceBlendMode")
+	setClipRect                         = dll.NewProc("SDL_SetClipRect")
+	setColorKey                         = dll.NewProc("SDL_SetColorKey")
+	setSurfaceColorMod                  = dll.NewProc("SDL_SetSurfaceColorMod")
+	setSurfacePalette                   = dll.NewProc("SDL_SetSurfacePalette")
+	setSurfaceRLE                       = dll.NewProc("SDL_SetSurfaceRLE")
+	softStretch                         = dll.NewProc("SDL_SoftStretch")
+	unlockSurface                       = dll.NewProc("SDL_UnlockSurface")
+	upperBlit                           = dll.NewProc("SDL_UpperBlit")
+	upperBlitScaled                     = dll.NewProc("SDL_UpperBlitScaled")
+	destroyTexture                      = dll.NewProc("SDL_DestroyTexture")
+	gl_BindTexture                      = dll.NewProc("SDL_GL_BindTexture")
+	gl_UnbindTexture                    = dll.NewProc("SDL_GL_UnbindTexture")
+	getTextureAlphaMod                  = dll.NewProc("SDL_GetTextureAlphaMod")
+	getTextureBlendMode                 = dll.NewProc("SDL_GetTextureBlendMode")
+	getTextureColorMod                  = dll.NewProc("SDL_GetTextureColorMod")
+	lockTexture                         = dll.NewProc("SDL_LockTexture")
+	queryTexture                        = dll.NewProc("SDL_QueryTexture")
+	setTextureAlphaMod                  = dll.NewProc("SDL_SetTextureAlphaMod")
+	setTextureBlendMode                 = dll.NewProc("SDL_SetTextureBlendMode")
+	setTextureColorMod                  = dll.NewProc("SDL_SetTextureColorMod")
+	unlockTexture                       = dll.NewProc("SDL_UnlockTexture")
+	updateTexture                       = dll.NewProc("SDL_UpdateTexture")
+	updateYUVTexture                    = dll.NewProc("SDL_UpdateYUVTexture")
+	getTouchDevice                      = dll.NewProc("SDL_GetTouchDevice")
+	getTouchDeviceType                  = dll.NewProc("SDL_GetTouchDeviceType")
+	createWindow                        = dll.NewProc("SDL_CreateWindow")
+	createWindowFrom                    = dll.NewProc("SDL_CreateWindowFrom")
+	getKeyboardFocus                    = dll.NewProc("SDL_GetKeyboardFocus")
+	getMouseFocus                       = dll.NewProc("SDL_GetMouseFocus")
+	getWindowFromID                     = dll.NewProc("SDL_GetWindowFromID")
+	destroyWindow                       = dll.NewProc("SDL_DestroyWindow")
+	gl_CreateContext                    = dll.NewProc("SDL_GL_CreateContext")
+	gl_GetDrawableSize                  = dll.NewProc("SDL_GL_GetDrawableSize")
+	gl_MakeCurrent                      = dll.NewProc("SDL_GL_MakeCurrent")
+	gl_SwapWindow                       = dll.NewProc("SDL_GL_SwapWindow")
+	getWindowBrightness                 = dll.NewProc("SDL_GetWindowBrightness")
+	getWindowData                       = dll.NewProc("SDL_GetWindowData")
+	getWindowDisplayIndex               = dll.NewProc("SDL_GetWindowDisplayIndex")
+	getWindowDisplayMode                = dll.NewProc("SDL_GetWindowDisplayMode")
+	getWindowFlags                      = dll.NewProc("SDL_GetWindowFlags")
+	getWindowGammaRamp                  = dll.NewProc("SDL_GetWindowGammaRamp")
+	getWindowGrab                       = dll.NewProc("SDL_GetWindowGrab")
+	getWindowID                         = dll.NewProc("SDL_GetWindowID")
+	getWindowMaximumSize                = dll.NewProc("SDL_GetWindowMaximumSize")
+	getWindowMinimumSize                = dll.NewProc("SDL_GetWindowMinimumSize")
+	getWindowPixelFormat                = dll.NewProc("SDL_GetWindowPixelFormat")
+	getWindowPosition                   = dll.NewProc("SDL_GetWindowPosition")
+	getRenderer                         = dll.NewProc("SDL_GetRenderer")
+	getWindowSize                       = dll.NewProc("SDL_GetWindowSize")
+	getWindowSurface                    = dll.NewProc("SDL_GetWindowSurface")
+	getWindowTitle                      = dll.NewProc("SDL_GetWindowTitle")
+	getWindowWMInfo                     = dll.NewProc("SDL_GetWindowWMInfo")
+	getWindowOpacity                    = dll.NewProc("SDL_GetWindowOpacity")
+	hideWindow                          = dll.NewProc("SDL_HideWindow")
+	maximizeWindow                      = dll.NewProc("SDL_MaximizeWindow")
+	minimizeWindow                      = dll.NewProc("SDL_MinimizeWindow")
+	raiseWindow                         = dll.NewProc("SDL_RaiseWindow")
+	restoreWindow                       = dll.NewProc("SDL_RestoreWindow")
+	setWindowBordered                   = dll.NewProc("SDL_SetWindowBordered")
+	setWindowBrightness                 = dll.NewProc("SDL_SetWindowBrightness")
+	setWindowData                       = dll.NewProc("SDL_SetWindowData")
+	setWindowDisplayMode                = dll.NewProc("SDL_SetWindowDisplayMode")
+	setWindowFullscreen                 = dll.NewProc("SDL_SetWindowFullscreen")
+	setWindowGammaRamp                  = dll.NewProc("SDL_SetWindowGammaRamp")
+	setWindowGrab                       = dll.NewProc("SDL_SetWindowGrab")
+	setWindowIcon                       = dll.NewProc("SDL_SetWindowIcon")
+	setWindowMaximumSize                = dll.NewProc("SDL_SetWindowMaximumSize")
+	setWindowMinimumSize                = dll.NewProc("SDL_SetWindowMinimumSize")
+	setWindowPosition                   = dll.NewProc("SDL_SetWindowPosition")
+	setWindowResizable                  = dll.NewProc("SDL_SetWindowResizable")
+	setWindowSize                       = dll.NewProc("SDL_SetWindowSize")
+	setWindowTitle                      = dll.NewProc("SDL_SetWindowTitle")
+	setWindowOpacity                    = dll.NewProc("SDL_SetWindowOpacity")
+	showWindow                          = dll.NewProc("SDL_ShowWindow")
+	updateWindowSurface                 = dll.NewProc("SDL_UpdateWindowSurface")
+	updateWindowSurfaceRects            = dll.NewProc("SDL_UpdateWindowSurfaceRects")
+	vulkan_GetDrawableSize              = dll.NewProc("SDL_Vulkan_GetDrawableSize")
+	vulkan_CreateSurface                = dll.NewProc("SDL_Vulkan_CreateSurface")
+	vulkan_GetInstanceExtensions        = dll.NewProc("SDL_Vulkan_GetInstanceExtensions")
+	warpMouseInWindow                   = dll.NewProc("SDL_WarpMouseInWindow")
+	getYUVConversionMode                = dll.NewProc("SDL_GetYUVConversionMode")
+	getYUVConversionModeForResolution   = dll.NewProc("SDL_GetYUVConversionModeForResolution")
 )
 
+// ProcError is the error for calls into a function that the loaded SDL2.dll
+// does not export. This usually means that the DLL is older than the SDL
+// version that introduced the function.
+//
+// Functions that were added in later SDL versions and that return an error
+// return the *ProcError. All other functions panic with it.
+type ProcError struct {
+	Name string // the name of the SDL function, e.g. "SDL_RenderGeometry"
+	Err  error  // the error returned when loading the DLL or looking up the function
+}
+
+func (e *ProcError) Error() string {
+	msg := "sdl: " + e.Name + " is not available in the loaded SDL2.dll"
+	if version, ok := procVersions[e.Name]; ok {
+		msg += ", it requires SDL " + version
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *ProcError) Unwrap() error {
+	return e.Err
+}
+
+// lazyDLL creates lazyProcs instead of syscall.LazyProcs.
+type lazyDLL struct {
+	*syscall.LazyDLL
+}
+
+func (d lazyDLL) NewProc(name string) *lazyProc {
+	return &lazyProc{LazyProc: d.LazyDLL.NewProc(name)}
+}
+
+// lazyProc looks up its function only once and, if that fails, reports it as
+// a *ProcError naming the missing function.
+type lazyProc struct {
+	*syscall.LazyProc
+	once sync.Once
+	err  error
+}
+
+// Find returns a *ProcError if the function is not in the DLL.
+func (p *lazyProc) Find() error {
+	p.once.Do(func() {
+		if err := p.LazyProc.Find(); err != nil {
+			p.err = &ProcError{Name: p.Name, Err: err}
+		}
+	})
+	return p.err
+}
+
+// Call calls the function and panics with a *ProcError if it does not exist.
+// Wrappers that return an error check Find before calling Call.
+func (p *lazyProc) Call(a ...uintptr) (r1, r2 uintptr, lastErr error) {
+	if err := p.Find(); err != nil {
+		panic(err)
+	}
+	if atomic.LoadInt32(&callChecks) != 0 {
+		return p.checkedCall(a...)
+	}
+	return p.LazyProc.Call(a...)
+}
+
+// EnableCallChecks turns extra checks on or off that run before every call
+// into SDL2.dll. They are meant for development: an invalid pointer passed to
+// SDL crashes the program with an access violation inside the DLL which says
+// nothing about the Go code that caused it. With the checks enabled, these
+// calls panic with a *CallCheckError instead, naming the SDL function and the
+// Go call site.
+//
+// The checks find methods called on nil objects, e.g. a nil *Texture, and
+// objects used after they were destroyed or freed, e.g. a *Surface after
+// calling its Free method. They cannot find every bad pointer and they slow
+// down each call, so leave them disabled in release builds.
+func EnableCallChecks(enable bool) {
+	var on int32
+	if enable {
+		on = 1
+	}
+	atomic.StoreInt32(&callChecks, on)
+	if !enable {
+		freed.Lock()
+		freed.pointers = make(map[uintptr]string)
+		freed.Unlock()
+	}
+}
+
+// CallCheckError is the value that calls panic with when EnableCallChecks is
+// on and a call into SDL2.dll would be made with an invalid argument.
+type CallCheckError struct {
+	Func   string // the SDL function, e.g. "SDL_RenderClear"
+	Method string // the Go function calling it, e.g. "Renderer.Clear"
+	Caller string // the file and line of the call from outside this package
+	Reason string // what is wrong with the arguments
+}
+
+func (e *CallCheckError) Error() string {
+	msg := "sdl: " + e.Method
+	if e.Caller != "" {
+		msg += " called from " + e.Caller
+	}
+	return msg + ": " + e.Reason + " (" + e.Func + ")"
+}
+
+// callChecks is 1 while EnableCallChecks is on, it is read on every call.
+var callChecks int32
+
+// freeingFuncs are the SDL functions that invalidate their first argument.
+// Functions for reference counted objects, like SDL_JoystickClose, are not
+// listed since the pointer might still be valid after them.
+var freeingFuncs = map[string]bool{
+	"SDL_DestroyCond":      true,
+	"SDL_DestroyMutex":     true,
+	"SDL_DestroyRenderer":  true,
+	"SDL_DestroySemaphore": true,
+	"SDL_DestroyTexture":   true,
+	"SDL_DestroyWindow":    true,
+	"SDL_FreeAudioStream":  true,
+	"SDL_FreeCursor":       true,
+	"SDL_FreeRW":           true,
+	"SDL_FreeSurface":      true,
+}
+
+// freed maps pointers that were destroyed while call checks were enabled to
+// the SDL function that destroyed them.
+var freed = struct {
+	sync.Mutex
+	pointers map[uintptr]string
+}{pointers: make(map[uintptr]string)}
+
+// forgetFreed removes pointers that SDL handed out again from the freed
+// pointers. Pointers returned from SDL functions are removed automatically,
+// this is only needed for pointers returned through output arguments.
+func forgetFreed(pointers ...uintptr) {
+	if atomic.LoadInt32(&callChecks) == 0 {
+		return
+	}
+	freed.Lock()
+	defer freed.Unlock()
+	for _, p := range pointers {
+		delete(freed.pointers, p)
+	}
+}
+
+// checkedCall is Call with the checks of EnableCallChecks.
+func (p *lazyProc) checkedCall(a ...uintptr) (r1, r2 uintptr, lastErr error) {
+	method, caller, receiverType := callSite()
+	fail := func(reason string) {
+		panic(&CallCheckError{
+			Func:   p.Name,
+			Method: method,
+			Caller: caller,
+			Reason: reason,
+		})
+	}
+
+	// By convention the receiver of a method is passed as the first argument
+	// to the SDL functions that contain the type name, e.g. Texture.Lock calls
+	// SDL_LockTexture(texture, ...). Methods that call other functions, like
+	// Window.HasKeyboardFocus, are not checked.
+	typeName := receiverType
+	if typeName == "Renderer" {
+		typeName = "Render" // as in SDL_RenderClear
+	}
+	if typeName != "" && strings.Contains(p.Name, typeName) &&
+		(len(a) == 0 || a[0] == 0) {
+		fail("the *" + receiverType + " is nil")
+	}
+
+	freed.Lock()
+	for i, arg := range a {
+		if by, ok := freed.pointers[arg]; ok && arg != 0 {
+			freed.Unlock()
+			fail(fmt.Sprintf("argument %d was already freed by %s", i+1, by))
+		}
+	}
+	freed.Unlock()
+
+	r1, r2, lastErr = p.LazyProc.Call(a...)
+
+	freed.Lock()
+	delete(freed.pointers, r1)
+	if freeingFuncs[p.Name] && len(a) > 0 && a[0] != 0 {
+		freed.pointers[a[0]] = p.Name
+	}
+	freed.Unlock()
+	return
+}
+
+// callSite returns the function of this package that calls into the DLL, the
+// position of the call to this package and, if the function is a method, the
+// name of its receiver type.
+func callSite() (method, caller, receiverType string) {
+	var pcs [16]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) {
+			if frame.Function != "" {
+				caller = fmt.Sprintf("%s:%d", frame.File, frame.Line)
+			}
+			return
+		}
+		name := strings.TrimPrefix(frame.Function, packagePrefix)
+		if method == "" && !strings.HasPrefix(name, "(*lazyProc)") {
+			method = name
+			if strings.HasPrefix(name, "(*") {
+				receiverType = name[2:strings.Index(name, ")")]
+				method = strings.Replace(name[2:], ")", "", 1)
+			}
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+//go:generate go run generate_procs.go
+
 func LoadDLL(file string) error {
-	dll = syscall.NewLazyDLL(file)
+	dll = lazyDLL{syscall.NewLazyDLL(file)}
 	if err := dll.Load(); err != nil {
 		return err
 	}
@@ -1942,6 +2265,9 @@ func LoadDLL(file string) error {
 	calculateGammaRamp = dll.NewProc("SDL_CalculateGammaRamp")
 	captureMouse = dll.NewProc("SDL_CaptureMouse")
 	clearError = dll.NewProc("SDL_ClearError")
+	getAssertionReport = dll.NewProc("SDL_GetAssertionReport")
+	resetAssertionReport = dll.NewProc("SDL_ResetAssertionReport")
+	setAssertionHandler = dll.NewProc("SDL_SetAssertionHandler")
 	clearHints = dll.NewProc("SDL_ClearHints")
 	clearQueuedAudio = dll.NewProc("SDL_ClearQueuedAudio")
 	getError = dll.NewProc("SDL_GetError")
@@ -1971,11 +2297,13 @@ func LoadDLL(file string) error {
 	gl_SetSwapInterval = dll.NewProc("SDL_GL_SetSwapInterval")
 	gl_UnloadLibrary = dll.NewProc("SDL_GL_UnloadLibrary")
 	gameControllerAddMapping = dll.NewProc("SDL_GameControllerAddMapping")
+	gameControllerAddMappingsFromRW = dll.NewProc("SDL_GameControllerAddMappingsFromRW")
 	gameControllerEventState = dll.NewProc("SDL_GameControllerEventState")
 	gameControllerGetStringForAxis = dll.NewProc("SDL_GameControllerGetStringForAxis")
 	gameControllerGetStringForButton = dll.NewProc("SDL_GameControllerGetStringForButton")
 	gameControllerMappingForGUID = dll.NewProc("SDL_GameControllerMappingForGUID")
 	gameControllerMappingForIndex = dll.NewProc("SDL_GameControllerMappingForIndex")
+	gameControllerMappingForDeviceIndex = dll.NewProc("SDL_GameControllerMappingForDeviceIndex")
 	gameControllerNameForIndex = dll.NewProc("SDL_GameControllerNameForIndex")
 	gameControllerNumMappings = dll.NewProc("SDL_GameControllerNumMappings")
 	gameControllerUpdate = dll.NewProc("SDL_GameControllerUpdate")
@@ -2064,6 +2392,7 @@ func LoadDLL(file string) error {
 	logCritical = dll.NewProc("SDL_LogCritical")
 	logDebug = dll.NewProc("SDL_LogDebug")
 	logError = dll.NewProc("SDL_LogError")
+	logGetOutputFunction = dll.NewProc("SDL_LogGetOutputFunction")
 	logInfo = dll.NewProc("SDL_LogInfo")
 	logMessage = dll.NewProc("SDL_LogMessage")
 	logResetPriorities = dll.NewProc("SDL_LogResetPriorities")
@@ -2297,6 +2626,7 @@ func LoadDLL(file string) error {
 	getRenderTarget = dll.NewProc("SDL_GetRenderTarget")
 	renderGetScale = dll.NewProc("SDL_RenderGetScale")
 	renderGetViewport = dll.NewProc("SDL_RenderGetViewport")
+	renderIsClipEnabled = dll.NewProc("SDL_RenderIsClipEnabled")
 	renderPresent = dll.NewProc("SDL_RenderPresent")
 	renderReadPixels = dll.NewProc("SDL_RenderReadPixels")
 	renderTargetSupported = dll.NewProc("SDL_RenderTargetSupported")
@@ -2333,6 +2663,11 @@ func LoadDLL(file string) error {
 	simdAlloc = dll.NewProc("SDL_SIMDAlloc")
 	simdFree = dll.NewProc("SDL_SIMDFree")
 	simdGetAlignment = dll.NewProc("SDL_SIMDGetAlignment")
+	free = dll.NewProc("SDL_free")
+	malloc = dll.NewProc("SDL_malloc")
+	memcpy = dll.NewProc("SDL_memcpy")
+	getMemoryFunctions = dll.NewProc("SDL_GetMemoryFunctions")
+	setMemoryFunctions = dll.NewProc("SDL_SetMemoryFunctions")
 	createRGBSurface = dll.NewProc("SDL_CreateRGBSurface")
 	createRGBSurfaceFrom = dll.NewProc("SDL_CreateRGBSurfaceFrom")
 	createRGBSurfaceWithFormat = dll.NewProc("SDL_CreateRGBSurfaceWithFormat")
@@ -2371,6 +2706,7 @@ func LoadDLL(file string) error {
 	gl_UnbindTexture = dll.NewProc("SDL_GL_UnbindTexture")
 	getTextureAlphaMod = dll.NewProc("SDL_GetTextureAlphaMod")
 	getTextureBlendMode = dll.NewProc("SDL_GetTextureBlendMode")
+	getTextureColorMod = dll.NewProc("SDL_GetTextureColorMod")
 	lockTexture = dll.NewProc("SDL_LockTexture")
 	queryTexture = dll.NewProc("SDL_QueryTexture")
 	setTextureAlphaMod = dll.NewProc("SDL_SetTextureAlphaMod")
@@ -2433,10 +2769,12 @@ func LoadDLL(file string) error {
 	updateWindowSurface = dll.NewProc("SDL_UpdateWindowSurface")
 	updateWindowSurfaceRects = dll.NewProc("SDL_UpdateWindowSurfaceRects")
 	vulkan_GetDrawableSize = dll.NewProc("SDL_Vulkan_GetDrawableSize")
+	vulkan_CreateSurface = dll.NewProc("SDL_Vulkan_CreateSurface")
 	vulkan_GetInstanceExtensions = dll.NewProc("SDL_Vulkan_GetInstanceExtensions")
 	warpMouseInWindow = dll.NewProc("SDL_WarpMouseInWindow")
 	getYUVConversionMode = dll.NewProc("SDL_GetYUVConversionMode")
 	getYUVConversionModeForResolution = dll.NewProc("SDL_GetYUVConversionModeForResolution")
+	loadGeneratedProcs()
 
 	return nil
 }
@@ -2470,6 +2808,43 @@ func AddHintCallback(name string, fn HintCallback, data interface{}) {
 	)
 }
 
+// AudioDevices returns the audio output devices, or the capture devices if
+// isCapture is true. The audio subsystem must be initialized.
+func AudioDevices(isCapture bool) []AudioDeviceInfo {
+	n := GetNumAudioDevices(isCapture)
+	if n <= 0 {
+		return nil
+	}
+	// GetAudioDeviceSpec is not available before SDL 2.0.16.
+	hasSpecs := getAudioDeviceSpec.Find() == nil
+	devices := make([]AudioDeviceInfo, n)
+	for i := range devices {
+		devices[i].Index = i
+		devices[i].Name = GetAudioDeviceName(i, isCapture)
+		if hasSpecs {
+			if spec, err := GetAudioDeviceSpec(i, isCapture); err == nil {
+				devices[i].Spec = spec
+			}
+		}
+	}
+	return devices
+}
+
+// AudioDrivers returns the names of the audio drivers that SDL was built
+// with, e.g. "wasapi", "directsound" and "winmm" on Windows. Not all of them
+// necessarily work on the system.
+func AudioDrivers() []string {
+	n := GetNumAudioDrivers()
+	if n <= 0 {
+		return nil
+	}
+	drivers := make([]string, n)
+	for i := range drivers {
+		drivers[i] = GetAudioDriver(i)
+	}
+	return drivers
+}
+
 // AudioInit initializes a particular audio driver.
 // (https://wiki.libsdl.org/SDL_AudioInit)
 func AudioInit(driverName string) error {
@@ -2481,15 +2856,61 @@ func AudioInit(driverName string) error {
 	return nil
 }
 
+// AudioDriverFallbacks are the audio drivers that AudioInitWithFallback tries
+// if no drivers are passed to it.
+var AudioDriverFallbacks = []string{"wasapi", "directsound", "winmm"}
+
+// AudioInitWithFallback initializes the first of the audio drivers that works
+// and returns its name. Without arguments, it tries AudioDriverFallbacks. If
+// no driver works, the error of the last one is returned.
+//
+// Use it instead of initializing INIT_AUDIO, which would initialize the
+// default driver again, and call AudioQuit when done.
+func AudioInitWithFallback(drivers ...string) (driver string, err error) {
+	if len(drivers) == 0 {
+		drivers = AudioDriverFallbacks
+	}
+	err = errors.New("sdl: no audio driver to initialize")
+	for _, driver := range drivers {
+		if err = AudioInit(driver); err == nil {
+			return GetCurrentAudioDriver(), nil
+		}
+	}
+	return "", err
+}
+
 // AudioQuit shuts down audio if you initialized it with AudioInit().
 // (https://wiki.libsdl.org/SDL_AudioQuit)
 func AudioQuit() {
 	audioQuit.Call()
+	forgetAudioDevices()
+}
+
+// AxisLabel returns the name printed on a controller of the given type for
+// the axis, e.g. "LT" on Xbox and "L2" on PlayStation controllers for
+// CONTROLLER_AXIS_TRIGGERLEFT. Unknown controller types use the Xbox names.
+// It returns an empty string for invalid axes.
+func AxisLabel(controllerType GameControllerType, axis GameControllerAxis) string {
+	switch axis {
+	case CONTROLLER_AXIS_LEFTX:
+		return "Left Stick X"
+	case CONTROLLER_AXIS_LEFTY:
+		return "Left Stick Y"
+	case CONTROLLER_AXIS_RIGHTX:
+		return "Right Stick X"
+	case CONTROLLER_AXIS_RIGHTY:
+		return "Right Stick Y"
+	case CONTROLLER_AXIS_TRIGGERLEFT:
+		return triggerLabels[controllerType.family()][0]
+	case CONTROLLER_AXIS_TRIGGERRIGHT:
+		return triggerLabels[controllerType.family()][1]
+	}
+	return ""
 }
 
 // BitsPerPixel returns the number of bits per pixel for the given format
 func BitsPerPixel(format uint32) int {
-	return int((format >> 8) & 0xFF)
+	return PixelFormatEnum(format).BitsPerPixel()
 }
 
 // Btoi returns 0 or 1 according to the value of b.
@@ -2559,19 +2980,46 @@ func ButtonX2Mask() uint32 {
 	return Button(BUTTON_X2)
 }
 
-// BytesPerPixel returns the number of bytes per pixel for the given format
-func BytesPerPixel(format uint32) int {
-	pixelFlag := (format >> 28) & 0x0F
-	isFourCC := format != 0 && pixelFlag != 1
-	if isFourCC {
-		if format == PIXELFORMAT_YUY2 ||
-			format == PIXELFORMAT_UYVY ||
-			format == PIXELFORMAT_YVYU {
-			return 2
+// ButtonLabel returns the name printed on a controller of the given type for
+// the button, e.g. "A" on Xbox and "Cross" on PlayStation controllers for
+// CONTROLLER_BUTTON_A, so button prompts can show what the player sees. SDL
+// reports Nintendo face buttons by their labels, not their positions, by
+// default, see HINT_GAMECONTROLLER_USE_BUTTON_LABELS. Unknown controller types
+// use the Xbox names. It returns an empty string for invalid buttons.
+func ButtonLabel(controllerType GameControllerType, button GameControllerButton) string {
+	if button >= CONTROLLER_BUTTON_MAX {
+		return ""
+	}
+	switch controllerType {
+	case CONTROLLER_TYPE_XBOX360:
+		switch button {
+		case CONTROLLER_BUTTON_BACK:
+			return "Back"
+		case CONTROLLER_BUTTON_START:
+			return "Start"
+		}
+	case CONTROLLER_TYPE_PS3:
+		switch button {
+		case CONTROLLER_BUTTON_BACK:
+			return "Select"
+		case CONTROLLER_BUTTON_START:
+			return "Start"
+		}
+	case CONTROLLER_TYPE_PS4:
+		if button == CONTROLLER_BUTTON_BACK {
+			return "Share"
+		}
+	case CONTROLLER_TYPE_PS5:
+		if button == CONTROLLER_BUTTON_BACK {
+			return "Create"
 		}
-		return 1
 	}
-	return int(format & 0xFF)
+	return buttonLabels[controllerType.family()][button]
+}
+
+// BytesPerPixel returns the number of bytes per pixel for the given format
+func BytesPerPixel(format uint32) int {
+	return PixelFormatEnum(format).BytesPerPixel()
 }
 
 // COMPILEDVERSION returns the SDL version number that you compiled against.
@@ -2599,6 +3047,27 @@ func CaptureMouse(toggle bool) error {
 	return nil
 }
 
+// CaptureMouseScoped captures the mouse until the returned release function
+// is called, e.g. while the player drags something out of the window. Defer
+// the call so the mouse is released even if the code panics or returns early:
+//
+//	release, err := sdl.CaptureMouseScoped()
+//	if err != nil {
+//		return err
+//	}
+//	defer release()
+//
+// Calling release more than once has no effect.
+func CaptureMouseScoped() (release func(), err error) {
+	if err := CaptureMouse(true); err != nil {
+		return func() {}, err
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() { CaptureMouse(false) })
+	}, nil
+}
+
 // ClearError clears any previous error message.
 // (https://wiki.libsdl.org/SDL_ClearError)
 func ClearError() {
@@ -2621,12 +3090,14 @@ func ClearQueuedAudio(dev AudioDeviceID) {
 // (https://wiki.libsdl.org/SDL_CloseAudio)
 func CloseAudio() {
 	closeAudio.Call()
+	trackAudioDevice(legacyAudioDevice, false)
 }
 
 // CloseAudioDevice shuts down audio processing and closes the audio device.
 // (https://wiki.libsdl.org/SDL_CloseAudioDevice)
 func CloseAudioDevice(dev AudioDeviceID) {
 	closeAudioDevice.Call(uintptr(dev))
+	trackAudioDevice(dev, false)
 }
 
 // ConvertAudio converts audio data to a desired audio format.
@@ -2669,8 +3140,8 @@ func ConvertPixels(
 // CreateWindowAndRenderer returns a new window and default renderer.
 // (https://wiki.libsdl.org/SDL_CreateWindowAndRenderer)
 func CreateWindowAndRenderer(w, h int32, flags uint32) (*Window, *Renderer, error) {
-	var window Window
-	var renderer Renderer
+	var window *Window
+	var renderer *Renderer
 	ret, _, _ := createWindowAndRenderer.Call(
 		uintptr(w),
 		uintptr(h),
@@ -2681,7 +3152,9 @@ func CreateWindowAndRenderer(w, h int32, flags uint32) (*Window, *Renderer, erro
 	if ret != 0 {
 		return nil, nil, GetError()
 	}
-	return &window, &renderer, nil
+	registerWindow(window)
+	forgetFreed(uintptr(unsafe.Pointer(window)), uintptr(unsafe.Pointer(renderer)))
+	return window, renderer, nil
 }
 
 // CurrentThreadID gets the thread identifier for the current thread.
@@ -2691,6 +3164,54 @@ func CurrentThreadID() uint {
 	return uint(ret)
 }
 
+// CursorDisplayIndex returns the index of the display that the mouse cursor is
+// on, using the global mouse position and GetDisplayBounds. If the cursor is
+// on none of the displays, e.g. while it is in the gap between displays of
+// different sizes, the closest display is returned.
+func CursorDisplayIndex() (int, error) {
+	x, y, _ := GetGlobalMouseState()
+	index, _, err := displayAt(Point{X: x, Y: y})
+	return index, err
+}
+
+// displayAt returns the display containing p, or the one closest to it, and
+// its bounds.
+func displayAt(p Point) (index int, bounds Rect, err error) {
+	n, err := GetNumVideoDisplays()
+	if err != nil {
+		return 0, Rect{}, err
+	}
+	bestDist := int64(-1)
+	for i := 0; i < n; i++ {
+		r, err := GetDisplayBounds(i)
+		if err != nil {
+			return 0, Rect{}, err
+		}
+		if r.Contains(p) {
+			return i, r, nil
+		}
+		dx := int64(clampInt32(p.X, r.X, r.X+r.W-1) - p.X)
+		dy := int64(clampInt32(p.Y, r.Y, r.Y+r.H-1) - p.Y)
+		if dist := dx*dx + dy*dy; bestDist < 0 || dist < bestDist {
+			index, bounds, bestDist = i, r, dist
+		}
+	}
+	if bestDist < 0 {
+		return 0, Rect{}, errors.New("sdl: no video displays available")
+	}
+	return index, bounds, nil
+}
+
+func clampInt32(x, min, max int32) int32 {
+	if x < min {
+		return min
+	}
+	if x > max {
+		return max
+	}
+	return x
+}
+
 // DelEventWatch removes an event watch callback added with AddEventWatch().
 // (https://wiki.libsdl.org/SDL_DelEventWatch)
 func DelEventWatch(handle EventWatchHandle) {
@@ -2809,6 +3330,12 @@ func FlushEvents(minType, maxType uint32) {
 	flushEvents.Call(uintptr(minType), uintptr(maxType))
 }
 
+// Free frees memory that was allocated by SDL, e.g. with Malloc.
+// (https://wiki.libsdl.org/SDL_free)
+func Free(mem unsafe.Pointer) {
+	free.Call(uintptr(mem))
+}
+
 // FreeCursor frees a cursor created with CreateCursor(), CreateColorCursor() or CreateSystemCursor().
 // (https://wiki.libsdl.org/SDL_FreeCursor)
 func FreeCursor(cursor *Cursor) {
@@ -2862,8 +3389,10 @@ func GLGetSwapInterval() (int, error) {
 }
 
 // errorFromInt returns GetError() if passed negative value, otherwise it returns nil.
+// Only the lower 32 bits of code are used since SDL functions return a C int,
+// which leaves the upper half of a 64 bit register undefined.
 func errorFromInt(code int) error {
-	if code < 0 {
+	if int32(code) < 0 {
 		return GetError()
 	}
 	return nil
@@ -2877,6 +3406,15 @@ func GLLoadLibrary(path string) error {
 	return errorFromInt(int(ret))
 }
 
+// GLLoader returns GLGetProcAddress as a loader function, matching the
+// signature of go-gl's InitWithProcAddrFunc. After creating an OpenGL context
+// with Window.GLCreateContext you can initialize go-gl with:
+//
+// 	gl.InitWithProcAddrFunc(sdl.GLLoader())
+func GLLoader() func(name string) unsafe.Pointer {
+	return GLGetProcAddress
+}
+
 // GLSetAttribute sets an OpenGL window attribute before window creation.
 // (https://wiki.libsdl.org/SDL_GL_SetAttribute)
 func GLSetAttribute(attr GLattr, value int) error {
@@ -2891,6 +3429,20 @@ func GLSetSwapInterval(interval int) error {
 	return errorFromInt(int(ret))
 }
 
+// GLSetSwapIntervalAdaptive tries to enable adaptive vsync for the current
+// OpenGL context. If the driver does not support it, regular vsync is enabled
+// instead. The returned interval is -1 for adaptive and 1 for regular vsync.
+// (https://wiki.libsdl.org/SDL_GL_SetSwapInterval)
+func GLSetSwapIntervalAdaptive() (interval int, err error) {
+	if err := GLSetSwapInterval(-1); err == nil {
+		return -1, nil
+	}
+	if err := GLSetSwapInterval(1); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
 // GLUnloadLibrary unloads the OpenGL library previously loaded by GLLoadLibrary().
 // (https://wiki.libsdl.org/SDL_GL_UnloadLibrary)
 func GLUnloadLibrary() {
@@ -2905,6 +3457,29 @@ func GameControllerAddMapping(mappingString string) int {
 	return int(ret)
 }
 
+// GameControllerAddMappingsFromFile loads mappings from a file like
+// gamecontrollerdb.txt and returns the number of mappings added, or -1 on
+// errors.
+// (https://wiki.libsdl.org/SDL_GameControllerAddMappingsFromFile)
+func GameControllerAddMappingsFromFile(file string) int {
+	rw := RWFromFile(file, "rb")
+	if rw == nil {
+		return -1
+	}
+	return GameControllerAddMappingsFromRW(rw, true)
+}
+
+// GameControllerAddMappingsFromRW loads mappings from an SDL data stream and
+// returns the number of mappings added, or -1 on errors.
+// (https://wiki.libsdl.org/SDL_GameControllerAddMappingsFromRW)
+func GameControllerAddMappingsFromRW(rw *RWops, freeRW bool) int {
+	ret, _, _ := gameControllerAddMappingsFromRW.Call(
+		uintptr(unsafe.Pointer(rw)),
+		uintptr(Btoi(freeRW)),
+	)
+	return int(int32(ret))
+}
+
 // GameControllerEventState returns the current state of, enable, or disable events dealing with Game Controllers. This will not disable Joystick events, which can also be fired by a controller (see https://wiki.libsdl.org/SDL_JoystickEventState).
 // (https://wiki.libsdl.org/SDL_GameControllerEventState)
 func GameControllerEventState(state int) int {
@@ -2926,11 +3501,20 @@ func GameControllerGetStringForButton(btn GameControllerButton) string {
 	return sdlToGoString(ret)
 }
 
+// GameControllerMappingForDeviceIndex returns the mapping of the joystick at
+// the device index, or an empty string if it has none.
+// This function requires SDL 2.0.9 or newer.
+// (https://wiki.libsdl.org/SDL_GameControllerMappingForDeviceIndex)
+func GameControllerMappingForDeviceIndex(index int) string {
+	ret, _, _ := gameControllerMappingForDeviceIndex.Call(uintptr(index))
+	return takeSDLString(ret)
+}
+
 // GameControllerMappingForIndex returns the game controller mapping string at a
 // particular index.
 func GameControllerMappingForIndex(index int) string {
 	ret, _, _ := gameControllerMappingForIndex.Call(uintptr(index))
-	return sdlToGoString(ret)
+	return takeSDLString(ret)
 }
 
 // GameControllerNameForIndex returns the implementation dependent name for the game controller.
@@ -2946,6 +3530,33 @@ func GameControllerNumMappings() int {
 	return int(ret)
 }
 
+// GameControllerSaveMappings writes all installed mappings to w, one per
+// line, in the format of gamecontrollerdb.txt. This way mappings the user
+// customized with GameControllerAddMapping can be exported and loaded again
+// with GameControllerAddMappingsFromFile.
+//
+// SDL only loads lines from a file that have a platform field, so
+// "platform:Windows," is appended to mappings without one, e.g. the built-in
+// XInput mapping or mappings added without a platform.
+func GameControllerSaveMappings(w io.Writer) error {
+	for i := 0; i < GameControllerNumMappings(); i++ {
+		mapping := GameControllerMappingForIndex(i)
+		if mapping == "" {
+			continue
+		}
+		if !strings.Contains(mapping, ",platform:") {
+			if !strings.HasSuffix(mapping, ",") {
+				mapping += ","
+			}
+			mapping += "platform:Windows,"
+		}
+		if _, err := io.WriteString(w, mapping+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GameControllerUpdate manually pumps game controller updates if not using the loop.
 // (https://wiki.libsdl.org/SDL_GameControllerUpdate)
 func GameControllerUpdate() {
@@ -2968,9 +3579,12 @@ func GetAudioDriver(index int) string {
 
 // GetBasePath returns the directory where the application was run from. This is where the application data directory is.
 // (https://wiki.libsdl.org/SDL_GetBasePath)
-func GetBasePath() string {
+func GetBasePath() (string, error) {
 	ret, _, _ := getBasePath.Call()
-	return sdlToGoString(ret)
+	if ret == 0 {
+		return "", GetError()
+	}
+	return takeSDLString(ret), nil
 }
 
 // GetCPUCacheLineSize returns the L1 cache line size of the CPU.
@@ -2987,6 +3601,36 @@ func GetCPUCount() int {
 	return int(ret)
 }
 
+// GetCPUFeatures returns the CPU information SDL uses to choose its optimized
+// code paths, so Go code can make the same decisions. Features that the
+// loaded SDL2.dll is too old to detect are reported as missing.
+func GetCPUFeatures() CPUFeatures {
+	has := func(proc *lazyProc, f func() bool) bool {
+		return proc.Find() == nil && f()
+	}
+	return CPUFeatures{
+		Count:         GetCPUCount(),
+		CacheLineSize: GetCPUCacheLineSize(),
+		SIMDAlignment: SIMDGetAlignment(),
+		Has3DNow:      Has3DNow(),
+		HasAltiVec:    HasAltiVec(),
+		HasARMSIMD:    has(hasARMSIMD, HasARMSIMD),
+		HasAVX:        HasAVX(),
+		HasAVX2:       HasAVX2(),
+		HasAVX512F:    has(hasAVX512F, HasAVX512F),
+		HasLASX:       has(hasLASX, HasLASX),
+		HasLSX:        has(hasLSX, HasLSX),
+		HasMMX:        HasMMX(),
+		HasNEON:       HasNEON(),
+		HasRDTSC:      HasRDTSC(),
+		HasSSE:        HasSSE(),
+		HasSSE2:       HasSSE2(),
+		HasSSE3:       HasSSE3(),
+		HasSSE41:      HasSSE41(),
+		HasSSE42:      HasSSE42(),
+	}
+}
+
 // GetClipboardText returns UTF-8 text from the clipboard.
 // (https://wiki.libsdl.org/SDL_GetClipboardText)
 func GetClipboardText() (string, error) {
@@ -2994,7 +3638,7 @@ func GetClipboardText() (string, error) {
 	if ret == 0 {
 		return "", GetError()
 	}
-	return sdlToGoString(ret), nil
+	return takeSDLString(ret), nil
 }
 
 // GetCurrentAudioDriver returns the name of the current audio driver.
@@ -3014,6 +3658,27 @@ func GetCurrentVideoDriver() (string, error) {
 	return sdlToGoString(ret), nil
 }
 
+// GetDefaultAudioInfo returns the name and preferred audio format of the
+// default audio output device, or capture device if isCapture is true.
+// Callback and UserData of the spec are not set.
+// This function requires SDL 2.24.0 or newer.
+// (https://wiki.libsdl.org/SDL_GetDefaultAudioInfo)
+func GetDefaultAudioInfo(isCapture bool) (name string, spec AudioSpec, err error) {
+	if err := getDefaultAudioInfo.Find(); err != nil {
+		return "", AudioSpec{}, err
+	}
+	var cName uintptr
+	ret, _, _ := getDefaultAudioInfo.Call(
+		uintptr(unsafe.Pointer(&cName)),
+		uintptr(unsafe.Pointer(&spec)),
+		uintptr(Btoi(isCapture)),
+	)
+	if int32(ret) != 0 {
+		return "", AudioSpec{}, GetError()
+	}
+	return takeSDLString(cName), spec, nil
+}
+
 // GetDisplayDPI returns the dots/pixels-per-inch for a display.
 // (https://wiki.libsdl.org/SDL_GetDisplayDPI)
 func GetDisplayDPI(displayIndex int) (ddpi, hdpi, vdpi float32, err error) {
@@ -3039,18 +3704,53 @@ func GetDisplayName(displayIndex int) (string, error) {
 
 // GetError returns the last error that occurred, or an empty string if there hasn't been an error message set since the last call to ClearError().
 // (https://wiki.libsdl.org/SDL_GetError)
+//
+// The returned error is a *CallError. If GetError is called from inside this
+// package, the error's Func is the name of the failing function.
 func GetError() error {
 	ret, _, _ := getError.Call()
 	if ret != 0 {
 		s := sdlToGoString(ret)
 		// SDL_GetError returns "an empty string if there hasn't been an error message"
 		if s != "" {
-			return errors.New(s)
+			return &CallError{Func: failingFuncName(), Msg: s}
 		}
 	}
 	return nil
 }
 
+// failingFuncName returns the name of the first function in the call stack
+// that belongs to this package but is not one of the error helpers, or the
+// empty string if GetError was called from outside this package.
+func failingFuncName() string {
+	var pcs [8]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		name := frame.Function
+		if !strings.HasPrefix(name, packagePrefix) {
+			return ""
+		}
+		name = strings.TrimPrefix(name, packagePrefix)
+		if name != "GetError" && name != "errorFromInt" {
+			name = strings.Replace(name, "(*", "", 1)
+			name = strings.Replace(name, ")", "", 1)
+			return name
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// packagePrefix is the package path as it appears in function names on the
+// call stack, e.g. "github.com/gonutz/go-sdl2/sdl.".
+var packagePrefix = strings.TrimSuffix(
+	runtime.FuncForPC(reflect.ValueOf(Btoi).Pointer()).Name(),
+	"Btoi",
+)
+
 // GetEventState returns the current processing state of the specified event
 // (https://wiki.libsdl.org/SDL_EventState)
 func GetEventState(typ uint32) uint8 {
@@ -3096,6 +3796,20 @@ func GetKeyboardState() []uint8 {
 	return *(*[]uint8)(unsafe.Pointer(&keys))
 }
 
+// GetMemoryFunctions returns the functions SDL currently uses to allocate
+// memory.
+// (https://wiki.libsdl.org/SDL_GetMemoryFunctions)
+func GetMemoryFunctions() MemoryFunctions {
+	var f MemoryFunctions
+	getMemoryFunctions.Call(
+		uintptr(unsafe.Pointer(&f.MallocFunc)),
+		uintptr(unsafe.Pointer(&f.CallocFunc)),
+		uintptr(unsafe.Pointer(&f.ReallocFunc)),
+		uintptr(unsafe.Pointer(&f.FreeFunc)),
+	)
+	return f
+}
+
 // GetMouseState returns the current state of the mouse.
 // (https://wiki.libsdl.org/SDL_GetMouseState)
 func GetMouseState() (x, y int32, state uint32) {
@@ -3181,16 +3895,48 @@ func GetPowerInfo() (state, secs, percent int) {
 	return
 }
 
+// GetPreferredLocales returns the user's preferred locales, the most preferred
+// first. The list can be empty if SDL cannot determine them.
+// This function requires SDL 2.0.14 or newer.
+// (https://wiki.libsdl.org/SDL_GetPreferredLocales)
+func GetPreferredLocales() ([]Locale, error) {
+	if err := getPreferredLocales.Find(); err != nil {
+		return nil, err
+	}
+	ret, _, _ := getPreferredLocales.Call()
+	if ret == 0 {
+		return nil, GetError()
+	}
+	defer free.Call(ret)
+	// SDL_Locale is a pair of char pointers, the list ends with a NULL
+	// language.
+	var locales []Locale
+	for p := ret; ; p += 2 * unsafe.Sizeof(uintptr(0)) {
+		pair := (*[2]uintptr)(unsafe.Pointer(p))
+		if pair[0] == 0 {
+			break
+		}
+		locales = append(locales, Locale{
+			Language: sdlToGoString(pair[0]),
+			Country:  sdlToGoString(pair[1]),
+		})
+	}
+	return locales, nil
+}
+
 // GetPrefPath returns the "pref dir". This is meant to be where the application can write personal files (Preferences and save games, etc.) that are specific to the application. This directory is unique per user and per application.
 // (https://wiki.libsdl.org/SDL_GetPrefPath)
-func GetPrefPath(org, app string) string {
+func GetPrefPath(org, app string) (string, error) {
 	o := append([]byte(org), 0)
 	a := append([]byte(app), 0)
 	ret, _, _ := getPrefPath.Call(
 		uintptr(unsafe.Pointer(&o[0])),
 		uintptr(unsafe.Pointer(&a[0])),
 	)
-	return sdlToGoString(ret)
+	if ret == 0 {
+		return "", GetError()
+	}
+	return takeSDLString(ret), nil
 }
 
 // GetQueuedAudioSize returns the number of bytes of still-queued audio.
@@ -3205,7 +3951,7 @@ func GetQueuedAudioSize(dev AudioDeviceID) uint32 {
 func GetRGB(pixel uint32, format *PixelFormat) (r, g, b uint8) {
 	getRGB.Call(
 		uintptr(pixel),
-		uintptr(unsafe.Pointer(&format)),
+		uintptr(unsafe.Pointer(format)),
 		uintptr(unsafe.Pointer(&r)),
 		uintptr(unsafe.Pointer(&g)),
 		uintptr(unsafe.Pointer(&b)),
@@ -3218,7 +3964,7 @@ func GetRGB(pixel uint32, format *PixelFormat) (r, g, b uint8) {
 func GetRGBA(pixel uint32, format *PixelFormat) (r, g, b, a uint8) {
 	getRGBA.Call(
 		uintptr(pixel),
-		uintptr(unsafe.Pointer(&format)),
+		uintptr(unsafe.Pointer(format)),
 		uintptr(unsafe.Pointer(&r)),
 		uintptr(unsafe.Pointer(&g)),
 		uintptr(unsafe.Pointer(&b)),
@@ -3285,6 +4031,23 @@ func GetScancodeName(code Scancode) string {
 	return sdlToGoString(ret)
 }
 
+// GetSystemInfo collects information about the system and the loaded SDL
+// version, e.g. for bug reports. Its String method formats it as text.
+func GetSystemInfo() SystemInfo {
+	info := SystemInfo{
+		Platform:         GetPlatform(),
+		Revision:         GetRevision(),
+		SystemRAM:        GetSystemRAM(),
+		CPUCount:         GetCPUCount(),
+		CPUCacheLineSize: GetCPUCacheLineSize(),
+	}
+	GetVersion(&info.Version)
+	if getPreferredLocales.Find() == nil {
+		info.Locales, _ = GetPreferredLocales()
+	}
+	return info
+}
+
 // GetSystemRAM returns the amount of RAM configured in the system.
 // (https://wiki.libsdl.org/SDL_GetSystemRAM)
 func GetSystemRAM() int {
@@ -3299,6 +4062,19 @@ func GetTicks() uint32 {
 	return uint32(ret)
 }
 
+// GetTicks64 returns the number of milliseconds since the SDL library
+// initialization. Unlike GetTicks it does not wrap around after 49 days.
+// This function requires SDL 2.0.18 or newer.
+// (https://wiki.libsdl.org/SDL_GetTicks64)
+func GetTicks64() uint64 {
+	// On 32 bit Windows the result is returned in two registers.
+	lo, hi, _ := getTicks64.Call()
+	if unsafe.Sizeof(uintptr(0)) == 8 {
+		return uint64(lo)
+	}
+	return uint64(uint32(lo)) | uint64(hi)<<32
+}
+
 // GetVersion returns the version of SDL that is linked against your program.
 // (https://wiki.libsdl.org/SDL_GetVersion)
 func GetVersion(v *Version) {
@@ -3312,6 +4088,82 @@ func GetVideoDriver(index int) string {
 	return sdlToGoString(ret)
 }
 
+// HandlePanic reports a panic to the user, see InstallPanicHandler. Main
+// calls it after InstallPanicHandler, programs that do not use Main can defer
+// it at the start of main and of every goroutine:
+//
+//	func main() {
+//		sdl.InstallPanicHandler("My Company", "My Game")
+//		defer sdl.HandlePanic()
+//		// ...
+//	}
+//
+// After reporting, the panic continues so the program still terminates.
+func HandlePanic() {
+	if r := recover(); r != nil {
+		reportPanic(r, debug.Stack())
+		panic(r)
+	}
+}
+
+// reportPanic writes the crash log and shows the message box.
+func reportPanic(r interface{}, stack []byte) {
+	now := time.Now()
+	text := fmt.Sprintf("%s\npanic: %v\n\n%s", now.Format(time.RFC3339), r, stack)
+	dir := os.TempDir()
+	if panicHandler.app != "" {
+		if pref, err := GetPrefPath(panicHandler.org, panicHandler.app); err == nil {
+			dir = pref
+		}
+	}
+	logPath := filepath.Join(dir, "crash_"+now.Format("20060102_150405")+".log")
+	msg := fmt.Sprintf("The program crashed:\n\n%v\n\n%s", r, stackSummary(stack, 5))
+	if err := ioutil.WriteFile(logPath, []byte(text), 0666); err == nil {
+		msg += "\nA crash log was written to\n" + logPath
+	}
+	title := panicHandler.app
+	if title == "" {
+		title = "Error"
+	}
+	showPanicMessage(title, msg)
+}
+
+// showPanicMessage shows the message box for a reported panic. Tests replace
+// it so they do not block on the dialog.
+var showPanicMessage = func(title, message string) {
+	ShowSimpleMessageBox(MESSAGEBOX_ERROR, title, message, nil)
+}
+
+// stackSummary returns the first n functions of the stack trace below the
+// call to panic, one per line.
+func stackSummary(stack []byte, n int) string {
+	lines := strings.Split(string(stack), "\n")
+	var summary string
+	inPanic := false
+	for i := 0; i+1 < len(lines) && n > 0; i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, "panic(") {
+			inPanic = true
+			i++ // skip the file of panic itself
+			continue
+		}
+		if !inPanic || strings.HasPrefix(line, "\t") || line == "" {
+			continue
+		}
+		if paren := strings.LastIndex(line, "("); paren != -1 {
+			line = line[:paren]
+		}
+		file := strings.TrimSpace(lines[i+1])
+		if offset := strings.Index(file, " +0x"); offset != -1 {
+			file = file[:offset]
+		}
+		summary += line + "\n\t" + file + "\n"
+		i++
+		n--
+	}
+	return summary
+}
+
 // HapticIndex returns the index of a haptic device.
 // (https://wiki.libsdl.org/SDL_HapticIndex)
 func HapticIndex(h *Haptic) (int, error) {
@@ -3339,6 +4191,32 @@ func HapticOpened(index int) (bool, error) {
 	return ret == 1, nil
 }
 
+// Haptics returns information about all haptic devices. To query a device's
+// capabilities it is opened and closed again, devices that cannot be opened
+// only have their Index and Name set.
+func Haptics() []HapticInfo {
+	n, err := NumHaptics()
+	if err != nil || n <= 0 {
+		return nil
+	}
+	haptics := make([]HapticInfo, n)
+	for i := range haptics {
+		info := &haptics[i]
+		info.Index = i
+		info.Name, _ = HapticName(i)
+		h, err := HapticOpen(i)
+		if err != nil {
+			continue
+		}
+		info.Axes, _ = h.NumAxes()
+		info.Effects, _ = h.NumEffects()
+		info.SupportedEffects, _ = h.Query()
+		info.Rumble, _ = h.RumbleSupported()
+		h.Close()
+	}
+	return haptics
+}
+
 // Has3DNow reports whether the CPU has 3DNow! features.
 // (https://wiki.libsdl.org/SDL_Has3DNow)
 func Has3DNow() bool {
@@ -3445,10 +4323,71 @@ func HasSSE42() bool {
 }
 
 // HasScreenKeyboardSupport reports whether the platform has some screen keyboard support.
+// SDL only supports screen keyboards for WinRT apps on Windows, for desktop
+// programs this returns false, even on tablets.
 // (https://wiki.libsdl.org/SDL_HasScreenKeyboardSupport)
 func HasScreenKeyboardSupport() bool {
 	ret, _, _ := hasScreenKeyboardSupport.Call()
-	return ret > 0
+	return ret != 0
+}
+
+// HideCursorScoped hides the mouse cursor until the returned restore function
+// is called, which shows it again if it was visible before. Defer the call so
+// the cursor comes back even if the code panics or returns early:
+//
+//	defer sdl.HideCursorScoped()()
+//
+// Calling restore more than once has no effect.
+func HideCursorScoped() (restore func()) {
+	wasShown, err := ShowCursor(QUERY)
+	if err != nil {
+		wasShown = ENABLE
+	}
+	ShowCursor(DISABLE)
+	var once sync.Once
+	return func() {
+		once.Do(func() { ShowCursor(wasShown) })
+	}
+}
+
+// HideScreenKeyboard stops text input, which hides the screen keyboard that
+// ShowScreenKeyboard showed.
+func HideScreenKeyboard() {
+	StopTextInput()
+}
+
+// screensaverInhibitor counts the active InhibitScreensaver calls.
+var screensaverInhibitor struct {
+	mu         sync.Mutex
+	count      int
+	wasEnabled bool
+}
+
+// InhibitScreensaver disables the screen saver until the returned release
+// function is called. Calls can overlap, the screen saver is only enabled
+// again after all of them were released, and only if it was enabled before
+// the first one. Calling release more than once has no effect.
+func InhibitScreensaver() (release func()) {
+	inhibitor := &screensaverInhibitor
+	inhibitor.mu.Lock()
+	defer inhibitor.mu.Unlock()
+	if inhibitor.count == 0 {
+		inhibitor.wasEnabled = IsScreenSaverEnabled()
+		DisableScreenSaver()
+	}
+	inhibitor.count++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			inhibitor.mu.Lock()
+			defer inhibitor.mu.Unlock()
+			inhibitor.count--
+			if inhibitor.count == 0 && inhibitor.wasEnabled {
+				EnableScreenSaver()
+			}
+		})
+	}
 }
 
 // Init initialize the SDL library. This must be called before using most other SDL functions.
@@ -3461,6 +4400,21 @@ func Init(flags uint32) error {
 	return nil
 }
 
+// InitHeadless initializes the subsystems INIT_TIMER, INIT_AUDIO, INIT_EVENTS
+// and INIT_JOYSTICK but not INIT_VIDEO, for command line tools that use SDL
+// audio or joystick input but never create a window. Call Quit when done.
+//
+// Without windows there is no window message loop, so SDL only reads input
+// devices when the event queue is pumped. Call PumpEvents, PollEvent or
+// WaitEventTimeout regularly, e.g. in the tool's main loop, otherwise joystick
+// events and states like Joystick.Button do not update. Audio callbacks run
+// on SDL's audio thread and do not need the event pump. Game controllers need
+// InitSubSystem(INIT_GAMECONTROLLER) in addition, which works headless as
+// well.
+func InitHeadless() error {
+	return Init(INIT_TIMER | INIT_AUDIO | INIT_EVENTS | INIT_JOYSTICK)
+}
+
 // InitSubSystem initializes specific SDL subsystems.
 // (https://wiki.libsdl.org/SDL_InitSubSystem)
 func InitSubSystem(flags uint32) error {
@@ -3471,24 +4425,126 @@ func InitSubSystem(flags uint32) error {
 	return nil
 }
 
-// IsGameController reports whether the given joystick is supported by the game controller interface.
-// (https://wiki.libsdl.org/SDL_IsGameController)
-func IsGameController(index int) bool {
-	ret, _, _ := isGameController.Call(uintptr(index))
-	return ret != 0
-}
-
-// IsScreenKeyboardShown reports whether the screen keyboard is shown for given window.
-// (https://wiki.libsdl.org/SDL_IsScreenKeyboardShown)
-func IsScreenKeyboardShown(window *Window) bool {
-	ret, _, _ := isScreenKeyboardShown.Call(uintptr(unsafe.Pointer(window)))
-	return ret > 0
+// subsystemRefs counts the active InitSubSystemScoped calls per subsystem
+// flag. owned are the flags of the subsystems that InitSubSystemScoped
+// initialized itself and thus has to quit.
+var subsystemRefs struct {
+	mu    sync.Mutex
+	count map[uint32]int
+	owned uint32
 }
 
-// IsScreenSaverEnabled reports whether the screensaver is currently enabled.
-// (https://wiki.libsdl.org/SDL_IsScreenSaverEnabled)
-func IsScreenSaverEnabled() bool {
-	ret, _, _ := isScreenSaverEnabled.Call()
+// InitSubSystemScoped initializes the subsystems in flags until the returned
+// release function is called. Libraries built on this package can use it to
+// require subsystems independently of each other and of the application:
+// calls can overlap and a subsystem is only shut down with QuitSubSystem after
+// all calls that required it were released. Subsystems that were already
+// initialized before, e.g. by Init, are left running. Calling release more
+// than once has no effect.
+//
+// Quit shuts down all subsystems regardless of the scoped calls, so only the
+// application should call it, at the very end.
+func InitSubSystemScoped(flags uint32) (release func(), err error) {
+	refs := &subsystemRefs
+	refs.mu.Lock()
+	defer refs.mu.Unlock()
+	if refs.count == nil {
+		refs.count = make(map[uint32]int)
+	}
+	var initFlags uint32
+	for bit := uint32(1); bit != 0; bit <<= 1 {
+		if flags&bit != 0 && refs.count[bit] == 0 && WasInit(bit) == 0 {
+			initFlags |= bit
+		}
+	}
+	if initFlags != 0 {
+		if err := InitSubSystem(initFlags); err != nil {
+			return func() {}, err
+		}
+	}
+	refs.owned |= initFlags
+	for bit := uint32(1); bit != 0; bit <<= 1 {
+		if flags&bit != 0 {
+			refs.count[bit]++
+		}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			refs.mu.Lock()
+			defer refs.mu.Unlock()
+			var quitFlags uint32
+			for bit := uint32(1); bit != 0; bit <<= 1 {
+				if flags&bit == 0 || refs.count[bit] == 0 {
+					continue
+				}
+				refs.count[bit]--
+				if refs.count[bit] == 0 && refs.owned&bit != 0 {
+					quitFlags |= bit
+				}
+			}
+			if quitFlags != 0 {
+				refs.owned &^= quitFlags
+				QuitSubSystem(quitFlags)
+			}
+		})
+	}, nil
+}
+
+// InitWithConfig sets the hints of the config and then initializes the
+// subsystems in config.Flags, e.g.
+//
+//	err := sdl.InitWithConfig(sdl.Config{
+//		Flags:        sdl.INIT_VIDEO | sdl.INIT_AUDIO,
+//		VSync:        true,
+//		ScaleQuality: sdl.SCALE_QUALITY_LINEAR,
+//	})
+func InitWithConfig(config Config) error {
+	config.Apply()
+	flags := config.Flags
+	if flags == 0 {
+		flags = INIT_EVERYTHING
+	}
+	return Init(flags)
+}
+
+// InstallPanicHandler makes Main handle panics in the main function and in
+// functions passed to Do, for shipped programs whose users never see the
+// console. On a panic, a crash log with the full stack trace is written to
+// the directory returned by GetPrefPath(org, app), or the temporary directory
+// if that fails. A message box then shows the error and the top of the stack
+// trace before the program terminates as usual. The message box uses app as
+// its title.
+func InstallPanicHandler(org, app string) {
+	panicHandler.installed = true
+	panicHandler.org = org
+	panicHandler.app = app
+}
+
+var panicHandler struct {
+	installed bool
+	org, app  string
+}
+
+// IsGameController reports whether the given joystick is supported by the game controller interface.
+// (https://wiki.libsdl.org/SDL_IsGameController)
+func IsGameController(index int) bool {
+	ret, _, _ := isGameController.Call(uintptr(index))
+	return ret != 0
+}
+
+// IsScreenKeyboardShown reports whether the screen keyboard is shown for given window.
+// (https://wiki.libsdl.org/SDL_IsScreenKeyboardShown)
+func IsScreenKeyboardShown(window *Window) bool {
+	ret, _, _ := isScreenKeyboardShown.Call(uintptr(unsafe.Pointer(window)))
+	return ret > 0
+}
+
+// IsScreenSaverEnabled reports whether the screensaver is currently enabled.
+// (https://wiki.libsdl.org/SDL_IsScreenSaverEnabled)
+func IsScreenSaverEnabled() bool {
+	ret, _, _ := isScreenSaverEnabled.Call()
 	return ret != 0
 }
 
@@ -3580,39 +4636,49 @@ func Log(str string, args ...interface{}) {
 
 // LogCritical logs a message with LOG_PRIORITY_CRITICAL.
 // (https://wiki.libsdl.org/SDL_LogCritical)
-func LogCritical(category int, str string, args ...interface{}) {
+func LogCritical(category LogCategory, str string, args ...interface{}) {
 	s := append([]byte(fmt.Sprintf(str, args...)), 0)
 	logCritical.Call(uintptr(category), uintptr(unsafe.Pointer(&s[0])))
 }
 
 // LogDebug logs a message with LOG_PRIORITY_DEBUG.
 // (https://wiki.libsdl.org/SDL_LogDebug)
-func LogDebug(category int, str string, args ...interface{}) {
+func LogDebug(category LogCategory, str string, args ...interface{}) {
 	s := append([]byte(fmt.Sprintf(str, args...)), 0)
 	logDebug.Call(uintptr(category), uintptr(unsafe.Pointer(&s[0])))
 }
 
 // LogError logs a message with LOG_PRIORITY_ERROR.
 // (https://wiki.libsdl.org/SDL_LogError)
-func LogError(category int, str string, args ...interface{}) {
+func LogError(category LogCategory, str string, args ...interface{}) {
 	s := append([]byte(fmt.Sprintf(str, args...)), 0)
 	logError.Call(uintptr(category), uintptr(unsafe.Pointer(&s[0])))
 }
 
 // LogInfo logs a message with LOG_PRIORITY_INFO.
 // (https://wiki.libsdl.org/SDL_LogInfo)
-func LogInfo(category int, str string, args ...interface{}) {
+func LogInfo(category LogCategory, str string, args ...interface{}) {
 	s := append([]byte(fmt.Sprintf(str, args...)), 0)
 	logInfo.Call(uintptr(category), uintptr(unsafe.Pointer(&s[0])))
 }
 
 // LogMessage logs a message with the specified category and priority.
 // (https://wiki.libsdl.org/SDL_LogMessage)
-func LogMessage(category int, pri LogPriority, str string, args ...interface{}) {
+func LogMessage(category LogCategory, pri LogPriority, str string, args ...interface{}) {
 	s := append([]byte(fmt.Sprintf(str, args...)), 0)
 	logMessage.Call(uintptr(category), uintptr(pri), uintptr(unsafe.Pointer(&s[0])))
 }
 
+// LogResetOutputFunction restores SDL's default log output function after
+// LogSetOutputFunction, LogSetOutputWriter or LogSetOutputHandler replaced it.
+func LogResetOutputFunction() {
+	if !logDefault.saved {
+		return
+	}
+	logSetOutputFunction.Call(logDefault.f, logDefault.data)
+	logCtx = logOutputFunctionCtx{}
+}
+
 // LogResetPriorities resets all priorities to default.
 // (https://wiki.libsdl.org/SDL_LogResetPriorities)
 func LogResetPriorities() {
@@ -3628,6 +4694,13 @@ func LogSetAllPriority(p LogPriority) {
 // LogSetOutputFunction replaces the default log output function with one of your own.
 // (https://wiki.libsdl.org/SDL_LogSetOutputFunction)
 func LogSetOutputFunction(f LogOutputFunction, data interface{}) {
+	if !logDefault.saved {
+		logGetOutputFunction.Call(
+			uintptr(unsafe.Pointer(&logDefault.f)),
+			uintptr(unsafe.Pointer(&logDefault.data)),
+		)
+		logDefault.saved = true
+	}
 	logCtx.f = f
 	logCtx.data = data
 	logSetOutputFunction.Call(
@@ -3637,9 +4710,9 @@ func LogSetOutputFunction(f LogOutputFunction, data interface{}) {
 }
 
 // Yissakhar Z. Beck (DeedleFake)'s implementation
-func theLogOutputFunction(data uintptr, category int, pri LogPriority, message uintptr) uintptr {
+func theLogOutputFunction(data uintptr, category uintptr, pri LogPriority, message uintptr) uintptr {
 	ctx := (*logOutputFunctionCtx)(unsafe.Pointer(data))
-	ctx.f(ctx.data, category, pri, sdlToGoString(message))
+	ctx.f(ctx.data, LogCategory(int32(category)), pri, sdlToGoString(message))
 	return 0
 }
 
@@ -3652,22 +4725,37 @@ type logOutputFunctionCtx struct {
 
 var logCtx logOutputFunctionCtx
 
+// logDefault is SDL's own output function, saved before it is first replaced
+// so LogResetOutputFunction can restore it.
+var logDefault struct {
+	saved   bool
+	f, data uintptr
+}
+
+// LogSetOutputWriter replaces the default log output function with one that
+// writes every message to w as a line of the form "[category] PRIORITY: message".
+func LogSetOutputWriter(w io.Writer) {
+	LogSetOutputFunction(func(_ interface{}, category LogCategory, pri LogPriority, message string) {
+		fmt.Fprintf(w, "[%s] %s: %s\n", category, strings.ToUpper(pri.String()), message)
+	}, nil)
+}
+
 // LogSetPriority sets the priority of a particular log category.
 // (https://wiki.libsdl.org/SDL_LogSetPriority)
-func LogSetPriority(category int, p LogPriority) {
+func LogSetPriority(category LogCategory, p LogPriority) {
 	logSetPriority.Call(uintptr(category), uintptr(p))
 }
 
 // LogVerbose logs a message with LOG_PRIORITY_VERBOSE.
 // (https://wiki.libsdl.org/SDL_LogVerbose)
-func LogVerbose(category int, str string, args ...interface{}) {
+func LogVerbose(category LogCategory, str string, args ...interface{}) {
 	s := append([]byte(fmt.Sprintf(str, args...)), 0)
 	logVerbose.Call(uintptr(category), uintptr(unsafe.Pointer(&s[0])))
 }
 
 // LogWarn logs a message with LOG_PRIORITY_WARN.
 // (https://wiki.libsdl.org/SDL_LogWarn)
-func LogWarn(category int, str string, args ...interface{}) {
+func LogWarn(category LogCategory, str string, args ...interface{}) {
 	s := append([]byte(fmt.Sprintf(str, args...)), 0)
 	logWarn.Call(uintptr(category), uintptr(unsafe.Pointer(&s[0])))
 }
@@ -3705,6 +4793,9 @@ func Main(main func()) {
 	callInMain = func(f func()) {
 		done := make(chan bool, 1)
 		callQueue <- func() {
+			if panicHandler.installed {
+				defer HandlePanic()
+			}
 			f()
 			done <- true
 		}
@@ -3712,6 +4803,9 @@ func Main(main func()) {
 	}
 
 	go func() {
+		if panicHandler.installed {
+			defer HandlePanic()
+		}
 		main()
 		// fmt.Println("END") // to check if os.Exit(..) is called by main() above
 		close(callQueue)
@@ -3722,6 +4816,15 @@ func Main(main func()) {
 	}
 }
 
+// Malloc allocates size bytes with SDL's allocator. Use it for memory that is
+// passed to SDL functions that take ownership of it and free it with Free
+// otherwise. It returns nil if the memory cannot be allocated.
+// (https://wiki.libsdl.org/SDL_malloc)
+func Malloc(size int) unsafe.Pointer {
+	ret, _, _ := malloc.Call(uintptr(size))
+	return unsafe.Pointer(ret)
+}
+
 // MapRGB maps an RGB triple to an opaque pixel value for a given pixel format.
 // (https://wiki.libsdl.org/SDL_MapRGB)
 func MapRGB(format *PixelFormat, r, g, b uint8) uint32 {
@@ -3760,9 +4863,26 @@ func MasksToPixelFormatEnum(bpp int, rmask, gmask, bmask, amask uint32) uint {
 	return uint(ret)
 }
 
+// Memcpy copies n bytes from src to dst, the memory areas must not overlap.
+// (https://wiki.libsdl.org/SDL_memcpy)
+func Memcpy(dst, src unsafe.Pointer, n int) {
+	memcpy.Call(uintptr(dst), uintptr(src), uintptr(n))
+}
+
 // MixAudio mixes audio data. New programs might want to use MixAudioFormat() instead.
+// Only as many bytes as fit into both dst and src are mixed.
+// (https://wiki.libsdl.org/SDL_MixAudio)
+func MixAudio(dst, src []byte, volume Volume) {
+	n := mixLength(dst, src)
+	if n == 0 {
+		return
+	}
+	MixAudioRaw(&dst[0], &src[0], uint32(n), int(volume.Clamp()))
+}
+
+// MixAudioRaw is MixAudio for raw pointers and a length in bytes.
 // (https://wiki.libsdl.org/SDL_MixAudio)
-func MixAudio(dst, src *uint8, len uint32, volume int) {
+func MixAudioRaw(dst, src *uint8, len uint32, volume int) {
 	mixAudio.Call(
 		uintptr(unsafe.Pointer(dst)),
 		uintptr(unsafe.Pointer(src)),
@@ -3772,8 +4892,19 @@ func MixAudio(dst, src *uint8, len uint32, volume int) {
 }
 
 // MixAudioFormat mixes audio data in a specified format.
+// Only as many bytes as fit into both dst and src are mixed.
+// (https://wiki.libsdl.org/SDL_MixAudioFormat)
+func MixAudioFormat(dst, src []byte, format AudioFormat, volume Volume) {
+	n := mixLength(dst, src)
+	if n == 0 {
+		return
+	}
+	MixAudioFormatRaw(&dst[0], &src[0], format, uint32(n), int(volume.Clamp()))
+}
+
+// MixAudioFormatRaw is MixAudioFormat for raw pointers and a length in bytes.
 // (https://wiki.libsdl.org/SDL_MixAudioFormat)
-func MixAudioFormat(dst, src *uint8, format AudioFormat, len uint32, volume int) {
+func MixAudioFormatRaw(dst, src *uint8, format AudioFormat, len uint32, volume int) {
 	mixAudioFormat.Call(
 		uintptr(unsafe.Pointer(dst)),
 		uintptr(unsafe.Pointer(src)),
@@ -3783,6 +4914,13 @@ func MixAudioFormat(dst, src *uint8, format AudioFormat, len uint32, volume int)
 	)
 }
 
+func mixLength(dst, src []byte) int {
+	if len(src) < len(dst) {
+		return len(src)
+	}
+	return len(dst)
+}
+
 // MouseIsHaptic reports whether or not the current mouse has haptic capabilities.
 // (https://wiki.libsdl.org/SDL_MouseIsHaptic)
 func MouseIsHaptic() (bool, error) {
@@ -3790,6 +4928,79 @@ func MouseIsHaptic() (bool, error) {
 	return ret != 0, errorFromInt(int(ret))
 }
 
+// NegotiateAudioSpec returns the spec to request from OpenAudioDevice so that
+// SDL does not have to convert or resample the audio. The fields that
+// allowedChanges, a combination of the AUDIO_ALLOW_* flags, permit to change
+// are replaced by the preferred values of the default output device, the
+// others are kept from desired. The audio subsystem must be initialized.
+//
+// The default device's spec is known since SDL 2.24.0, SDL 2.0.16 knows the
+// spec of the first output device, which is used instead. With older versions
+// desired is returned unchanged.
+func NegotiateAudioSpec(desired AudioSpec, allowedChanges int) AudioSpec {
+	var device AudioSpec
+	var err error
+	if getDefaultAudioInfo.Find() == nil {
+		_, device, err = GetDefaultAudioInfo(false)
+	} else if getAudioDeviceSpec.Find() == nil && GetNumAudioDevices(false) > 0 {
+		device, err = GetAudioDeviceSpec(0, false)
+	} else {
+		return desired
+	}
+	if err != nil {
+		return desired
+	}
+
+	spec := desired
+	if allowedChanges&AUDIO_ALLOW_FREQUENCY_CHANGE != 0 && device.Freq != 0 {
+		spec.Freq = device.Freq
+	}
+	if allowedChanges&AUDIO_ALLOW_FORMAT_CHANGE != 0 && device.Format != 0 {
+		spec.Format = device.Format
+	}
+	if allowedChanges&AUDIO_ALLOW_CHANNELS_CHANGE != 0 && device.Channels != 0 {
+		spec.Channels = device.Channels
+	}
+	if allowedChanges&AUDIO_ALLOW_SAMPLES_CHANGE != 0 && device.Samples != 0 {
+		spec.Samples = device.Samples
+	}
+	return spec
+}
+
+// NormalizeAxis converts a raw joystick or game controller axis value to the
+// range -1 to 1. Values whose magnitude is below the dead zone, which is a
+// fraction of the full range between 0 and 1, become 0 and the remaining range
+// is rescaled so the output still starts at 0 right outside the dead zone,
+// instead of jumping to the dead zone value. Use a StickState for the two axes
+// of an analog stick, clipping each axis separately distorts diagonals.
+func NormalizeAxis(value int16, deadZone float32) float32 {
+	v := float32(value) / 32767
+	if v < -1 {
+		v = -1
+	}
+	return applyDeadZone(v, deadZone)
+}
+
+// applyDeadZone rescales v, which is in the range -1 to 1, so that the range
+// from deadZone to 1 maps to 0 to 1, keeping the sign.
+func applyDeadZone(v, deadZone float32) float32 {
+	if deadZone < 0 {
+		deadZone = 0
+	}
+	magnitude := v
+	if magnitude < 0 {
+		magnitude = -magnitude
+	}
+	if magnitude <= deadZone || deadZone >= 1 {
+		return 0
+	}
+	scaled := (magnitude - deadZone) / (1 - deadZone)
+	if v < 0 {
+		return -scaled
+	}
+	return scaled
+}
+
 // NumHaptics returns the number of haptic devices attached to the system.
 // (https://wiki.libsdl.org/SDL_NumHaptics)
 func NumHaptics() (int, error) {
@@ -3811,6 +5022,31 @@ func NumSensors() int {
 	return int(ret)
 }
 
+// OnQuit sets a function that is called when PollEvent, WaitEvent or
+// WaitEventTimeout receive a QUIT event, e.g. because the user closed the last
+// window. If the handler returns true, the QUIT event is returned as usual. If
+// it returns false, the event is dropped, which cancels the quit. Pass nil to
+// remove the handler.
+//
+// The handler is called on the goroutine that polls for events, so it can
+// safely save the game or show a confirmation dialog. See QuitOnInterrupt to
+// handle Ctrl+C in a console the same way.
+func OnQuit(handler func() bool) {
+	quitHandler = handler
+}
+
+// quitHandler is the function set with OnQuit.
+var quitHandler func() bool
+
+// quitVetoed calls the OnQuit handler for QUIT events and reports whether it
+// cancelled the quit.
+func quitVetoed(e Event) bool {
+	if _, ok := e.(*QuitEvent); ok && quitHandler != nil {
+		return !quitHandler()
+	}
+	return false
+}
+
 // OpenAudio opens the audio device. New programs might want to use OpenAudioDevice() instead.
 // (https://wiki.libsdl.org/SDL_OpenAudio)
 func OpenAudio(desired, obtained *AudioSpec) error {
@@ -3821,6 +5057,7 @@ func OpenAudio(desired, obtained *AudioSpec) error {
 	if ret != 0 {
 		return GetError()
 	}
+	trackAudioDevice(legacyAudioDevice, true)
 	return nil
 }
 
@@ -3864,10 +5101,24 @@ func PeepEvents(events []Event, action EventAction, minType, maxType uint32) (st
 	)
 	storedEvents = int(ret)
 
-	if action != ADDEVENT { // put events into slice, events unchanged if action = ADDEVENT
+	if action == ADDEVENT {
+		added := storedEvents
+		if added < 0 {
+			added = 0
+		}
+		for i := added; i < len(_events); i++ {
+			freeCEvent(&_events[i])
+		}
+	}
+
+	if action == PEEKEVENT {
 		for i := 0; i < storedEvents; i++ {
 			events[i] = goEvent(&_events[i])
 		}
+	} else if action == GETEVENT {
+		for i := 0; i < storedEvents; i++ {
+			events[i] = takeEvent(&_events[i])
+		}
 	}
 
 	if storedEvents < 0 {
@@ -3877,10 +5128,48 @@ func PeepEvents(events []Event, action EventAction, minType, maxType uint32) (st
 	return
 }
 
+// cEvent copies the event into a new CEvent. SDL always reads a whole event
+// union which is larger than most of the Go event structs.
 func cEvent(event Event) *CEvent {
-	evv := reflect.ValueOf(event)
-	p := evv.Elem()
-	return (*CEvent)(unsafe.Pointer(p.UnsafeAddr()))
+	var e CEvent
+	if drop, ok := event.(*DropEvent); ok {
+		// The Go string cannot be copied as is, SDL expects a char* that it
+		// can free.
+		c := (*tDropEvent)(unsafe.Pointer(&e))
+		c.Type = drop.Type
+		c.Timestamp = drop.Timestamp
+		c.WindowID = drop.WindowID
+		if drop.Type == DROPFILE || drop.Type == DROPTEXT {
+			c.File = newSDLString(drop.File)
+		}
+		return &e
+	}
+	p := reflect.ValueOf(event).Elem()
+	n := p.Type().Size()
+	if n > unsafe.Sizeof(e) {
+		n = unsafe.Sizeof(e)
+	}
+	dst := (*[unsafe.Sizeof(CEvent{})]byte)(unsafe.Pointer(&e))
+	src := (*[unsafe.Sizeof(CEvent{})]byte)(unsafe.Pointer(p.UnsafeAddr()))
+	copy(dst[:n], src[:n])
+	return &e
+}
+
+// copyEventBack copies the CEvent that was created by cEvent back into the Go
+// event, e.g. after SDL set its timestamp.
+func copyEventBack(event Event, e *CEvent) {
+	if drop, ok := event.(*DropEvent); ok {
+		drop.Timestamp = (*tDropEvent)(unsafe.Pointer(e)).Timestamp
+		return
+	}
+	p := reflect.ValueOf(event).Elem()
+	n := p.Type().Size()
+	if n > unsafe.Sizeof(*e) {
+		n = unsafe.Sizeof(*e)
+	}
+	dst := (*[unsafe.Sizeof(CEvent{})]byte)(unsafe.Pointer(p.UnsafeAddr()))
+	src := (*[unsafe.Sizeof(CEvent{})]byte)(unsafe.Pointer(e))
+	copy(dst[:n], src[:n])
 }
 
 // PixelFormatEnumToMasks converts one of the enumerated pixel formats to a bpp value and RGBA masks.
@@ -3900,6 +5189,81 @@ func PixelFormatEnumToMasks(format uint) (bpp int, rmask, gmask, bmask, amask ui
 	return
 }
 
+// PlaceNearCursor returns the global position for a popup or tooltip window of
+// size w x h so that its top-left corner is offset from the mouse cursor. If
+// it would stick out of the usable area of the cursor's display, i.e. without
+// the task bar, it is moved to the other side of the cursor and, if it is
+// still too large, pushed inside the display.
+func PlaceNearCursor(w, h, offsetX, offsetY int32) (x, y int32, err error) {
+	mx, my, _ := GetGlobalMouseState()
+	index, _, err := displayAt(Point{X: mx, Y: my})
+	if err != nil {
+		return 0, 0, err
+	}
+	usable, err := GetDisplayUsableBounds(index)
+	if err != nil {
+		return 0, 0, err
+	}
+	place := func(cursor, offset, size, min, max int32) int32 {
+		pos := cursor + offset
+		if pos+size > max {
+			pos = cursor - offset - size
+		}
+		if pos+size > max {
+			pos = max - size
+		}
+		if pos < min {
+			pos = min
+		}
+		return pos
+	}
+	x = place(mx, offsetX, w, usable.X, usable.X+usable.W)
+	y = place(my, offsetY, h, usable.Y, usable.Y+usable.H)
+	return x, y, nil
+}
+
+// PremultiplyAlpha premultiplies the alpha on a block of pixels. It returns
+// ErrInvalidParameters if src or dst are too small for width x height pixels
+// with the given format and pitch.
+// This function requires SDL 2.0.18 or newer.
+// (https://wiki.libsdl.org/SDL_PremultiplyAlpha)
+func PremultiplyAlpha(width, height int, srcFormat uint32, src []byte, srcPitch int, dstFormat uint32, dst []byte, dstPitch int) error {
+	if !isPixelBlock(width, height, srcFormat, src, srcPitch) ||
+		!isPixelBlock(width, height, dstFormat, dst, dstPitch) {
+		return ErrInvalidParameters
+	}
+	if err := premultiplyAlpha.Find(); err != nil {
+		return err
+	}
+	ret, _, _ := premultiplyAlpha.Call(
+		uintptr(width),
+		uintptr(height),
+		uintptr(srcFormat),
+		uintptr(unsafe.Pointer(&src[0])),
+		uintptr(srcPitch),
+		uintptr(dstFormat),
+		uintptr(unsafe.Pointer(&dst[0])),
+		uintptr(dstPitch),
+	)
+	return errorFromInt(int(ret))
+}
+
+// isPixelBlock returns true if pixels holds height rows of width pixels in the
+// format, each row pitch bytes long.
+func isPixelBlock(width, height int, format uint32, pixels []byte, pitch int) bool {
+	bpp := BytesPerPixel(format)
+	return width > 0 && height > 0 && bpp > 0 &&
+		pitch >= width*bpp && len(pixels) >= pitch*height
+}
+
+// freeCEvent frees the memory that cEvent allocated for an event that did not
+// make it into the event queue.
+func freeCEvent(e *CEvent) {
+	if e.Type == DROPFILE || e.Type == DROPTEXT {
+		free.Call(uintptr((*tDropEvent)(unsafe.Pointer(e)).File))
+	}
+}
+
 // PumpEvents pumps the event loop, gathering events from the input devices.
 // (https://wiki.libsdl.org/SDL_PumpEvents)
 func PumpEvents() {
@@ -3911,6 +5275,10 @@ func PumpEvents() {
 func PushEvent(event Event) (filtered bool, err error) {
 	e := cEvent(event)
 	ret, _, _ := pushEvent.Call(uintptr(unsafe.Pointer(e)))
+	copyEventBack(event, e)
+	if int(ret) <= 0 {
+		freeCEvent(e)
+	}
 	if int(ret) < 0 {
 		filtered, err = false, GetError()
 	} else if ret == 0 {
@@ -3947,12 +5315,68 @@ func Quit() {
 	eventFilterCache = nil
 	eventWatches = make(map[EventWatchHandle]*eventFilterCallbackContext)
 	lastEventWatchHandle = 0
+	rendererStates = make(map[*Renderer][]rendererState)
+	drawColorStacks = make(map[*Renderer][]Color)
+	targetStacks = make(map[*Renderer][]*Texture)
+	windows = nil
+	for _, c := range windowEvents {
+		close(c)
+	}
+	windowEvents = make(map[uint32]chan Event)
+	windowedGeometry = make(map[*Window]Rect)
+	quitHandler = nil
+	forgetAudioDevices()
+	subsystemRefs.mu.Lock()
+	subsystemRefs.count = nil
+	subsystemRefs.owned = 0
+	subsystemRefs.mu.Unlock()
+}
+
+// QuitOnInterrupt turns interrupt signals, i.e. pressing Ctrl+C in the
+// console, into QUIT events. This way they take the same path as closing the
+// window, including the OnQuit handler, instead of terminating the program
+// right away. Call the returned function to restore the default behavior.
+func QuitOnInterrupt() (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-signals:
+				pushQuitEvent()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
+
+// pushQuitEvent adds a QUIT event to the event queue. It can be called from
+// any goroutine.
+func pushQuitEvent() {
+	// A full size event is pushed because SDL copies a whole SDL_Event.
+	var e CEvent
+	e.Type = QUIT
+	pushEvent.Call(uintptr(unsafe.Pointer(&e)))
 }
 
 // QuitSubSystem shuts down specific SDL subsystems.
 // (https://wiki.libsdl.org/SDL_QuitSubSystem)
 func QuitSubSystem(flags uint32) {
 	quitSubSystem.Call(uintptr(flags))
+	// SDL counts the initializations of each subsystem, audio might still be
+	// running.
+	if flags&INIT_AUDIO != 0 && WasInit(INIT_AUDIO) == 0 {
+		forgetAudioDevices()
+	}
 }
 
 // RegisterEvents allocates a set of user-defined events, and return the beginning event number for that set of events.
@@ -3962,6 +5386,85 @@ func RegisterEvents(numEvents int) uint32 {
 	return uint32(ret)
 }
 
+// EventLoopTimestep is the fixed time step that RunEventLoop passes to its
+// frame function.
+var EventLoopTimestep = time.Second / 60
+
+// maxFrameCatchUp limits the number of frames that RunEventLoop runs back to
+// back after the program was stalled, e.g. while a window was being dragged.
+const maxFrameCatchUp = 5
+
+// RelativeMouseModeScoped enables relative mouse mode, which hides the cursor
+// and reports only relative mouse motion, until the returned restore function
+// is called. It then goes back to the previous mode. Defer the call so the
+// mode is restored even if the code panics or returns early. Calling restore
+// more than once has no effect.
+func RelativeMouseModeScoped() (restore func(), err error) {
+	wasRelative := GetRelativeMouseMode()
+	if SetRelativeMouseMode(true) != 0 {
+		return func() {}, GetError()
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() { SetRelativeMouseMode(wasRelative) })
+	}, nil
+}
+
+// RenderDrivers returns information about all render drivers that are
+// available, in the order of their indices for CreateRenderer. Together with
+// RendererInfo.String it can be used for graphics diagnostics and bug
+// reports. The info of drivers that cannot be queried is left empty.
+func RenderDrivers() []RendererInfo {
+	n, err := GetNumRenderDrivers()
+	if err != nil || n <= 0 {
+		return nil
+	}
+	drivers := make([]RendererInfo, n)
+	for i := range drivers {
+		GetRenderDriverInfo(i, &drivers[i])
+	}
+	return drivers
+}
+
+// RunEventLoop runs a main loop until ctx is done. Events are passed to
+// handler as they arrive and frame is called once for every
+// EventLoopTimestep that passed, always with that fixed dt. While waiting for
+// the next frame the loop sleeps in WaitEventTimeout. Either function may be
+// nil. Like all event functions, RunEventLoop must be called on the main
+// thread.
+func RunEventLoop(ctx context.Context, handler func(Event), frame func(dt time.Duration)) {
+	step := EventLoopTimestep
+	last := time.Now()
+	var accumulated time.Duration
+	for ctx.Err() == nil {
+		wait := step - accumulated
+		if wait < 0 {
+			wait = 0
+		}
+		for e := WaitEventTimeout(int(wait / time.Millisecond)); e != nil; e = PollEvent() {
+			if handler != nil {
+				handler(e)
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		now := time.Now()
+		accumulated += now.Sub(last)
+		last = now
+		if accumulated > maxFrameCatchUp*step {
+			accumulated = maxFrameCatchUp * step
+		}
+		for accumulated >= step && ctx.Err() == nil {
+			if frame != nil {
+				frame(step)
+			}
+			accumulated -= step
+		}
+	}
+}
+
 // SaveAllDollarTemplates saves all currently loaded Dollar Gesture templates.
 // (https://wiki.libsdl.org/SDL_SaveAllDollarTemplates)
 func SaveAllDollarTemplates(src *RWops) int {
@@ -3969,6 +5472,122 @@ func SaveAllDollarTemplates(src *RWops) int {
 	return int(ret)
 }
 
+// SaveWAV writes the audio data as a WAVE file, see WriteWAV.
+func SaveWAV(path string, spec AudioSpec, data []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WriteWAV(f, spec, data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteWAV writes the audio data in the format of the spec as a WAVE file.
+// Only Freq, Format and Channels of the spec are used. WAVE files store 8 bit
+// samples unsigned and all others signed and in little-endian byte order, the
+// data is converted accordingly. Unsigned 16 bit samples thus lose nothing.
+func WriteWAV(w io.Writer, spec AudioSpec, data []byte) error {
+	format := spec.Format
+	bits := int(format.BitSize())
+	if bits != 8 && bits != 16 && bits != 32 {
+		return fmt.Errorf("sdl: cannot write %d bit audio as WAVE", bits)
+	}
+	if spec.Channels == 0 || spec.Freq <= 0 {
+		return errors.New("sdl: WAVE spec needs a frequency and channels")
+	}
+	bytesPerSample := bits / 8
+	frameSize := bytesPerSample * int(spec.Channels)
+	if len(data)%frameSize != 0 {
+		return fmt.Errorf(
+			"sdl: audio data length %d is not a multiple of the frame size %d",
+			len(data), frameSize,
+		)
+	}
+
+	samples := make([]byte, len(data))
+	copy(samples, data)
+	for i := 0; i < len(samples); i += bytesPerSample {
+		sample := samples[i : i+bytesPerSample]
+		if format.IsBigEndian() {
+			for a, b := 0, len(sample)-1; a < b; a, b = a+1, b-1 {
+				sample[a], sample[b] = sample[b], sample[a]
+			}
+		}
+		// Flip the sign bit, which is now in the last byte, to convert between
+		// signed and unsigned samples.
+		if bits == 8 && format.IsSigned() || bits == 16 && format.IsUnsigned() {
+			sample[len(sample)-1] ^= 0x80
+		}
+	}
+
+	const waveFormatPCM, waveFormatFloat = 1, 3
+	tag := uint16(waveFormatPCM)
+	if format.IsFloat() {
+		tag = waveFormatFloat
+	}
+	var header [44]byte
+	le := binary.LittleEndian
+	copy(header[0:], "RIFF")
+	le.PutUint32(header[4:], uint32(36+len(samples)+len(samples)%2))
+	copy(header[8:], "WAVE")
+	copy(header[12:], "fmt ")
+	le.PutUint32(header[16:], 16)
+	le.PutUint16(header[20:], tag)
+	le.PutUint16(header[22:], uint16(spec.Channels))
+	le.PutUint32(header[24:], uint32(spec.Freq))
+	le.PutUint32(header[28:], uint32(int(spec.Freq)*frameSize))
+	le.PutUint16(header[32:], uint16(frameSize))
+	le.PutUint16(header[34:], uint16(bits))
+	copy(header[36:], "data")
+	le.PutUint32(header[40:], uint32(len(samples)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	if len(samples)%2 == 1 {
+		// RIFF chunks are padded to an even size, the pad byte is not part of
+		// the data size in the header.
+		samples = append(samples, 0)
+	}
+	_, err := w.Write(samples)
+	return err
+}
+
+// Scope starts timing a section of code. The returned function ends it and
+// calls report with the elapsed time, e.g.
+//
+// 	defer sdl.Scope(func(d time.Duration) { stats.UpdateTime = d })()
+//
+// See Profiler for collecting the times of several named sections.
+func Scope(report func(elapsed time.Duration)) (end func()) {
+	start := GetPerformanceCounter()
+	return func() {
+		report(countsToDuration(GetPerformanceCounter() - start))
+	}
+}
+
+// performanceFrequency caches GetPerformanceFrequency, it does not change
+// while the program runs.
+var performanceFrequency struct {
+	once  sync.Once
+	value uint64
+}
+
+// countsToDuration converts a difference of performance counter values to a
+// time.Duration.
+func countsToDuration(counts uint64) time.Duration {
+	performanceFrequency.once.Do(func() {
+		performanceFrequency.value = GetPerformanceFrequency()
+	})
+	f := performanceFrequency.value
+	// Split into whole seconds and the rest so that the multiplication
+	// cannot overflow for long durations.
+	seconds, rest := counts/f, counts%f
+	return time.Duration(seconds)*time.Second + time.Duration(rest*uint64(time.Second)/f)
+}
+
 // SensorGetDeviceName gets the implementation dependent name of a sensor.
 //
 // This can be called before any sensors are opened.
@@ -4022,7 +5641,9 @@ func SetCursor(cursor *Cursor) {
 // (https://wiki.libsdl.org/SDL_SetError)
 func SetError(err error) {
 	var msg string
-	if err != nil {
+	if e, ok := err.(*CallError); ok {
+		msg = e.Msg
+	} else if err != nil {
 		msg = err.Error()
 	}
 	m := append([]byte(msg), 0)
@@ -4092,6 +5713,84 @@ func SetHintWithPriority(name, value string, hp HintPriority) bool {
 	return ret != 0
 }
 
+// SetMemoryAllocator makes SDL allocate its memory through the Go allocator
+// a. The allocator is called from C, possibly on SDL's own threads, so it has
+// to be safe for concurrent use and must not return memory that is managed by
+// the Go garbage collector. If a is a MemoryFunctions, it is passed to
+// SetMemoryFunctions directly.
+//
+// Wrapping the functions returned by GetMemoryFunctions is a way to
+// instrument SDL's allocations, e.g.
+//
+//	type countingAllocator struct {
+//		sdl.MemoryFunctions
+//		mallocs int64
+//	}
+//
+//	func (a *countingAllocator) Malloc(size uintptr) unsafe.Pointer {
+//		atomic.AddInt64(&a.mallocs, 1)
+//		return a.MemoryFunctions.Malloc(size)
+//	}
+//
+//	sdl.SetMemoryAllocator(&countingAllocator{MemoryFunctions: sdl.GetMemoryFunctions()})
+//
+// Like SetMemoryFunctions, call it before any other SDL function.
+func SetMemoryAllocator(a MemoryAllocator) error {
+	if f, ok := a.(MemoryFunctions); ok {
+		return SetMemoryFunctions(f)
+	}
+	memoryAllocatorOnce.Do(func() {
+		memoryAllocatorCallbacks = MemoryFunctions{
+			MallocFunc: syscall.NewCallbackCDecl(func(size uintptr) uintptr {
+				return uintptr(currentMemoryAllocator().Malloc(size))
+			}),
+			CallocFunc: syscall.NewCallbackCDecl(func(n, size uintptr) uintptr {
+				return uintptr(currentMemoryAllocator().Calloc(n, size))
+			}),
+			ReallocFunc: syscall.NewCallbackCDecl(func(mem, size uintptr) uintptr {
+				return uintptr(currentMemoryAllocator().Realloc(unsafe.Pointer(mem), size))
+			}),
+			FreeFunc: syscall.NewCallbackCDecl(func(mem uintptr) uintptr {
+				currentMemoryAllocator().Free(unsafe.Pointer(mem))
+				return 0
+			}),
+		}
+	})
+	old := memoryAllocator.Load()
+	memoryAllocator.Store(&a)
+	if err := SetMemoryFunctions(memoryAllocatorCallbacks); err != nil {
+		memoryAllocator.Store(old)
+		return err
+	}
+	return nil
+}
+
+// Go callbacks cannot be released, so SetMemoryAllocator creates the C
+// callable functions once and lets them call the current allocator.
+var (
+	memoryAllocator          atomic.Value // *MemoryAllocator
+	memoryAllocatorOnce      sync.Once
+	memoryAllocatorCallbacks MemoryFunctions
+)
+
+func currentMemoryAllocator() MemoryAllocator {
+	return *memoryAllocator.Load().(*MemoryAllocator)
+}
+
+// SetMemoryFunctions replaces the functions SDL uses to allocate memory. It
+// has to be called before any other SDL function, otherwise SDL might free
+// memory with functions that did not allocate it.
+// (https://wiki.libsdl.org/SDL_SetMemoryFunctions)
+func SetMemoryFunctions(f MemoryFunctions) error {
+	ret, _, _ := setMemoryFunctions.Call(
+		f.MallocFunc,
+		f.CallocFunc,
+		f.ReallocFunc,
+		f.FreeFunc,
+	)
+	return errorFromInt(int(ret))
+}
+
 // SetModState sets the current key modifier state for the keyboard.
 // (https://wiki.libsdl.org/SDL_SetModState)
 func SetModState(mod Keymod) {
@@ -4175,6 +5874,22 @@ func ShowMessageBox(data *MessageBoxData) (buttonid int, err error) {
 	return
 }
 
+// ShowScreenKeyboard starts text input, which shows the screen keyboard on
+// platforms that support one, see HasScreenKeyboardSupport. The rect is the
+// text field that is being edited, in window coordinates, so the keyboard and
+// any input method candidate list do not cover it. It may be nil. Use
+// Window.IsScreenKeyboardShown to check whether the keyboard is visible and
+// HideScreenKeyboard to close it. With SDL 2.28.0 and later, setting
+// HINT_ENABLE_SCREEN_KEYBOARD to "0" keeps the keyboard hidden while text
+// input is active. Older versions, like the SDL 2.0.10 DLLs that come with this
+// package, ignore the hint.
+func ShowScreenKeyboard(rect *Rect) {
+	if rect != nil {
+		SetTextInputRect(rect)
+	}
+	StartTextInput()
+}
+
 // ShowSimpleMessageBox displays a simple modal message box.
 // (https://wiki.libsdl.org/SDL_ShowSimpleMessageBox)
 func ShowSimpleMessageBox(flags uint32, title, message string, window *Window) error {
@@ -4189,6 +5904,24 @@ func ShowSimpleMessageBox(flags uint32, title, message string, window *Window) e
 	return errorFromInt(int(ret))
 }
 
+// StartLeakCheck returns a function that reports how many more allocations
+// SDL has outstanding than when StartLeakCheck was called. Use it to verify
+// that code frees everything it allocates through SDL, e.g.
+//
+//	outstanding := sdl.StartLeakCheck()
+//	loadAndFreeLevel()
+//	if n := outstanding(); n != 0 {
+//		log.Println(n, "SDL allocations leaked")
+//	}
+//
+// The count includes allocations of other threads, like SDL's audio thread.
+func StartLeakCheck() (outstanding func() int) {
+	start := GetNumAllocations()
+	return func() int {
+		return GetNumAllocations() - start
+	}
+}
+
 // StartTextInput starts accepting Unicode text input events.
 // (https://wiki.libsdl.org/SDL_StartTextInput)
 func StartTextInput() {
@@ -4320,52 +6053,395 @@ func WasInit(flags uint32) uint32 {
 	return uint32(ret)
 }
 
-// AudioCVT contains audio data conversion information.
-// (https://wiki.libsdl.org/SDL_AudioCVT)
-type AudioCVT struct {
-	Needed      int32           // set to 1 if conversion possible
-	SrcFormat   AudioFormat     // source audio format
-	DstFormat   AudioFormat     // target audio format
-	RateIncr    float64         // rate conversion increment
-	Buf         unsafe.Pointer  // the buffer to hold entire audio data. Use AudioCVT.BufAsSlice() for access via a Go slice
-	Len         int32           // length of original audio buffer
-	LenCVT      int32           // length of converted audio buffer
-	LenMult     int32           // buf must be len*len_mult big
-	LenRatio    float64         // given len, final size is len*len_ratio
-	filters     [10]AudioFilter // filter list (internal use)
-	filterIndex int32           // current audio conversion function (internal use)
-	bufData     []byte
+// WatchJoystickBattery returns a channel that receives an event every time
+// the battery level of an opened joystick changes, e.g. to warn the player
+// that a wireless controller is running out of power. The events arrive while
+// the event queue is pumped, e.g. in PollEvent, and also stay in the queue.
+// Events are dropped if the channel's buffer is full. Call stop to stop
+// watching, which closes the channel.
+// SDL sends JOYBATTERYUPDATED events since version 2.24.0, older versions
+// never send to the channel.
+func WatchJoystickBattery(buffer int) (events <-chan JoyBatteryEvent, stop func()) {
+	c := make(chan JoyBatteryEvent, buffer)
+	handle := AddEventWatchFunc(func(e Event, _ interface{}) bool {
+		if battery, ok := e.(*JoyBatteryEvent); ok {
+			select {
+			case c <- *battery:
+			default:
+			}
+		}
+		return true
+	}, nil)
+	var once sync.Once
+	return c, func() {
+		once.Do(func() {
+			DelEventWatch(handle)
+			close(c)
+		})
+	}
+}
+
+// Animation plays a sequence of frames from a sprite sheet. Each frame is a
+// rectangle in the sheet that is shown for its duration. Advance it with
+// Update every frame and draw the CurrentFrame, or use an Animator which also
+// holds the texture.
+type Animation struct {
+	Frames []Rect
+	// Durations are the times that the frames are shown. If there are fewer
+	// durations than frames, the last duration is used for the rest.
+	Durations []time.Duration
+	Mode      AnimationMode
+
+	elapsed time.Duration
+}
+
+// NewAnimation returns an animation that shows all frames for the same time.
+func NewAnimation(frames []Rect, frameDuration time.Duration, mode AnimationMode) *Animation {
+	return &Animation{
+		Frames:    frames,
+		Durations: []time.Duration{frameDuration},
+		Mode:      mode,
+	}
+}
+
+// GridFrames returns count frames of size cellW x cellH from a sprite sheet
+// whose frames are laid out in a grid with the given number of columns,
+// starting at x, y and going row by row.
+func GridFrames(x, y, cellW, cellH int32, columns, count int) []Rect {
+	if columns < 1 {
+		columns = 1
+	}
+	frames := make([]Rect, count)
+	for i := range frames {
+		frames[i] = Rect{
+			X: x + int32(i%columns)*cellW,
+			Y: y + int32(i/columns)*cellH,
+			W: cellW,
+			H: cellH,
+		}
+	}
+	return frames
 }
 
-// AllocBuf allocates the requested memory for AudioCVT buffer.
-func (cvt *AudioCVT) AllocBuf(size uintptr) {
-	cvt.bufData = make([]byte, size)
-	if size != 0 {
-		cvt.Buf = unsafe.Pointer(&cvt.bufData[0])
+// CurrentFrame returns the rectangle of the frame that is shown right now.
+// An animation without frames returns an empty rectangle.
+func (a *Animation) CurrentFrame() Rect {
+	i := a.FrameIndex()
+	if i < 0 {
+		return Rect{}
 	}
+	return a.Frames[i]
 }
 
-// BufAsSlice returns AudioCVT.buf as byte slice.
-// NOTE: Must be used after ConvertAudio() because it uses LenCVT as slice length.
-func (cvt AudioCVT) BufAsSlice() []byte {
-	var b []byte
-	sliceHeader := (*reflect.SliceHeader)(unsafe.Pointer(&b))
-	sliceHeader.Len = int(cvt.LenCVT)
-	sliceHeader.Cap = int(cvt.Len * cvt.LenMult)
-	sliceHeader.Data = uintptr(unsafe.Pointer(cvt.Buf))
-	return b
+// Done returns true if an ANIMATION_ONCE animation has reached its end.
+// Looping animations are never done.
+func (a *Animation) Done() bool {
+	return a.Mode == ANIMATION_ONCE && a.elapsed >= a.Duration()
 }
 
-// FreeBuf deallocates the memory previously allocated from AudioCVT buffer.
-func (cvt *AudioCVT) FreeBuf() {
-	cvt.Buf = nil
-	cvt.bufData = nil
+// Duration returns the time it takes to play all frames once.
+func (a *Animation) Duration() time.Duration {
+	var total time.Duration
+	for i := range a.Frames {
+		total += a.frameDuration(i)
+	}
+	return total
 }
 
-// AudioCallback is a function to call when the audio device needs more data.
-// (https://wiki.libsdl.org/SDL_AudioSpec)
-type AudioCallback uintptr
-
+// FrameIndex returns the index in Frames of the frame that is shown right
+// now, or -1 if there are no frames.
+func (a *Animation) FrameIndex() int {
+	sequence := a.sequence()
+	if len(sequence) == 0 {
+		return -1
+	}
+	t := a.elapsed
+	for _, i := range sequence {
+		d := a.frameDuration(i)
+		if t < d {
+			return i
+		}
+		t -= d
+	}
+	// Only ANIMATION_ONCE animations get past the end, they stay at the last
+	// frame.
+	return sequence[len(sequence)-1]
+}
+
+// Reset starts the animation over at the first frame.
+func (a *Animation) Reset() {
+	a.elapsed = 0
+}
+
+// Update advances the animation by dt, e.g. the time since the last frame.
+func (a *Animation) Update(dt time.Duration) {
+	if dt <= 0 {
+		return
+	}
+	a.elapsed += dt
+	var cycle time.Duration
+	for _, i := range a.sequence() {
+		cycle += a.frameDuration(i)
+	}
+	if cycle <= 0 {
+		a.elapsed = 0
+		return
+	}
+	if a.Mode == ANIMATION_ONCE {
+		if a.elapsed > cycle {
+			a.elapsed = cycle
+		}
+	} else {
+		a.elapsed %= cycle
+	}
+}
+
+func (a *Animation) frameDuration(i int) time.Duration {
+	if len(a.Durations) == 0 {
+		return 0
+	}
+	if i < len(a.Durations) {
+		return a.Durations[i]
+	}
+	return a.Durations[len(a.Durations)-1]
+}
+
+// sequence returns the frame indices of one cycle of the animation. For
+// ANIMATION_PING_PONG the way back leaves out the first and last frames, so
+// they are not shown twice in a row.
+func (a *Animation) sequence() []int {
+	n := len(a.Frames)
+	sequence := make([]int, 0, 2*n)
+	for i := 0; i < n; i++ {
+		sequence = append(sequence, i)
+	}
+	if a.Mode == ANIMATION_PING_PONG {
+		for i := n - 2; i > 0; i-- {
+			sequence = append(sequence, i)
+		}
+	}
+	return sequence
+}
+
+// AnimationMode says what an Animation does after its last frame.
+type AnimationMode int
+
+// Animator draws named animations from one sprite sheet texture, e.g. the
+// "idle", "walk" and "jump" animations of a character. One animation is
+// playing at a time.
+//
+//	player := sdl.NewAnimator(sheet)
+//	player.Add("idle", sdl.NewAnimation(sdl.GridFrames(0, 0, 32, 32, 4, 4), 150*time.Millisecond, sdl.ANIMATION_LOOP))
+//	player.Add("walk", sdl.NewAnimation(sdl.GridFrames(0, 32, 32, 32, 6, 6), 100*time.Millisecond, sdl.ANIMATION_LOOP))
+//	player.Play("idle")
+//	// every frame:
+//	player.Update(dt)
+//	player.Draw(renderer, &sdl.Rect{X: x, Y: y, W: 32, H: 32})
+type Animator struct {
+	Texture *Texture
+
+	animations map[string]*Animation
+	current    string
+}
+
+// NewAnimator returns an Animator for the texture without any animations.
+func NewAnimator(texture *Texture) *Animator {
+	return &Animator{
+		Texture:    texture,
+		animations: make(map[string]*Animation),
+	}
+}
+
+// Add adds the animation under the name, replacing an animation of the same
+// name. The first animation that is added starts playing.
+func (a *Animator) Add(name string, animation *Animation) {
+	a.animations[name] = animation
+	if a.current == "" {
+		a.current = name
+	}
+}
+
+// Animation returns the current animation or nil if there is none.
+func (a *Animator) Animation() *Animation {
+	return a.animations[a.current]
+}
+
+// Current returns the name of the animation that is playing.
+func (a *Animator) Current() string {
+	return a.current
+}
+
+// Draw draws the current frame of the current animation into dst.
+func (a *Animator) Draw(renderer *Renderer, dst *Rect) error {
+	return a.DrawEx(renderer, dst, 0, nil, FLIP_NONE)
+}
+
+// DrawEx is like Draw but can rotate and flip the frame, see Renderer.CopyEx.
+// Flipping horizontally lets e.g. a walk animation facing right be reused for
+// walking left.
+func (a *Animator) DrawEx(renderer *Renderer, dst *Rect, angle float64, center *Point, flip RendererFlip) error {
+	animation := a.Animation()
+	if animation == nil || len(animation.Frames) == 0 {
+		return nil
+	}
+	src := animation.CurrentFrame()
+	if angle == 0 && flip == FLIP_NONE {
+		return renderer.Copy(a.Texture, &src, dst)
+	}
+	return renderer.CopyEx(a.Texture, &src, dst, angle, center, flip)
+}
+
+// Play switches to the animation with the name and starts it over. If it is
+// already playing, it just keeps playing, so Play can be called every frame
+// with the animation that the game state asks for.
+func (a *Animator) Play(name string) error {
+	animation, ok := a.animations[name]
+	if !ok {
+		return fmt.Errorf("sdl: Animator has no animation %q", name)
+	}
+	if name != a.current {
+		a.current = name
+		animation.Reset()
+	}
+	return nil
+}
+
+// Update advances the current animation by dt.
+func (a *Animator) Update(dt time.Duration) {
+	if animation := a.Animation(); animation != nil {
+		animation.Update(dt)
+	}
+}
+
+// AssertData contains information about an assertion that failed in SDL.
+// (https://wiki.libsdl.org/SDL_AssertData)
+type AssertData struct {
+	AlwaysIgnore bool   // true if the assertion is ignored from now on
+	TriggerCount uint   // the number of times the assertion failed
+	Condition    string // the condition that failed
+	Filename     string // the source file containing the assertion
+	LineNum      int    // the source line of the assertion
+	Function     string // the function containing the assertion
+}
+
+// cAssertData is the memory layout of SDL_AssertData.
+type cAssertData struct {
+	alwaysIgnore int32
+	triggerCount uint32
+	condition    uintptr
+	filename     uintptr
+	linenum      int32
+	function     uintptr
+	next         uintptr
+}
+
+func (d *cAssertData) goData() AssertData {
+	return AssertData{
+		AlwaysIgnore: d.alwaysIgnore != 0,
+		TriggerCount: uint(d.triggerCount),
+		Condition:    sdlToGoString(d.condition),
+		Filename:     sdlToGoString(d.filename),
+		LineNum:      int(d.linenum),
+		Function:     sdlToGoString(d.function),
+	}
+}
+
+// AssertState is the result of an assertion handler.
+// (https://wiki.libsdl.org/SDL_AssertState)
+type AssertState int
+
+// AssertionHandler is called when an assertion fails inside SDL. Note that
+// the release builds of SDL only check a few assertions.
+type AssertionHandler func(data AssertData) AssertState
+
+var assertionHandler AssertionHandler
+
+func theAssertionHandler(data, userdata uintptr) uintptr {
+	return uintptr(assertionHandler((*cAssertData)(unsafe.Pointer(data)).goData()))
+}
+
+var assertionHandlerPtr = syscall.NewCallbackCDecl(theAssertionHandler)
+
+// GetAssertionReport returns all assertions that failed since the program
+// started or since the last call to ResetAssertionReport.
+// (https://wiki.libsdl.org/SDL_GetAssertionReport)
+func GetAssertionReport() []AssertData {
+	var report []AssertData
+	ret, _, _ := getAssertionReport.Call()
+	for p := ret; p != 0; {
+		d := (*cAssertData)(unsafe.Pointer(p))
+		report = append(report, d.goData())
+		p = d.next
+	}
+	return report
+}
+
+// ResetAssertionReport clears the list of failed assertions.
+// (https://wiki.libsdl.org/SDL_ResetAssertionReport)
+func ResetAssertionReport() {
+	resetAssertionReport.Call()
+}
+
+// SetAssertionHandler makes SDL call handler instead of showing its own
+// dialog when an assertion fails. A nil handler restores the default one.
+// The handler may be called from any thread and must not panic; to turn
+// assertions into panics, record them and panic after the SDL call returns.
+// (https://wiki.libsdl.org/SDL_SetAssertionHandler)
+func SetAssertionHandler(handler AssertionHandler) {
+	assertionHandler = handler
+	if handler == nil {
+		setAssertionHandler.Call(0, 0)
+	} else {
+		setAssertionHandler.Call(assertionHandlerPtr, 0)
+	}
+}
+
+// AudioCVT contains audio data conversion information.
+// (https://wiki.libsdl.org/SDL_AudioCVT)
+type AudioCVT struct {
+	Needed      int32           // set to 1 if conversion possible
+	SrcFormat   AudioFormat     // source audio format
+	DstFormat   AudioFormat     // target audio format
+	RateIncr    float64         // rate conversion increment
+	Buf         unsafe.Pointer  // the buffer to hold entire audio data. Use AudioCVT.BufAsSlice() for access via a Go slice
+	Len         int32           // length of original audio buffer
+	LenCVT      int32           // length of converted audio buffer
+	LenMult     int32           // buf must be len*len_mult big
+	LenRatio    float64         // given len, final size is len*len_ratio
+	filters     [10]AudioFilter // filter list (internal use)
+	filterIndex int32           // current audio conversion function (internal use)
+	bufData     []byte
+}
+
+// AllocBuf allocates the requested memory for AudioCVT buffer.
+func (cvt *AudioCVT) AllocBuf(size uintptr) {
+	cvt.bufData = make([]byte, size)
+	if size != 0 {
+		cvt.Buf = unsafe.Pointer(&cvt.bufData[0])
+	}
+}
+
+// BufAsSlice returns AudioCVT.buf as byte slice.
+// NOTE: Must be used after ConvertAudio() because it uses LenCVT as slice length.
+func (cvt AudioCVT) BufAsSlice() []byte {
+	var b []byte
+	sliceHeader := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	sliceHeader.Len = int(cvt.LenCVT)
+	sliceHeader.Cap = int(cvt.Len * cvt.LenMult)
+	sliceHeader.Data = uintptr(unsafe.Pointer(cvt.Buf))
+	return b
+}
+
+// FreeBuf deallocates the memory previously allocated from AudioCVT buffer.
+func (cvt *AudioCVT) FreeBuf() {
+	cvt.Buf = nil
+	cvt.bufData = nil
+}
+
+// AudioCallback is a function to call when the audio device needs more data.
+// (https://wiki.libsdl.org/SDL_AudioSpec)
+type AudioCallback uintptr
+
 // AudioDeviceEvent contains audio device event information.
 // (https://wiki.libsdl.org/SDL_AudioDeviceEvent)
 type AudioDeviceEvent struct {
@@ -4392,7 +6468,9 @@ func (e *AudioDeviceEvent) GetType() uint32 {
 // (https://wiki.libsdl.org/SDL_OpenAudioDevice)
 type AudioDeviceID uint32
 
-// OpenAudioDevice opens a specific audio device.
+// OpenAudioDevice opens a specific audio device. Pass an empty device name to
+// open the most reasonable default device. The obtained spec may be nil, in
+// which case SDL converts from the desired spec if necessary.
 // (https://wiki.libsdl.org/SDL_OpenAudioDevice)
 func OpenAudioDevice(device string, isCapture bool, desired, obtained *AudioSpec, allowedChanges int) (AudioDeviceID, error) {
 	d := append([]byte(device), 0)
@@ -4403,16 +6481,143 @@ func OpenAudioDevice(device string, isCapture bool, desired, obtained *AudioSpec
 	ret, _, _ := openAudioDevice.Call(
 		devicePtr,
 		uintptr(Btoi(isCapture)),
-		uintptr(unsafe.Pointer(&desired)),
-		uintptr(unsafe.Pointer(&obtained)),
+		uintptr(unsafe.Pointer(desired)),
+		uintptr(unsafe.Pointer(obtained)),
 		uintptr(allowedChanges),
 	)
 	if ret == 0 {
 		return 0, GetError()
 	}
+	trackAudioDevice(AudioDeviceID(ret), true)
 	return AudioDeviceID(ret), nil
 }
 
+// legacyAudioDevice is the ID that SDL gives the device opened with OpenAudio.
+const legacyAudioDevice AudioDeviceID = 1
+
+// openAudioDevices holds the devices opened with OpenAudioDevice and
+// OpenAudio that were not closed yet. Shutting down the audio subsystem closes
+// all of them.
+var openAudioDevices struct {
+	mu  sync.Mutex
+	ids map[AudioDeviceID]bool
+}
+
+func trackAudioDevice(dev AudioDeviceID, open bool) {
+	openAudioDevices.mu.Lock()
+	defer openAudioDevices.mu.Unlock()
+	if open {
+		if openAudioDevices.ids == nil {
+			openAudioDevices.ids = make(map[AudioDeviceID]bool)
+		}
+		openAudioDevices.ids[dev] = true
+	} else {
+		delete(openAudioDevices.ids, dev)
+	}
+}
+
+// forgetAudioDevices is called after the audio subsystem was shut down.
+func forgetAudioDevices() {
+	openAudioDevices.mu.Lock()
+	openAudioDevices.ids = nil
+	openAudioDevices.mu.Unlock()
+}
+
+// OpenAudioDevices returns the devices that were opened with OpenAudioDevice
+// or OpenAudio and not closed yet, in no particular order. The device opened
+// with OpenAudio always has the ID 1.
+func OpenAudioDevices() []AudioDeviceID {
+	openAudioDevices.mu.Lock()
+	defer openAudioDevices.mu.Unlock()
+	devices := make([]AudioDeviceID, 0, len(openAudioDevices.ids))
+	for dev := range openAudioDevices.ids {
+		devices = append(devices, dev)
+	}
+	return devices
+}
+
+// PauseAllAudioDevices pauses or unpauses all devices returned by
+// OpenAudioDevices, e.g. when the game loses the input focus.
+func PauseAllAudioDevices(pauseOn bool) {
+	for _, dev := range OpenAudioDevices() {
+		PauseAudioDevice(dev, pauseOn)
+	}
+}
+
+// ClearQueued drops any queued audio data waiting to be sent to the
+// hardware, see ClearQueuedAudio.
+func (dev AudioDeviceID) ClearQueued() {
+	ClearQueuedAudio(dev)
+}
+
+// Close shuts down audio processing and closes the device, see
+// CloseAudioDevice.
+func (dev AudioDeviceID) Close() {
+	CloseAudioDevice(dev)
+}
+
+// Dequeue reads queued audio data from a capture device into data and
+// returns the number of bytes read.
+// (https://wiki.libsdl.org/SDL_DequeueAudio)
+func (dev AudioDeviceID) Dequeue(data []byte) int {
+	if len(data) == 0 {
+		return 0
+	}
+	ret, _, _ := dequeueAudio.Call(
+		uintptr(dev),
+		uintptr(unsafe.Pointer(&data[0])),
+		uintptr(len(data)),
+	)
+	return int(uint32(ret))
+}
+
+// Lock locks out the audio callback of the device, see LockAudioDevice.
+func (dev AudioDeviceID) Lock() {
+	LockAudioDevice(dev)
+}
+
+// Pause pauses audio playback on the device, see PauseAudioDevice.
+func (dev AudioDeviceID) Pause() {
+	PauseAudioDevice(dev, true)
+}
+
+// Queue queues more audio data on a device without callback, see QueueAudio.
+func (dev AudioDeviceID) Queue(data []byte) error {
+	return QueueAudio(dev, data)
+}
+
+// QueuedSize returns the number of bytes of queued audio data, see
+// GetQueuedAudioSize.
+func (dev AudioDeviceID) QueuedSize() uint32 {
+	return GetQueuedAudioSize(dev)
+}
+
+// Status returns whether the device is stopped, playing or paused, see
+// GetAudioDeviceStatus.
+func (dev AudioDeviceID) Status() AudioStatus {
+	return GetAudioDeviceStatus(dev)
+}
+
+// Unlock unlocks the audio callback of the device, see UnlockAudioDevice.
+func (dev AudioDeviceID) Unlock() {
+	UnlockAudioDevice(dev)
+}
+
+// Unpause starts or resumes audio playback on the device, see
+// PauseAudioDevice.
+func (dev AudioDeviceID) Unpause() {
+	PauseAudioDevice(dev, false)
+}
+
+// AudioDeviceInfo describes an audio device, see AudioDevices.
+type AudioDeviceInfo struct {
+	Index int    // the index for GetAudioDeviceName and similar functions
+	Name  string // the name to pass to OpenAudioDevice
+	// Spec is the preferred audio format of the device. It is the zero value
+	// if SDL does not know it, which is always the case before SDL 2.0.16.
+	Spec AudioSpec
+}
+
 // AudioFilter is the filter list used in AudioCVT() (internal use)
 // (https://wiki.libsdl.org/SDL_AudioCVT)
 type AudioFilter uintptr
@@ -4544,11 +6749,45 @@ func NewAudioStream(srcFormat AudioFormat, srcChannels uint8, srcRate int, dstFo
 	return (*AudioStream)(unsafe.Pointer(ret)), nil
 }
 
+// Resample converts the audio data from one format, channel count and
+// frequency to another, using a temporary AudioStream. Only the Format,
+// Channels and Freq fields of the specs are used.
+func Resample(data []byte, from, to AudioSpec) ([]byte, error) {
+	stream, err := NewAudioStream(
+		from.Format, from.Channels, int(from.Freq),
+		to.Format, to.Channels, int(to.Freq),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Free()
+
+	if err := stream.Put(data); err != nil {
+		return nil, err
+	}
+	if err := stream.Flush(); err != nil {
+		return nil, err
+	}
+	n, err := stream.Available()
+	if err != nil {
+		return nil, err
+	}
+	converted := make([]byte, n)
+	if err := stream.Get(converted); err != nil {
+		return nil, err
+	}
+	return converted, nil
+}
+
 // Available gets the number of converted/resampled bytes available
 // TODO: (https://wiki.libsdl.org/SDL_AudioStreamAvailable)
-func (stream *AudioStream) Available() (err error) {
+func (stream *AudioStream) Available() (n int, err error) {
 	ret, _, _ := audioStreamAvailable.Call(uintptr(unsafe.Pointer(stream)))
-	return errorFromInt(int(ret))
+	n = int(int32(ret))
+	if n < 0 {
+		return 0, GetError()
+	}
+	return n, nil
 }
 
 // Clear clears any pending data in the stream without converting it
@@ -4599,6 +6838,184 @@ func (stream *AudioStream) Put(buf []byte) (err error) {
 	return errorFromInt(int(ret))
 }
 
+// BitmapFont draws text from a sprite sheet whose characters are laid out in a
+// grid of equally sized cells, for HUD text without a TrueType font library.
+// The cells are ordered row by row and hold consecutive characters, e.g. the
+// 95 printable ASCII characters from ' ' to '~' in 16 columns and 6 rows.
+// Every character advances by the cell width, there is no kerning.
+type BitmapFont struct {
+	// LineSpacing is the number of extra pixels between lines, it may be
+	// negative.
+	LineSpacing int32
+
+	renderer      *Renderer
+	texture       *Texture
+	cellW, cellH  int32
+	columns       int32
+	first         rune
+	count         int32
+	fallbackIndex int32 // the cell of '?' or -1 if the font has none
+}
+
+// LoadBitmapFont loads a sprite sheet from a PNG or BMP file, see BitmapFont.
+// first is the character in the top-left cell. PNG files keep their alpha
+// channel. BMP files usually have none, the color of their top-left pixel is
+// then used as the transparent background color.
+//
+// All files except BMPs are decoded with image.Decode, so the caller must
+// register their format, e.g. with
+//
+//	import _ "image/png"
+func LoadBitmapFont(renderer *Renderer, path string, cellW, cellH int32, first rune) (*BitmapFont, error) {
+	if strings.EqualFold(filepath.Ext(path), ".bmp") {
+		surface, err := LoadBMP(path)
+		if err != nil {
+			return nil, err
+		}
+		defer surface.Free()
+		if surface.Format.Amask == 0 && surface.W > 0 && surface.H > 0 {
+			var key uint32
+			pixel := surface.pitchedPixels()[:surface.Format.BytesPerPixel]
+			for i := len(pixel) - 1; i >= 0; i-- {
+				key = key<<8 | uint32(pixel[i])
+			}
+			if err := surface.SetColorKey(true, key); err != nil {
+				return nil, err
+			}
+		}
+		return NewBitmapFontFromSurface(renderer, surface, cellW, cellH, first)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("sdl: cannot decode bitmap font %s: %v", path, err)
+	}
+	return NewBitmapFont(renderer, img, cellW, cellH, first)
+}
+
+// NewBitmapFont creates a font from a sprite sheet image, see BitmapFont.
+// first is the character in the top-left cell.
+func NewBitmapFont(renderer *Renderer, img image.Image, cellW, cellH int32, first rune) (*BitmapFont, error) {
+	surface, err := CreateSurfaceFromImage(img)
+	if err != nil {
+		return nil, err
+	}
+	defer surface.Free()
+	return NewBitmapFontFromSurface(renderer, surface, cellW, cellH, first)
+}
+
+// NewBitmapFontFromSurface creates a font from a sprite sheet surface, see
+// BitmapFont. first is the character in the top-left cell. The surface can be
+// freed afterwards.
+func NewBitmapFontFromSurface(renderer *Renderer, surface *Surface, cellW, cellH int32, first rune) (*BitmapFont, error) {
+	if cellW <= 0 || cellH <= 0 {
+		return nil, fmt.Errorf("sdl: invalid bitmap font cell size %dx%d", cellW, cellH)
+	}
+	columns, rows := surface.W/cellW, surface.H/cellH
+	if columns == 0 || rows == 0 {
+		return nil, fmt.Errorf(
+			"sdl: bitmap font image of size %dx%d is smaller than a %dx%d cell",
+			surface.W, surface.H, cellW, cellH,
+		)
+	}
+	texture, err := renderer.CreateTextureFromSurface(surface)
+	if err != nil {
+		return nil, err
+	}
+	if err := texture.SetBlendMode(BLENDMODE_BLEND); err != nil {
+		texture.Destroy()
+		return nil, err
+	}
+	font := &BitmapFont{
+		renderer: renderer,
+		texture:  texture,
+		cellW:    cellW,
+		cellH:    cellH,
+		columns:  columns,
+		first:    first,
+		count:    columns * rows,
+		// cellIndex returns fallbackIndex for unknown characters so it must
+		// be invalid while looking up the fallback itself.
+		fallbackIndex: -1,
+	}
+	font.fallbackIndex = font.cellIndex('?')
+	return font, nil
+}
+
+// cellIndex returns the cell of the character, the fallback cell if the font
+// does not contain it.
+func (font *BitmapFont) cellIndex(r rune) int32 {
+	if font.first <= r && r < font.first+rune(font.count) {
+		return int32(r - font.first)
+	}
+	return font.fallbackIndex
+}
+
+// Destroy destroys the font's texture. The font cannot be used afterwards.
+func (font *BitmapFont) Destroy() error {
+	return font.texture.Destroy()
+}
+
+// Draw draws the text with its top-left corner at x, y. Line breaks start a
+// new line below x. Characters that are not in the font are drawn as '?' or,
+// if the font has no '?', left blank.
+func (font *BitmapFont) Draw(text string, x, y int32) error {
+	startX := x
+	for _, r := range text {
+		if r == '\n' {
+			x = startX
+			y += font.cellH + font.LineSpacing
+			continue
+		}
+		if i := font.cellIndex(r); i >= 0 {
+			src := Rect{
+				X: i % font.columns * font.cellW,
+				Y: i / font.columns * font.cellH,
+				W: font.cellW,
+				H: font.cellH,
+			}
+			dst := Rect{X: x, Y: y, W: font.cellW, H: font.cellH}
+			if err := font.renderer.Copy(font.texture, &src, &dst); err != nil {
+				return err
+			}
+		}
+		x += font.cellW
+	}
+	return nil
+}
+
+// Measure returns the size in pixels that Draw needs for the text.
+func (font *BitmapFont) Measure(text string) (w, h int32) {
+	var lineLen, maxLen, lines int32 = 0, 0, 1
+	for _, r := range text {
+		if r == '\n' {
+			lines++
+			lineLen = 0
+			continue
+		}
+		lineLen++
+		if lineLen > maxLen {
+			maxLen = lineLen
+		}
+	}
+	return maxLen * font.cellW, lines*font.cellH + (lines-1)*font.LineSpacing
+}
+
+// SetColor tints the font, which works best for white characters. The alpha
+// value makes the text translucent.
+func (font *BitmapFont) SetColor(c color.Color) error {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if err := font.texture.SetColorMod(n.R, n.G, n.B); err != nil {
+		return err
+	}
+	return font.texture.SetAlphaMod(n.A)
+}
+
 // BlendFactor is an enumeration of blend factors used when creating a custom blend mode with ComposeCustomBlendMode().
 // (https://wiki.libsdl.org/SDL_BlendFactor)
 type BlendFactor uint32
@@ -4629,6 +7046,35 @@ func ComposeCustomBlendMode(srcColorFactor, dstColorFactor BlendFactor, colorOpe
 // (https://wiki.libsdl.org/SDL_BlendOperation)
 type BlendOperation uint32
 
+// Borders are the widths of the four borders of a rectangle, e.g. the fixed
+// size edges of a nine-patch, see Renderer.DrawNinePatch.
+type Borders struct {
+	Left, Top, Right, Bottom int32
+}
+
+// CallError is the error returned by this package when a call into SDL2.dll
+// fails. It carries the message returned by SDL_GetError.
+type CallError struct {
+	Func string // the name of the failing function, e.g. "CreateWindow" or "Renderer.Clear"
+	Msg  string // the SDL error message
+}
+
+// Error returns the failing function's name followed by the SDL message.
+func (e *CallError) Error() string {
+	if e.Func == "" {
+		return e.Msg
+	}
+	return e.Func + ": " + e.Msg
+}
+
+// Is reports whether target is a *CallError with the same message. This
+// lets errors.Is compare errors to sentinels like ErrUnsupported, regardless
+// of the function that failed.
+func (e *CallError) Is(target error) bool {
+	t, ok := target.(*CallError)
+	return ok && t.Msg == e.Msg
+}
+
 // CEvent is a union of all event structures used in SDL.
 // (https://wiki.libsdl.org/SDL_Event)
 type CEvent struct {
@@ -4636,6 +7082,151 @@ type CEvent struct {
 	_    [52]byte // padding
 }
 
+// CPUFeatures is a snapshot of the CPU information returned by
+// GetCPUFeatures.
+type CPUFeatures struct {
+	Count         int // number of logical CPU cores
+	CacheLineSize int // L1 cache line size in bytes
+	SIMDAlignment int // alignment in bytes needed for SIMD allocations
+	Has3DNow      bool
+	HasAltiVec    bool
+	HasARMSIMD    bool
+	HasAVX        bool
+	HasAVX2       bool
+	HasAVX512F    bool
+	HasLASX       bool
+	HasLSX        bool
+	HasMMX        bool
+	HasNEON       bool
+	HasRDTSC      bool
+	HasSSE        bool
+	HasSSE2       bool
+	HasSSE3       bool
+	HasSSE41      bool
+	HasSSE42      bool
+}
+
+// Camera2D describes the view onto a 2D world, e.g. to scroll through a tile
+// map. The world point at Position is shown at the screen point Offset, world
+// units are scaled by Zoom and the world is rotated around Offset. Use a
+// CameraRenderer to draw textures in world coordinates.
+type Camera2D struct {
+	Position FPoint  // the world point shown at Offset
+	Offset   FPoint  // the screen point that the camera looks at, usually the center
+	Zoom     float32 // the size of a world unit in pixels, 0 is treated as 1
+	Rotation float64 // the clockwise rotation of the world on the screen, in degrees
+}
+
+// NewCamera2D returns a camera with a Zoom of 1 that shows the world origin at
+// the center of a screen of the given size.
+func NewCamera2D(screenW, screenH int32) *Camera2D {
+	return &Camera2D{
+		Offset: FPoint{X: float32(screenW) / 2, Y: float32(screenH) / 2},
+		Zoom:   1,
+	}
+}
+
+// ScreenToWorld returns the world point shown at the screen point, e.g. to
+// find out what the mouse points at. It is the inverse of WorldToScreen.
+func (c *Camera2D) ScreenToWorld(p FPoint) FPoint {
+	sin, cos := math.Sincos(-c.Rotation * math.Pi / 180)
+	x := float64(p.X-c.Offset.X) / float64(c.zoom())
+	y := float64(p.Y-c.Offset.Y) / float64(c.zoom())
+	return FPoint{
+		X: c.Position.X + float32(x*cos-y*sin),
+		Y: c.Position.Y + float32(x*sin+y*cos),
+	}
+}
+
+// VisibleBounds returns the smallest world rectangle that contains everything
+// visible on a screen of the given size, e.g. to draw only the visible tiles
+// of a map.
+func (c *Camera2D) VisibleBounds(screenW, screenH int32) FRect {
+	w, h := float32(screenW), float32(screenH)
+	corners := [4]FPoint{
+		c.ScreenToWorld(FPoint{X: 0, Y: 0}),
+		c.ScreenToWorld(FPoint{X: w, Y: 0}),
+		c.ScreenToWorld(FPoint{X: 0, Y: h}),
+		c.ScreenToWorld(FPoint{X: w, Y: h}),
+	}
+	min, max := corners[0], corners[0]
+	for _, p := range corners[1:] {
+		min.X = float32(math.Min(float64(min.X), float64(p.X)))
+		min.Y = float32(math.Min(float64(min.Y), float64(p.Y)))
+		max.X = float32(math.Max(float64(max.X), float64(p.X)))
+		max.Y = float32(math.Max(float64(max.Y), float64(p.Y)))
+	}
+	return FRect{X: min.X, Y: min.Y, W: max.X - min.X, H: max.Y - min.Y}
+}
+
+// WorldToScreen returns the screen point at which the world point is shown.
+func (c *Camera2D) WorldToScreen(p FPoint) FPoint {
+	sin, cos := math.Sincos(c.Rotation * math.Pi / 180)
+	x := float64(p.X - c.Position.X)
+	y := float64(p.Y - c.Position.Y)
+	return FPoint{
+		X: c.Offset.X + c.zoom()*float32(x*cos-y*sin),
+		Y: c.Offset.Y + c.zoom()*float32(x*sin+y*cos),
+	}
+}
+
+func (c *Camera2D) zoom() float32 {
+	if c.Zoom == 0 {
+		return 1
+	}
+	return c.Zoom
+}
+
+// CameraRenderer draws textures at world coordinates, applying the camera's
+// transform to every copy:
+//
+//	world := sdl.CameraRenderer{Renderer: renderer, Camera: camera}
+//	for _, tile := range visibleTiles {
+//		world.Copy(tiles, &tile.Src, &tile.WorldRect)
+//	}
+//
+// Everything else, e.g. UI on top of the world, is drawn with the Renderer
+// directly in screen coordinates.
+type CameraRenderer struct {
+	Renderer *Renderer
+	Camera   *Camera2D
+}
+
+// Copy draws the src part of the texture, or all of it if src is nil, into
+// the world rectangle dst. Unlike in Renderer.Copy, dst must not be nil since
+// the world has no size to fill, ErrInvalidParameters is returned for nil.
+func (r CameraRenderer) Copy(texture *Texture, src *Rect, dst *FRect) error {
+	return r.CopyEx(texture, src, dst, 0, nil, FLIP_NONE)
+}
+
+// CopyEx is like Copy but rotates the texture by angle degrees clockwise
+// around center, which is relative to dst's top-left corner in world units,
+// or around dst's center if center is nil. It can also flip the texture. Like
+// in Copy, dst must not be nil.
+func (r CameraRenderer) CopyEx(texture *Texture, src *Rect, dst *FRect, angle float64, center *FPoint, flip RendererFlip) error {
+	if dst == nil {
+		return ErrInvalidParameters
+	}
+	pivot := FPoint{X: dst.W / 2, Y: dst.H / 2}
+	if center != nil {
+		pivot = *center
+	}
+	zoom := r.Camera.zoom()
+	screenPivot := r.Camera.WorldToScreen(FPoint{X: dst.X + pivot.X, Y: dst.Y + pivot.Y})
+	screenCenter := FPoint{X: pivot.X * zoom, Y: pivot.Y * zoom}
+	screen := FRect{
+		X: screenPivot.X - screenCenter.X,
+		Y: screenPivot.Y - screenCenter.Y,
+		W: dst.W * zoom,
+		H: dst.H * zoom,
+	}
+	angle += r.Camera.Rotation
+	if angle == 0 && flip == FLIP_NONE {
+		return r.Renderer.CopyF(texture, src, &screen)
+	}
+	return r.Renderer.CopyExF(texture, src, &screen, angle, &screenCenter, flip)
+}
+
 // ClipboardEvent contains clipboard event information.
 // (https://wiki.libsdl.org/SDL_EventType)
 type ClipboardEvent struct {
@@ -4672,6 +7263,20 @@ func (c Color) Uint32() uint32 {
 	return v
 }
 
+// RGBA implements the color.Color interface. SDL colors are not
+// alpha-premultiplied, so c behaves like a color.NRGBA.
+func (c Color) RGBA() (r, g, b, a uint32) {
+	return color.NRGBA(c).RGBA()
+}
+
+// toColor converts any color.Color to an SDL color.
+func toColor(c color.Color) Color {
+	if sdlColor, ok := c.(Color); ok {
+		return sdlColor
+	}
+	return Color(color.NRGBAModel.Convert(c).(color.NRGBA))
+}
+
 // CommonEvent contains common event data.
 // (https://wiki.libsdl.org/SDL_Event)
 type CommonEvent struct {
@@ -4689,8 +7294,56 @@ func (e *CommonEvent) GetType() uint32 {
 	return e.Type
 }
 
-// Cond is the SDL condition variable structure.
-type Cond struct {
+// Config holds common hints in typed form, see InitWithConfig. The zero value
+// of each field leaves the hint at its default.
+type Config struct {
+	// Flags are the subsystems to initialize, 0 means INIT_EVERYTHING.
+	Flags uint32
+	// RenderDriver is the name of the render driver for new renderers, e.g.
+	// "direct3d11" or "opengl", see RenderDrivers.
+	RenderDriver string
+	// VSync makes renderers wait for the vertical refresh when presenting.
+	VSync bool
+	// ScaleQuality is the filtering of textures that are drawn scaled.
+	ScaleQuality ScaleQuality
+	// AllowScreensaver lets the screen saver turn on while windows are open.
+	AllowScreensaver bool
+	// KeepFullscreenOnFocusLoss keeps fullscreen windows open instead of
+	// minimizing them when they lose the focus, e.g. on Alt+Tab.
+	KeepFullscreenOnFocusLoss bool
+	// JoystickBackgroundEvents delivers joystick and game controller events
+	// while no window of the program has the focus.
+	JoystickBackgroundEvents bool
+	// NoCloseOnAltF4 stops Alt+F4 from sending a QUIT event.
+	NoCloseOnAltF4 bool
+	// Hints are set in addition to the typed fields, by hint name. They take
+	// precedence over the typed fields.
+	Hints map[string]string
+}
+
+// Apply sets the hints of the config. Most hints have to be set before the
+// subsystem or object they affect is created. InitWithConfig calls Apply
+// before Init.
+func (c Config) Apply() {
+	setIf := func(set bool, name, value string) {
+		if set {
+			SetHint(name, value)
+		}
+	}
+	setIf(c.RenderDriver != "", HINT_RENDER_DRIVER, c.RenderDriver)
+	setIf(c.VSync, HINT_RENDER_VSYNC, "1")
+	setIf(c.ScaleQuality != "", HINT_RENDER_SCALE_QUALITY, string(c.ScaleQuality))
+	setIf(c.AllowScreensaver, HINT_VIDEO_ALLOW_SCREENSAVER, "1")
+	setIf(c.KeepFullscreenOnFocusLoss, HINT_VIDEO_MINIMIZE_ON_FOCUS_LOSS, "0")
+	setIf(c.JoystickBackgroundEvents, HINT_JOYSTICK_ALLOW_BACKGROUND_EVENTS, "1")
+	setIf(c.NoCloseOnAltF4, HINT_WINDOWS_NO_CLOSE_ON_ALT_F4, "1")
+	for name, value := range c.Hints {
+		SetHint(name, value)
+	}
+}
+
+// Cond is the SDL condition variable structure.
+type Cond struct {
 	Lock     *Mutex
 	Waiting  int
 	Signals  int
@@ -4743,6 +7396,34 @@ func (cond *Cond) Wait(mutex *Mutex) error {
 	return nil
 }
 
+// WaitContext waits until a condition variable is signaled or the context is
+// done. In the latter case the mutex is locked again and ctx.Err() is
+// returned. SDL condition variables cannot be interrupted so WaitContext
+// waits in steps of condPollInterval, checking the context in between.
+// Like with Wait, the mutex must be locked when calling WaitContext.
+func (cond *Cond) WaitContext(ctx context.Context, mutex *Mutex) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ret, _, _ := condWaitTimeout.Call(
+			uintptr(unsafe.Pointer(cond)),
+			uintptr(unsafe.Pointer(mutex)),
+			uintptr(condPollInterval/time.Millisecond),
+		)
+		if ret == 0 {
+			return nil
+		}
+		if ret != MUTEX_TIMEDOUT {
+			return GetError()
+		}
+	}
+}
+
+// condPollInterval is how long Cond.WaitContext waits before it checks the
+// context again.
+const condPollInterval = 10 * time.Millisecond
+
 // WaitTimeout waits until a condition variable is signaled or a specified amount of time has passed.
 // (https://wiki.libsdl.org/SDL_CondWaitTimeout)
 func (cond *Cond) WaitTimeout(mutex *Mutex, ms uint32) error {
@@ -4877,6 +7558,101 @@ type DFBInfo struct {
 	Surface unsafe.Pointer // the DirectFB client surface
 }
 
+// DPIWatcher keeps track of the DPI scale factor of windows, i.e. the DPI of
+// the display that a window is on divided by the default of 96 DPI. UI code
+// can use it to rescale fonts when a window moves to a monitor with a
+// different DPI. Pass all events to HandleEvent:
+//
+//	dpi := sdl.NewDPIWatcher()
+//	dpi.OnChange = func(window *sdl.Window, scale float32) {
+//		loadFont(int(12 * scale))
+//	}
+//	for e := sdl.PollEvent(); e != nil; e = sdl.PollEvent() {
+//		dpi.HandleEvent(e)
+//	}
+//
+// Windows only reports the actual DPI to programs that are DPI aware, e.g.
+// through their application manifest, otherwise the scale is always 1.
+type DPIWatcher struct {
+	// OnChange is called from HandleEvent when the scale of a window changed.
+	OnChange func(window *Window, scale float32)
+
+	scales map[uint32]float32
+}
+
+// NewDPIWatcher returns a DPIWatcher that does not know any windows yet.
+func NewDPIWatcher() *DPIWatcher {
+	return &DPIWatcher{scales: make(map[uint32]float32)}
+}
+
+// HandleEvent updates the scale of the event's window if it was moved,
+// resized, shown or put onto another display. It returns true if the scale
+// changed, after calling OnChange.
+func (w *DPIWatcher) HandleEvent(e Event) bool {
+	event, ok := e.(*WindowEvent)
+	if !ok {
+		return false
+	}
+	switch event.Event {
+	case WINDOWEVENT_SHOWN, WINDOWEVENT_MOVED, WINDOWEVENT_SIZE_CHANGED,
+		WINDOWEVENT_DISPLAY_CHANGED:
+	case WINDOWEVENT_CLOSE:
+		delete(w.scales, event.WindowID)
+		return false
+	default:
+		return false
+	}
+	window, err := GetWindowFromID(event.WindowID)
+	if err != nil {
+		return false
+	}
+	scale, err := windowDPIScale(window)
+	if err != nil {
+		return false
+	}
+	old, known := w.scales[event.WindowID]
+	w.scales[event.WindowID] = scale
+	if known && old == scale {
+		return false
+	}
+	if w.OnChange != nil {
+		w.OnChange(window, scale)
+	}
+	return true
+}
+
+// Scale returns the current DPI scale factor of the window, 1 if it cannot be
+// determined.
+func (w *DPIWatcher) Scale(window *Window) float32 {
+	id, err := window.GetID()
+	if err != nil {
+		return 1
+	}
+	if scale, ok := w.scales[id]; ok {
+		return scale
+	}
+	scale, err := windowDPIScale(window)
+	if err != nil {
+		return 1
+	}
+	w.scales[id] = scale
+	return scale
+}
+
+// windowDPIScale returns the diagonal DPI of the window's display divided by
+// 96.
+func windowDPIScale(window *Window) (float32, error) {
+	display, err := window.GetDisplayIndex()
+	if err != nil {
+		return 1, err
+	}
+	ddpi, _, _, err := GetDisplayDPI(display)
+	if err != nil {
+		return 1, err
+	}
+	return ddpi / 96, nil
+}
+
 // DisplayMode contains the description of a display mode.
 // (https://wiki.libsdl.org/SDL_DisplayMode)
 type DisplayMode struct {
@@ -4887,6 +7663,65 @@ type DisplayMode struct {
 	DriverData  unsafe.Pointer // driver-specific data, initialize to 0
 }
 
+// ChooseDisplayMode returns the display mode that SDL uses in fullscreen for
+// the desired resolution and refresh rate, e.g. for a resolution list in an
+// options menu. A refresh rate of 0 means any. It returns the closest mode
+// that is at least as large as desired, like GetClosestDisplayMode. If there
+// is none, e.g. because the desired size is larger than the display, it
+// returns the available mode with the closest size and refresh rate.
+func ChooseDisplayMode(displayIndex int, w, h, refreshRate int32) (DisplayMode, error) {
+	desired := DisplayMode{W: w, H: h, RefreshRate: refreshRate}
+	var closest DisplayMode
+	if _, err := GetClosestDisplayMode(displayIndex, &desired, &closest); err == nil {
+		return closest, nil
+	}
+	modes, err := DisplayModes(displayIndex)
+	if err != nil {
+		return DisplayMode{}, err
+	}
+	if len(modes) == 0 {
+		return DisplayMode{}, errors.New("sdl: the display has no display modes")
+	}
+	distance := func(m DisplayMode) (size, rate int64) {
+		dw, dh := int64(m.W-w), int64(m.H-h)
+		size = dw*dw + dh*dh
+		if refreshRate != 0 {
+			rate = int64(m.RefreshRate - refreshRate)
+			if rate < 0 {
+				rate = -rate
+			}
+		}
+		return
+	}
+	best := modes[0]
+	bestSize, bestRate := distance(best)
+	for _, m := range modes[1:] {
+		size, rate := distance(m)
+		if size < bestSize || size == bestSize && rate < bestRate {
+			best, bestSize, bestRate = m, size, rate
+		}
+	}
+	return best, nil
+}
+
+// DisplayModes returns all display modes of the display, sorted by SDL from
+// largest to smallest.
+func DisplayModes(displayIndex int) ([]DisplayMode, error) {
+	n, err := GetNumDisplayModes(displayIndex)
+	if err != nil {
+		return nil, err
+	}
+	modes := make([]DisplayMode, 0, n)
+	for i := 0; i < n; i++ {
+		mode, err := GetDisplayMode(displayIndex, i)
+		if err != nil {
+			return nil, err
+		}
+		modes = append(modes, mode)
+	}
+	return modes, nil
+}
+
 // GetClosestDisplayMode returns the closest match to the requested display mode.
 // (https://wiki.libsdl.org/SDL_GetClosestDisplayMode)
 func GetClosestDisplayMode(displayIndex int, mode *DisplayMode, closest *DisplayMode) (*DisplayMode, error) {
@@ -4990,38 +7825,90 @@ type Event interface {
 // PollEvent polls for currently pending events.
 // (https://wiki.libsdl.org/SDL_PollEvent)
 func PollEvent() Event {
-	var e CEvent
-	ret, _, _ := pollEvent.Call(uintptr(unsafe.Pointer(&e)))
-	if ret == 0 {
-		return nil
+	for {
+		var e CEvent
+		ret, _, _ := pollEvent.Call(uintptr(unsafe.Pointer(&e)))
+		if ret == 0 {
+			return nil
+		}
+		event := takeEvent(&e)
+		if _, ok := event.(*WakeEvent); ok {
+			continue
+		}
+		if !quitVetoed(event) {
+			return routeEvent(event)
+		}
 	}
-	return goEvent(&e)
 }
 
 // WaitEvent waits indefinitely for the next available event.
 // (https://wiki.libsdl.org/SDL_WaitEvent)
 func WaitEvent() Event {
-	var e CEvent
-	ret, _, _ := waitEvent.Call(uintptr(unsafe.Pointer(&e)))
-	if ret == 0 {
-		return nil
+	for {
+		var e CEvent
+		ret, _, _ := waitEvent.Call(uintptr(unsafe.Pointer(&e)))
+		if ret == 0 {
+			return nil
+		}
+		if event := takeEvent(&e); !quitVetoed(event) {
+			return routeEvent(event)
+		}
 	}
-	return goEvent(&e)
 }
 
 // WaitEventTimeout waits until the specified timeout (in milliseconds) for the
 // next available event.
 // (https://wiki.libsdl.org/SDL_WaitEventTimeout)
 func WaitEventTimeout(timeout int) Event {
-	var e CEvent
-	ret, _, _ := waitEventTimeout.Call(
-		uintptr(unsafe.Pointer(&e)),
-		uintptr(timeout),
-	)
-	if ret == 0 {
-		return nil
+	for {
+		var e CEvent
+		ret, _, _ := waitEventTimeout.Call(
+			uintptr(unsafe.Pointer(&e)),
+			uintptr(timeout),
+		)
+		if ret == 0 {
+			return nil
+		}
+		if event := takeEvent(&e); !quitVetoed(event) {
+			return routeEvent(event)
+		}
+	}
+}
+
+// wakeEventType is the user event type of WakeEvents, 0 until WakeEventLoop
+// is first called.
+var (
+	wakeEventType     uint32
+	wakeEventTypeOnce sync.Once
+)
+
+// WakeEventLoop makes a WaitEvent or WaitEventTimeout that is blocking on the
+// main thread return a *WakeEvent, e.g. because a worker goroutine finished
+// loading a level. It is safe to call from any goroutine. PollEvent skips
+// WakeEvents. The first call reserves a user event type with RegisterEvents.
+func WakeEventLoop() error {
+	wakeEventTypeOnce.Do(func() {
+		if typ := RegisterEvents(1); typ != 0xFFFFFFFF {
+			atomic.StoreUint32(&wakeEventType, typ)
+		}
+	})
+	typ := atomic.LoadUint32(&wakeEventType)
+	if typ == 0 {
+		return errors.New("sdl: no user event type left for WakeEventLoop")
 	}
-	return goEvent(&e)
+	_, err := PushEvent(&WakeEvent{Type: typ})
+	return err
+}
+
+// takeEvent converts an event that was removed from the event queue. Unlike
+// goEvent it frees the file name or text of drop events, which SDL allocates
+// for the receiver of the event.
+func takeEvent(cevent *CEvent) Event {
+	event := goEvent(cevent)
+	if cevent.Type == DROPFILE || cevent.Type == DROPTEXT {
+		free.Call(uintptr((*tDropEvent)(unsafe.Pointer(cevent)).File))
+	}
+	return event
 }
 
 func goEvent(cevent *CEvent) Event {
@@ -5054,6 +7941,8 @@ func goEvent(cevent *CEvent) Event {
 		return (*JoyDeviceAddedEvent)(unsafe.Pointer(cevent))
 	case JOYDEVICEREMOVED:
 		return (*JoyDeviceRemovedEvent)(unsafe.Pointer(cevent))
+	case JOYBATTERYUPDATED:
+		return (*JoyBatteryEvent)(unsafe.Pointer(cevent))
 	case CONTROLLERAXISMOTION:
 		return (*ControllerAxisEvent)(unsafe.Pointer(cevent))
 	case CONTROLLERBUTTONDOWN, CONTROLLERBUTTONUP:
@@ -5083,118 +7972,638 @@ func goEvent(cevent *CEvent) Event {
 		return (*RenderEvent)(unsafe.Pointer(cevent))
 	case QUIT:
 		return (*QuitEvent)(unsafe.Pointer(cevent))
-	case USEREVENT:
-		return (*UserEvent)(unsafe.Pointer(cevent))
 	case CLIPBOARDUPDATE:
 		return (*ClipboardEvent)(unsafe.Pointer(cevent))
 	default:
+		if t := atomic.LoadUint32(&wakeEventType); t != 0 && cevent.Type == t {
+			return (*WakeEvent)(unsafe.Pointer(cevent))
+		}
+		// All event types from RegisterEvents are user events.
+		if USEREVENT <= cevent.Type && cevent.Type < LASTEVENT {
+			return (*UserEvent)(unsafe.Pointer(cevent))
+		}
 		return (*CommonEvent)(unsafe.Pointer(cevent))
 	}
-}
+}
+
+// routeEvent sends a copy of the event to the channel returned by
+// Window.Events for the event's window, if there is one. It returns the event
+// unchanged. The copy makes sure that changes to one of them do not show up
+// in the other.
+func routeEvent(e Event) Event {
+	if len(windowEvents) == 0 {
+		return e
+	}
+	var id uint32
+	var routed Event
+	switch e := e.(type) {
+	case *WindowEvent:
+		c := *e
+		id, routed = c.WindowID, &c
+	case *KeyboardEvent:
+		c := *e
+		id, routed = c.WindowID, &c
+	case *TextEditingEvent:
+		c := *e
+		id, routed = c.WindowID, &c
+	case *TextInputEvent:
+		c := *e
+		id, routed = c.WindowID, &c
+	case *MouseMotionEvent:
+		c := *e
+		id, routed = c.WindowID, &c
+	case *MouseButtonEvent:
+		c := *e
+		id, routed = c.WindowID, &c
+	case *MouseWheelEvent:
+		c := *e
+		id, routed = c.WindowID, &c
+	case *DropEvent:
+		c := *e
+		id, routed = c.WindowID, &c
+	case *UserEvent:
+		c := *e
+		id, routed = c.WindowID, &c
+	default:
+		return e
+	}
+	if c, ok := windowEvents[id]; ok {
+		select {
+		case c <- routed:
+		default:
+		}
+	}
+	return e
+}
+
+type tDropEvent struct {
+	Type      uint32
+	Timestamp uint32
+	File      unsafe.Pointer
+	WindowID  uint32
+}
+
+// EventAction is the action to take in PeepEvents() function.
+// (https://wiki.libsdl.org/SDL_PeepEvents)
+type EventAction uint32
+
+// EventFilter is the function to call when an event happens.
+// (https://wiki.libsdl.org/SDL_SetEventFilter)
+type EventFilter interface {
+	FilterEvent(e Event, userdata interface{}) bool
+}
+
+// GetEventFilter queries the current event filter.
+// (https://wiki.libsdl.org/SDL_GetEventFilter)
+func GetEventFilter() EventFilter {
+	return eventFilterCache
+}
+
+// EventWatchHandle is an event watch callback added with AddEventWatch().
+type EventWatchHandle uintptr
+
+// AddEventWatch adds a callback to be triggered when an event is added to the event queue.
+// (https://wiki.libsdl.org/SDL_AddEventWatch)
+func AddEventWatch(filter EventFilter, userdata interface{}) EventWatchHandle {
+	context := newEventFilterCallbackContext(filter, userdata)
+	addEventWatch.Call(
+		eventFilterCallbackPtr,
+		uintptr(context.handle),
+	)
+	return context.handle
+}
+
+func theEventFilterCallback(userdata, event uintptr) uintptr {
+	context := eventWatches[EventWatchHandle(userdata)]
+	return wrapEventFilterCallback(context.filter, event, context.userdata)
+}
+
+var eventFilterCallbackPtr = syscall.NewCallbackCDecl(theEventFilterCallback)
+
+func wrapEventFilterCallback(filter EventFilter, e uintptr, userdata interface{}) uintptr {
+	gev := goEvent((*CEvent)(unsafe.Pointer(e)))
+	result := filter.FilterEvent(gev, userdata)
+	if result {
+		return 1
+	}
+	return 0
+}
+
+func newEventFilterCallbackContext(filter EventFilter, userdata interface{}) *eventFilterCallbackContext {
+	lastEventWatchHandleMutex.Lock()
+	defer lastEventWatchHandleMutex.Unlock()
+	// Look for the next available watch handle (this should be immediate
+	// unless you're creating a LOT of handlers).
+	for {
+		if _, ok := eventWatches[lastEventWatchHandle]; !ok {
+			break
+		}
+		lastEventWatchHandle++
+	}
+	e := &eventFilterCallbackContext{filter, lastEventWatchHandle, userdata}
+	eventWatches[lastEventWatchHandle] = e
+	lastEventWatchHandle++
+	return e
+}
+
+var (
+	eventFilterCache          EventFilter
+	eventWatches              = make(map[EventWatchHandle]*eventFilterCallbackContext)
+	lastEventWatchHandleMutex sync.Mutex
+	lastEventWatchHandle      EventWatchHandle
+)
+
+type eventFilterCallbackContext struct {
+	filter   EventFilter
+	handle   EventWatchHandle
+	userdata interface{}
+}
+
+// AddEventWatchFunc adds a callback function to be triggered when an event is added to the event queue.
+// (https://wiki.libsdl.org/SDL_AddEventWatch)
+func AddEventWatchFunc(filterFunc eventFilterFunc, userdata interface{}) EventWatchHandle {
+	return AddEventWatch(filterFunc, userdata)
+}
+
+// Frame rate limits of the FPSManager.
+const (
+	FPS_UPPER_LIMIT = 200
+	FPS_LOWER_LIMIT = 1
+	FPS_DEFAULT     = 30
+)
+
+// fpsAverageFrames is the number of frames that FPSManager.AverageFPS
+// averages over.
+const fpsAverageFrames = 60
+
+// FPSManager limits the frame rate of a game loop and measures the actual
+// frame rate, like the framerate module of SDL2_gfx. Call Delay once per frame,
+// after presenting it:
+//
+// 	fps := sdl.NewFPSManager()
+// 	fps.SetRate(60)
+// 	for running {
+// 		// handle events, update and render the frame
+// 		fps.Delay()
+// 	}
+//
+// The time is measured with the high resolution performance counter. The zero
+// value is ready to use with a rate of FPS_DEFAULT, it starts timing at the
+// first call to Delay.
+type FPSManager struct {
+	rate       int
+	frequency  uint64
+	base       uint64 // counter value that the frame times are relative to
+	baseFrames uint64 // frames delayed since base
+	lastFrame  uint64 // counter value at the end of the last Delay
+	frameCount int
+
+	frameTimes     [fpsAverageFrames]uint64 // in performance counter ticks
+	frameTimeIndex int
+	frameTimeCount int
+	frameTimeSum   uint64
+}
+
+// NewFPSManager returns an FPSManager with a rate of FPS_DEFAULT.
+func NewFPSManager() *FPSManager {
+	now := GetPerformanceCounter()
+	return &FPSManager{
+		rate:      FPS_DEFAULT,
+		frequency: GetPerformanceFrequency(),
+		base:      now,
+		lastFrame: now,
+	}
+}
+
+// AverageFPS returns the frame rate averaged over the last frames. It returns
+// 0 before the first frame.
+func (m *FPSManager) AverageFPS() float64 {
+	if m.frameTimeSum == 0 {
+		return 0
+	}
+	return float64(m.frameTimeCount) * float64(m.frequency) / float64(m.frameTimeSum)
+}
+
+// Delay waits until it is time for the next frame. It returns the time that
+// passed since the last call to Delay, including the waiting time. If the frame
+// took longer than the frame rate allows, Delay returns right away and the
+// following frames are timed from now on, instead of rushing to catch up.
+func (m *FPSManager) Delay() time.Duration {
+	if m.frequency == 0 {
+		// This is the zero value, start timing now.
+		m.frequency = GetPerformanceFrequency()
+		m.base = GetPerformanceCounter()
+		m.lastFrame = m.base
+	}
+	m.frameCount++
+	m.baseFrames++
+	target := m.base + m.baseFrames*m.frequency/uint64(m.GetRate())
+
+	now := GetPerformanceCounter()
+	if now < target {
+		// Delay has millisecond resolution, round up so we do not return
+		// early. Frames are timed relative to base, sleeping a little too long
+		// only shortens the next frame's wait so the rate stays the same.
+		ms := ((target-now)*1000 + m.frequency - 1) / m.frequency
+		Delay(uint32(ms))
+		now = GetPerformanceCounter()
+	} else {
+		m.base = now
+		m.baseFrames = 0
+	}
+
+	frameTime := now - m.lastFrame
+	m.lastFrame = now
+	m.frameTimeSum -= m.frameTimes[m.frameTimeIndex]
+	m.frameTimes[m.frameTimeIndex] = frameTime
+	m.frameTimeSum += frameTime
+	m.frameTimeIndex = (m.frameTimeIndex + 1) % fpsAverageFrames
+	if m.frameTimeCount < fpsAverageFrames {
+		m.frameTimeCount++
+	}
+	return countsToDuration(frameTime)
+}
+
+// GetFrameCount returns the number of calls to Delay since the last call to
+// SetRate.
+func (m *FPSManager) GetFrameCount() int {
+	return m.frameCount
+}
+
+// GetRate returns the target frame rate in frames per second.
+func (m *FPSManager) GetRate() int {
+	if m.rate == 0 {
+		return FPS_DEFAULT
+	}
+	return m.rate
+}
+
+// SetRate sets the target frame rate in frames per second. It must be in the
+// range FPS_LOWER_LIMIT to FPS_UPPER_LIMIT. This resets the frame count.
+func (m *FPSManager) SetRate(rate int) error {
+	if rate < FPS_LOWER_LIMIT || rate > FPS_UPPER_LIMIT {
+		return fmt.Errorf(
+			"sdl: frame rate %d is not in the range %d to %d",
+			rate, FPS_LOWER_LIMIT, FPS_UPPER_LIMIT,
+		)
+	}
+	m.rate = rate
+	m.frameCount = 0
+	m.base = GetPerformanceCounter()
+	m.baseFrames = 0
+	return nil
+}
+
+// FPoint defines a two dimensional point.
+// TODO: (https://wiki.libsdl.org/SDL_FPoint)
+type FPoint struct {
+	X float32 // the x coordinate of the point
+	Y float32 // the y coordinate of the point
+}
+
+// InRect reports whether the point resides inside a rectangle.
+// TODO: (https://wiki.libsdl.org/SDL_PointInFRect)
+func (p *FPoint) InRect(r *FRect) bool {
+	if (p.X >= r.X) && (p.X < (r.X + r.W)) &&
+		(p.Y >= r.Y) && (p.Y < (r.Y + r.H)) {
+		return true
+	}
+	return false
+}
+
+// FRect contains the definition of a rectangle, with the origin at the upper left.
+// TODO: (https://wiki.libsdl.org/SDL_FRect)
+type FRect struct {
+	X float32 // the x location of the rectangle's upper left corner
+	Y float32 // the y location of the rectangle's upper left corner
+	W float32 // the width of the rectangle
+	H float32 // the height of the rectangle
+}
+
+// EncloseFPoints calculates a minimal rectangle that encloses a set of points.
+// TODO: (https://wiki.libsdl.org/SDL_EncloseFPoints)
+func EncloseFPoints(points []FPoint, clip *FRect) (FRect, bool) {
+	var result FRect
+
+	if len(points) == 0 {
+		return result, false
+	}
+
+	var minX, minY, maxX, maxY float32
+	added := false
+	for _, val := range points {
+		if clip != nil {
+			// If the clip has no size, we're done
+			if clip.Empty() {
+				return result, false
+			}
+			// Check if the point is inside the clip rect
+			if val.X < clip.X || val.X > clip.X+clip.W ||
+				val.Y < clip.Y || val.Y > clip.Y+clip.H {
+				continue
+			}
+		}
+
+		if !added {
+			// If it's the first point
+			minX = val.X
+			maxX = val.X
+			minY = val.Y
+			maxY = val.Y
+			added = true
+			continue
+		}
+
+		// Find mins and maxes
+		if val.X < minX {
+			minX = val.X
+		} else if val.X > maxX {
+			maxX = val.X
+		}
+		if val.Y < minY {
+			minY = val.Y
+		} else if val.Y > maxY {
+			maxY = val.Y
+		}
+	}
+	if !added {
+		return result, false
+	}
+
+	result.X = minX
+	result.Y = minY
+	result.W = maxX - minX
+	result.H = maxY - minY
+
+	return result, true
+}
+
+// Contains reports whether the point resides inside the rectangle.
+func (a *FRect) Contains(p FPoint) bool {
+	return a != nil && p.InRect(a)
+}
+
+// Empty reports whether a rectangle has no area.
+// TODO: (https://wiki.libsdl.org/SDL_FRectEmpty)
+func (a *FRect) Empty() bool {
+	return a == nil || a.W <= 0 || a.H <= 0
+}
+
+// Equals reports whether two rectangles are equal, within a small epsilon.
+// TODO: (https://wiki.libsdl.org/SDL_FRectEquals)
+func (a *FRect) Equals(b *FRect) bool {
+	const fltEpsilon = 1.1920928955078125e-07 // FLT_EPSILON
+	return a.EqualsEpsilon(b, fltEpsilon)
+}
+
+// EqualsEpsilon reports whether two rectangles are equal, within some given
+// epsilon.
+// TODO: (https://wiki.libsdl.org/SDL_FRectEqualsEpsilon)
+func (a *FRect) EqualsEpsilon(b *FRect, epsilon float32) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	if a == b {
+		return true
+	}
+	abs := func(x float32) float32 {
+		if x < 0 {
+			return -x
+		}
+		return x
+	}
+	return abs(a.X-b.X) <= epsilon &&
+		abs(a.Y-b.Y) <= epsilon &&
+		abs(a.W-b.W) <= epsilon &&
+		abs(a.H-b.H) <= epsilon
+}
+
+// HasIntersection reports whether two rectangles intersect.
+// TODO: (https://wiki.libsdl.org/SDL_HasIntersectionF)
+func (a *FRect) HasIntersection(b *FRect) bool {
+	if a == nil || b == nil {
+		return false
+	}
+
+	// Special case for empty rects
+	if a.Empty() || b.Empty() {
+		return false
+	}
+
+	if a.X >= b.X+b.W || a.X+a.W <= b.X || a.Y >= b.Y+b.H || a.Y+a.H <= b.Y {
+		return false
+	}
+
+	return true
+}
+
+// Intersect calculates the intersection of two rectangles.
+// TODO: (https://wiki.libsdl.org/SDL_IntersectFRect)
+func (a *FRect) Intersect(b *FRect) (FRect, bool) {
+	var result FRect
+
+	if a == nil || b == nil {
+		return result, false
+	}
+
+	// Special case for empty rects
+	if a.Empty() || b.Empty() {
+		return result, false
+	}
+
+	aMin := a.X
+	aMax := aMin + a.W
+	bMin := b.X
+	bMax := bMin + b.W
+	if bMin > aMin {
+		aMin = bMin
+	}
+	result.X = aMin
+	if bMax < aMax {
+		aMax = bMax
+	}
+	result.W = aMax - aMin
+
+	aMin = a.Y
+	aMax = aMin + a.H
+	bMin = b.Y
+	bMax = bMin + b.H
+	if bMin > aMin {
+		aMin = bMin
+	}
+	result.Y = aMin
+	if bMax < aMax {
+		aMax = bMax
+	}
+	result.H = aMax - aMin
+
+	return result, !result.Empty()
+}
+
+// IntersectLine calculates the intersection of a rectangle and a line segment.
+// TODO: (https://wiki.libsdl.org/SDL_IntersectFRectAndLine)
+func (a *FRect) IntersectLine(X1, Y1, X2, Y2 *float32) bool {
+	if a.Empty() {
+		return false
+	}
+
+	x1 := *X1
+	y1 := *Y1
+	x2 := *X2
+	y2 := *Y2
+	rectX1 := a.X
+	rectY1 := a.Y
+	rectX2 := a.X + a.W
+	rectY2 := a.Y + a.H
+
+	// Check if the line is entirely inside the rect
+	if x1 >= rectX1 && x1 <= rectX2 && x2 >= rectX1 && x2 <= rectX2 &&
+		y1 >= rectY1 && y1 <= rectY2 && y2 >= rectY1 && y2 <= rectY2 {
+		return true
+	}
+
+	// Check if the line is entirely outside the rect
+	if (x1 < rectX1 && x2 < rectX1) || (x1 > rectX2 && x2 > rectX2) ||
+		(y1 < rectY1 && y2 < rectY1) || (y1 > rectY2 && y2 > rectY2) {
+		return false
+	}
+
+	// Check if the line is horizontal
+	if y1 == y2 {
+		if x1 < rectX1 {
+			*X1 = rectX1
+		} else if x1 > rectX2 {
+			*X1 = rectX2
+		}
+		if x2 < rectX1 {
+			*X2 = rectX1
+		} else if x2 > rectX2 {
+			*X2 = rectX2
+		}
+
+		return true
+	}
 
-type tDropEvent struct {
-	Type      uint32
-	Timestamp uint32
-	File      unsafe.Pointer
-	WindowID  uint32
-}
+	// Check if the line is vertical
+	if x1 == x2 {
+		if y1 < rectY1 {
+			*Y1 = rectY1
+		} else if y1 > rectY2 {
+			*Y1 = rectY2
+		}
+		if y2 < rectY1 {
+			*Y2 = rectY1
+		} else if y2 > rectY2 {
+			*Y2 = rectY2
+		}
 
-// EventAction is the action to take in PeepEvents() function.
-// (https://wiki.libsdl.org/SDL_PeepEvents)
-type EventAction uint32
+		return true
+	}
 
-// EventFilter is the function to call when an event happens.
-// (https://wiki.libsdl.org/SDL_SetEventFilter)
-type EventFilter interface {
-	FilterEvent(e Event, userdata interface{}) bool
-}
+	// Use Cohen-Sutherland algorithm when all shortcuts fail
+	outCode1 := computeOutCodeF(a, x1, y1)
+	outCode2 := computeOutCodeF(a, x2, y2)
+	for outCode1 != 0 || outCode2 != 0 {
+		if outCode1&outCode2 != 0 {
+			return false
+		}
 
-// GetEventFilter queries the current event filter.
-// (https://wiki.libsdl.org/SDL_GetEventFilter)
-func GetEventFilter() EventFilter {
-	return eventFilterCache
-}
+		outCode := outCode1
+		if outCode == 0 {
+			outCode = outCode2
+		}
+		var x, y float32
+		if outCode&codeTop != 0 {
+			y = rectY1
+			x = x1 + ((x2-x1)*(y-y1))/(y2-y1)
+		} else if outCode&codeBottom != 0 {
+			y = rectY2
+			x = x1 + ((x2-x1)*(y-y1))/(y2-y1)
+		} else if outCode&codeLeft != 0 {
+			x = rectX1
+			y = y1 + ((y2-y1)*(x-x1))/(x2-x1)
+		} else if outCode&codeRight != 0 {
+			x = rectX2
+			y = y1 + ((y2-y1)*(x-x1))/(x2-x1)
+		}
 
-// EventWatchHandle is an event watch callback added with AddEventWatch().
-type EventWatchHandle uintptr
+		if outCode1 != 0 {
+			x1 = x
+			y1 = y
+			outCode1 = computeOutCodeF(a, x, y)
+		} else {
+			x2 = x
+			y2 = y
+			outCode2 = computeOutCodeF(a, x, y)
+		}
+	}
 
-// AddEventWatch adds a callback to be triggered when an event is added to the event queue.
-// (https://wiki.libsdl.org/SDL_AddEventWatch)
-func AddEventWatch(filter EventFilter, userdata interface{}) EventWatchHandle {
-	context := newEventFilterCallbackContext(filter, userdata)
-	addEventWatch.Call(
-		eventFilterCallbackPtr,
-		uintptr(context.handle),
-	)
-	return context.handle
-}
+	*X1 = x1
+	*Y1 = y1
+	*X2 = x2
+	*Y2 = y2
 
-func theEventFilterCallback(userdata, event uintptr) uintptr {
-	context := eventWatches[EventWatchHandle(userdata)]
-	return wrapEventFilterCallback(context.filter, event, context.userdata)
+	return true
 }
 
-var eventFilterCallbackPtr = syscall.NewCallbackCDecl(theEventFilterCallback)
-
-func wrapEventFilterCallback(filter EventFilter, e uintptr, userdata interface{}) uintptr {
-	gev := goEvent((*CEvent)(unsafe.Pointer(e)))
-	result := filter.FilterEvent(gev, userdata)
-	if result {
-		return 1
+func computeOutCodeF(rect *FRect, x, y float32) int {
+	code := 0
+	if y < rect.Y {
+		code |= codeTop
+	} else if y > rect.Y+rect.H {
+		code |= codeBottom
 	}
-	return 0
-}
-
-func newEventFilterCallbackContext(filter EventFilter, userdata interface{}) *eventFilterCallbackContext {
-	lastEventWatchHandleMutex.Lock()
-	defer lastEventWatchHandleMutex.Unlock()
-	// Look for the next available watch handle (this should be immediate
-	// unless you're creating a LOT of handlers).
-	for {
-		if _, ok := eventWatches[lastEventWatchHandle]; !ok {
-			break
-		}
-		lastEventWatchHandle++
+	if x < rect.X {
+		code |= codeLeft
+	} else if x > rect.X+rect.W {
+		code |= codeRight
 	}
-	e := &eventFilterCallbackContext{filter, lastEventWatchHandle, userdata}
-	eventWatches[lastEventWatchHandle] = e
-	lastEventWatchHandle++
-	return e
+	return code
 }
 
-var (
-	eventFilterCache          EventFilter
-	eventWatches              = make(map[EventWatchHandle]*eventFilterCallbackContext)
-	lastEventWatchHandleMutex sync.Mutex
-	lastEventWatchHandle      EventWatchHandle
-)
+// Union calculates the union of two rectangles.
+// TODO: (https://wiki.libsdl.org/SDL_UnionFRect)
+func (a *FRect) Union(b *FRect) FRect {
+	var result FRect
 
-type eventFilterCallbackContext struct {
-	filter   EventFilter
-	handle   EventWatchHandle
-	userdata interface{}
-}
+	if a == nil || b == nil {
+		return result
+	}
 
-// AddEventWatchFunc adds a callback function to be triggered when an event is added to the event queue.
-// (https://wiki.libsdl.org/SDL_AddEventWatch)
-func AddEventWatchFunc(filterFunc eventFilterFunc, userdata interface{}) EventWatchHandle {
-	return AddEventWatch(filterFunc, userdata)
-}
+	// Special case for empty rects
+	if a.Empty() {
+		return *b
+	} else if b.Empty() {
+		return *a
+	}
 
-// FPoint defines a two dimensional point.
-// TODO: (https://wiki.libsdl.org/SDL_FPoint)
-type FPoint struct {
-	X float32 // the x coordinate of the point
-	Y float32 // the y coordinate of the point
-}
+	aMin := a.X
+	aMax := aMin + a.W
+	bMin := b.X
+	bMax := bMin + b.W
+	if bMin < aMin {
+		aMin = bMin
+	}
+	result.X = aMin
+	if bMax > aMax {
+		aMax = bMax
+	}
+	result.W = aMax - aMin
 
-// FRect contains the definition of a rectangle, with the origin at the upper left.
-// TODO: (https://wiki.libsdl.org/SDL_FRect)
-type FRect struct {
-	X float32 // the x location of the rectangle's upper left corner
-	Y float32 // the y location of the rectangle's upper left corner
-	W float32 // the width of the rectangle
-	H float32 // the height of the rectangle
+	aMin = a.Y
+	aMax = aMin + a.H
+	bMin = b.Y
+	bMax = bMin + b.H
+	if bMin < aMin {
+		aMin = bMin
+	}
+	result.Y = aMin
+	if bMax > aMax {
+		aMax = bMax
+	}
+	result.H = aMax - aMin
+
+	return result
 }
 
 // Finger contains touch information.
@@ -5208,9 +8617,19 @@ type Finger struct {
 // FingerID is a finger id.
 type FingerID int64
 
+// FlashOperation tells Window.Flash how to flash the window.
+// (https://wiki.libsdl.org/SDL_FlashOperation)
+type FlashOperation int
+
 // GLContext is an opaque handle to an OpenGL context.
 type GLContext uintptr
 
+// Destroy deletes the OpenGL context, see GLDeleteContext.
+// (https://wiki.libsdl.org/SDL_GL_DeleteContext)
+func (context GLContext) Destroy() {
+	GLDeleteContext(context)
+}
+
 // GLattr is an OpenGL configuration attribute.
 //(https://wiki.libsdl.org/SDL_GLattr)
 type GLattr uint32
@@ -5232,6 +8651,26 @@ func GameControllerOpen(index int) *GameController {
 	return (*GameController)(unsafe.Pointer(ret))
 }
 
+// AllBindings returns the joystick bindings of all controller axes and
+// buttons that are mapped, e.g. for showing them in a mapping editor.
+func (ctrl *GameController) AllBindings() GameControllerBindings {
+	bindings := GameControllerBindings{
+		Axes:    make(map[GameControllerAxis]GameControllerButtonBind),
+		Buttons: make(map[GameControllerButton]GameControllerButtonBind),
+	}
+	for axis := GameControllerAxis(0); axis < CONTROLLER_AXIS_MAX; axis++ {
+		if bind := ctrl.BindForAxis(axis); bind.Type() != CONTROLLER_BINDTYPE_NONE {
+			bindings.Axes[axis] = bind
+		}
+	}
+	for btn := GameControllerButton(0); btn < CONTROLLER_BUTTON_MAX; btn++ {
+		if bind := ctrl.BindForButton(btn); bind.Type() != CONTROLLER_BINDTYPE_NONE {
+			bindings.Buttons[btn] = bind
+		}
+	}
+	return bindings
+}
+
 // Attached reports whether a controller has been opened and is currently connected.
 // (https://wiki.libsdl.org/SDL_GameControllerGetAttached)
 func (ctrl *GameController) Attached() bool {
@@ -5252,23 +8691,29 @@ func (ctrl *GameController) Axis(axis GameControllerAxis) int16 {
 // BindForAxis returns the SDL joystick layer binding for a controller button mapping.
 // (https://wiki.libsdl.org/SDL_GameControllerGetBindForAxis)
 func (ctrl *GameController) BindForAxis(axis GameControllerAxis) GameControllerButtonBind {
-	// TODO why does this not return a pointer?
-	ret, _, _ := gameControllerGetBindForAxis.Call(
+	// The 12 byte struct is returned through a hidden pointer that the caller
+	// passes as the first argument, both on 386 and amd64.
+	var bind GameControllerButtonBind
+	gameControllerGetBindForAxis.Call(
+		uintptr(unsafe.Pointer(&bind)),
 		uintptr(unsafe.Pointer(ctrl)),
 		uintptr(axis),
 	)
-	return *((*GameControllerButtonBind)(unsafe.Pointer(ret)))
+	return bind
 }
 
 // BindForButton returns the SDL joystick layer binding for this controller button mapping.
 // (https://wiki.libsdl.org/SDL_GameControllerGetBindForButton)
 func (ctrl *GameController) BindForButton(btn GameControllerButton) GameControllerButtonBind {
-	// TODO why does this not return a pointer?
-	ret, _, _ := gameControllerGetBindForButton.Call(
+	// The 12 byte struct is returned through a hidden pointer that the caller
+	// passes as the first argument, both on 386 and amd64.
+	var bind GameControllerButtonBind
+	gameControllerGetBindForButton.Call(
+		uintptr(unsafe.Pointer(&bind)),
 		uintptr(unsafe.Pointer(ctrl)),
 		uintptr(btn),
 	)
-	return *((*GameControllerButtonBind)(unsafe.Pointer(ret)))
+	return bind
 }
 
 // Button returns the current state of a button on a game controller.
@@ -5294,11 +8739,20 @@ func (ctrl *GameController) Joystick() *Joystick {
 	return (*Joystick)(unsafe.Pointer(ret))
 }
 
+// LeftStick returns the state of the left analog stick, see NewStickState.
+func (ctrl *GameController) LeftStick(deadZone float32) StickState {
+	return NewStickState(
+		ctrl.Axis(CONTROLLER_AXIS_LEFTX),
+		ctrl.Axis(CONTROLLER_AXIS_LEFTY),
+		deadZone,
+	)
+}
+
 // Mapping returns the current mapping of a Game Controller.
 // (https://wiki.libsdl.org/SDL_GameControllerMapping)
 func (ctrl *GameController) Mapping() string {
 	ret, _, _ := gameControllerMapping.Call(uintptr(unsafe.Pointer(ctrl)))
-	return sdlToGoString(ret)
+	return takeSDLString(ret)
 }
 
 // Name returns the implementation dependent name for an opened game controller.
@@ -5320,6 +8774,15 @@ func (ctrl *GameController) ProductVersion() int {
 	return int(ret)
 }
 
+// RightStick returns the state of the right analog stick, see NewStickState.
+func (ctrl *GameController) RightStick(deadZone float32) StickState {
+	return NewStickState(
+		ctrl.Axis(CONTROLLER_AXIS_RIGHTX),
+		ctrl.Axis(CONTROLLER_AXIS_RIGHTY),
+		deadZone,
+	)
+}
+
 // Vendor returns the USB vendor ID of an opened controller, if available, 0 otherwise.
 func (ctrl *GameController) Vendor() int {
 	ret, _, _ := gameControllerGetVendor.Call(uintptr(unsafe.Pointer(ctrl)))
@@ -5356,31 +8819,27 @@ func GameControllerGetButtonFromString(pchString string) GameControllerButton {
 // GameControllerButtonBind SDL joystick layer binding for controller button/axis mapping.
 type GameControllerButtonBind struct {
 	bindType GameControllerBindType
-	value    [8]byte // TODO this is a union of two ints in the SDL code
+	value    [8]byte // a union of button, axis or (hat, hat mask), all C ints
 }
 
 // Axis returns axis mapped for this SDL joystick layer binding.
 func (bind *GameControllerButtonBind) Axis() int {
-	val, _ := binary.Varint(bind.value[:4])
-	return int(val)
+	return int(int32(binary.LittleEndian.Uint32(bind.value[:4])))
 }
 
 // Button returns button mapped for this SDL joystick layer binding.
 func (bind *GameControllerButtonBind) Button() int {
-	val, _ := binary.Varint(bind.value[:4])
-	return int(val)
+	return int(int32(binary.LittleEndian.Uint32(bind.value[:4])))
 }
 
 // Hat returns hat mapped for this SDL joystick layer binding.
 func (bind *GameControllerButtonBind) Hat() int {
-	val, _ := binary.Varint(bind.value[:4])
-	return int(val)
+	return int(int32(binary.LittleEndian.Uint32(bind.value[:4])))
 }
 
 // HatMask returns hat mask for this SDL joystick layer binding.
 func (bind *GameControllerButtonBind) HatMask() int {
-	val, _ := binary.Varint(bind.value[4:8])
-	return int(val)
+	return int(int32(binary.LittleEndian.Uint32(bind.value[4:8])))
 }
 
 // Type returns the type of game controller input for this SDL joystick layer binding.
@@ -5388,6 +8847,62 @@ func (bind *GameControllerButtonBind) Type() int {
 	return int(bind.bindType)
 }
 
+// GameControllerBindings contains the joystick bindings of a game
+// controller's axes and buttons, see GameController.AllBindings. Unmapped
+// axes and buttons are not in the maps.
+type GameControllerBindings struct {
+	Axes    map[GameControllerAxis]GameControllerButtonBind
+	Buttons map[GameControllerButton]GameControllerButtonBind
+}
+
+// GameControllerType is the type of a game controller, e.g.
+// CONTROLLER_TYPE_PS4.
+// (https://wiki.libsdl.org/SDL_GameControllerType)
+type GameControllerType int
+
+// controllerFamily groups controller types with the same button labels.
+type controllerFamily int
+
+const (
+	xboxFamily controllerFamily = iota
+	playStationFamily
+	nintendoFamily
+)
+
+func (t GameControllerType) family() controllerFamily {
+	switch t {
+	case CONTROLLER_TYPE_PS3, CONTROLLER_TYPE_PS4, CONTROLLER_TYPE_PS5:
+		return playStationFamily
+	case CONTROLLER_TYPE_NINTENDO_SWITCH_PRO,
+		CONTROLLER_TYPE_NINTENDO_SWITCH_JOYCON_LEFT,
+		CONTROLLER_TYPE_NINTENDO_SWITCH_JOYCON_RIGHT,
+		CONTROLLER_TYPE_NINTENDO_SWITCH_JOYCON_PAIR:
+		return nintendoFamily
+	}
+	return xboxFamily
+}
+
+var buttonLabels = [...][CONTROLLER_BUTTON_MAX]string{
+	xboxFamily: {
+		"A", "B", "X", "Y", "View", "Xbox", "Menu", "LS", "RS", "LB", "RB",
+		"Up", "Down", "Left", "Right",
+	},
+	playStationFamily: {
+		"Cross", "Circle", "Square", "Triangle", "Share", "PS", "Options",
+		"L3", "R3", "L1", "R1", "Up", "Down", "Left", "Right",
+	},
+	nintendoFamily: {
+		"A", "B", "X", "Y", "-", "Home", "+", "Left Stick", "Right Stick",
+		"L", "R", "Up", "Down", "Left", "Right",
+	},
+}
+
+var triggerLabels = [...][2]string{
+	xboxFamily:        {"LT", "RT"},
+	playStationFamily: {"L2", "R2"},
+	nintendoFamily:    {"ZL", "ZR"},
+}
+
 // GestureID is the unique id of the closest gesture to the performed stroke.
 type GestureID int64
 
@@ -5684,6 +9199,18 @@ type HapticEffect interface {
 	pointer() uintptr
 }
 
+// HapticInfo describes a haptic device, see Haptics.
+type HapticInfo struct {
+	Index   int    // the index for HapticOpen
+	Name    string // the implementation dependent name
+	Axes    int    // the number of haptic axes
+	Effects int    // the number of effects the device can store
+	// SupportedEffects has a bit set for every supported effect type, e.g.
+	// HAPTIC_SINE, and for features like HAPTIC_GAIN.
+	SupportedEffects uint32
+	Rumble           bool // whether simple rumble playback is supported
+}
+
 // HapticLeftRight contains a template for a left/right effect.
 // (https://wiki.libsdl.org/SDL_HapticLeftRight)
 type HapticLeftRight struct {
@@ -5824,6 +9351,25 @@ func (e *JoyButtonEvent) GetType() uint32 {
 	return e.Type
 }
 
+// JoyBatteryEvent contains joystick battery level change event information.
+// (https://wiki.libsdl.org/SDL_JoyBatteryEvent)
+type JoyBatteryEvent struct {
+	Type      uint32             // JOYBATTERYUPDATED
+	Timestamp uint32             // the timestamp of the event
+	Which     JoystickID         // the joystick instance id
+	Level     JoystickPowerLevel // the joystick battery level
+}
+
+// GetTimestamp returns the timestamp of the event.
+func (e *JoyBatteryEvent) GetTimestamp() uint32 {
+	return e.Timestamp
+}
+
+// GetType returns the event type.
+func (e *JoyBatteryEvent) GetType() uint32 {
+	return e.Type
+}
+
 // JoyDeviceAddedEvent contains joystick device event information.
 // (https://wiki.libsdl.org/SDL_JoyDeviceEvent)
 type JoyDeviceAddedEvent struct {
@@ -5965,8 +9511,9 @@ func (joy *Joystick) CurrentPowerLevel() JoystickPowerLevel {
 // GUID returns the implementation-dependent GUID for the joystick.
 // (https://wiki.libsdl.org/SDL_JoystickGetGUID)
 func (joy *Joystick) GUID() JoystickGUID {
-	ret, _, _ := joystickGetGUID.Call(uintptr(unsafe.Pointer(joy)))
-	return *(*JoystickGUID)(unsafe.Pointer(ret))
+	var guid JoystickGUID
+	joystickGetGUID.Call(uintptr(unsafe.Pointer(&guid)), uintptr(unsafe.Pointer(joy)))
+	return guid
 }
 
 // Hat returns the current state of a POV hat on a joystick.
@@ -6046,6 +9593,9 @@ func (joy *Joystick) Vendor() int {
 }
 
 // JoystickGUID is a stable unique id for a joystick device.
+//
+// SDL returns this 16 byte struct by value, which on Windows means the caller
+// passes a pointer to the result as a hidden first argument.
 type JoystickGUID struct {
 	data [16]byte
 }
@@ -6053,16 +9603,21 @@ type JoystickGUID struct {
 // JoystickGetDeviceGUID returns the implementation dependent GUID for the joystick at a given device index.
 // (https://wiki.libsdl.org/SDL_JoystickGetDeviceGUID)
 func JoystickGetDeviceGUID(index int) JoystickGUID {
-	ret, _, _ := joystickGetDeviceGUID.Call(uintptr(index))
-	return *(*JoystickGUID)(unsafe.Pointer(ret))
+	var guid JoystickGUID
+	joystickGetDeviceGUID.Call(uintptr(unsafe.Pointer(&guid)), uintptr(index))
+	return guid
 }
 
 // JoystickGetGUIDFromString converts a GUID string into a JoystickGUID structure.
 // (https://wiki.libsdl.org/SDL_JoystickGetGUIDFromString)
 func JoystickGetGUIDFromString(pchGUID string) JoystickGUID {
+	var guid JoystickGUID
 	g := append([]byte(pchGUID), 0)
-	ret, _, _ := joystickGetGUIDFromString.Call(uintptr(unsafe.Pointer(&g[0])))
-	return *(*JoystickGUID)(unsafe.Pointer(ret))
+	joystickGetGUIDFromString.Call(
+		uintptr(unsafe.Pointer(&guid)),
+		uintptr(unsafe.Pointer(&g[0])),
+	)
+	return guid
 }
 
 // JoystickID is joystick's instance id.
@@ -6139,17 +9694,193 @@ func GetModState() Keymod {
 	return Keymod(ret)
 }
 
-// Keysym contains key information used in key events.
-// (https://wiki.libsdl.org/SDL_Keysym)
-type Keysym struct {
-	Scancode Scancode // SDL physical key code
-	Sym      Keycode  // SDL virtual key code
-	Mod      uint16   // current key modifiers
-	unused   uint32   // unused
+// Keysym contains key information used in key events.
+// (https://wiki.libsdl.org/SDL_Keysym)
+type Keysym struct {
+	Scancode Scancode // SDL physical key code
+	Sym      Keycode  // SDL virtual key code
+	Mod      uint16   // current key modifiers
+	unused   uint32   // unused
+}
+
+// LayoutWatcher caches the key codes and labels of all scancodes for the
+// current keyboard layout. Key binding UIs store scancodes, which name physical
+// key positions, but must show the label printed on the player's keyboard,
+// e.g. SCANCODE_Q is "Q" on a QWERTY keyboard and "A" on an AZERTY keyboard.
+// Pass all events to HandleEvent so the cache is refreshed when the user
+// switches the layout:
+//
+//	layout := sdl.NewLayoutWatcher()
+//	for e := sdl.PollEvent(); e != nil; e = sdl.PollEvent() {
+//		layout.HandleEvent(e)
+//	}
+//	jumpLabel := layout.ScancodeToLabel(jumpScancode)
+type LayoutWatcher struct {
+	// OnChange is called from HandleEvent after the layout changed.
+	OnChange func()
+
+	keys   [NUM_SCANCODES]Keycode
+	labels [NUM_SCANCODES]string
+}
+
+// NewLayoutWatcher returns a LayoutWatcher for the current keyboard layout.
+func NewLayoutWatcher() *LayoutWatcher {
+	w := &LayoutWatcher{}
+	w.Refresh()
+	return w
+}
+
+// HandleEvent refreshes the cache on KEYMAPCHANGED events. It returns true if
+// the event was a layout change, after calling OnChange.
+func (w *LayoutWatcher) HandleEvent(e Event) bool {
+	if e.GetType() != KEYMAPCHANGED {
+		return false
+	}
+	w.Refresh()
+	if w.OnChange != nil {
+		w.OnChange()
+	}
+	return true
+}
+
+// Key returns the key code that the scancode produces in the current layout,
+// like GetKeyFromScancode. Invalid scancodes return K_UNKNOWN.
+func (w *LayoutWatcher) Key(code Scancode) Keycode {
+	if code >= NUM_SCANCODES {
+		return K_UNKNOWN
+	}
+	return w.keys[code]
+}
+
+// Refresh reads the key codes and labels for the current layout. HandleEvent
+// calls it automatically.
+func (w *LayoutWatcher) Refresh() {
+	for i := range w.keys {
+		code := Scancode(i)
+		key := GetKeyFromScancode(code)
+		label := ""
+		if key != K_UNKNOWN {
+			label = GetKeyName(key)
+		}
+		if label == "" {
+			label = GetScancodeName(code)
+		}
+		w.keys[i] = key
+		w.labels[i] = label
+	}
+}
+
+// ScancodeToLabel returns the label of the key at the scancode's position in
+// the current layout, e.g. "Q", "Space" or "Left Shift". If the layout does not
+// map the scancode to a key, the scancode's name is returned. Scancodes without
+// a name return an empty string.
+func (w *LayoutWatcher) ScancodeToLabel(code Scancode) string {
+	if code >= NUM_SCANCODES {
+		return ""
+	}
+	return w.labels[code]
+}
+
+// Letterbox describes how a logical screen of fixed size is scaled to fit an
+// output of another size while keeping its aspect ratio. The logical screen is
+// centered, leaving black bars at the top and bottom (letterbox) or left and
+// right (pillarbox).
+type Letterbox struct {
+	LogicalW, LogicalH int32   // the size of the logical screen
+	Scale              float32 // output pixels per logical pixel
+	Viewport           Rect    // the area of the output showing the logical screen
+}
+
+// NewLetterbox computes the largest centered area of the output that has the
+// aspect ratio of the logical size.
+func NewLetterbox(outputW, outputH, logicalW, logicalH int32) Letterbox {
+	box := Letterbox{LogicalW: logicalW, LogicalH: logicalH}
+	if logicalW <= 0 || logicalH <= 0 || outputW <= 0 || outputH <= 0 {
+		return box
+	}
+	scale := math.Min(
+		float64(outputW)/float64(logicalW),
+		float64(outputH)/float64(logicalH),
+	)
+	w := int32(math.Round(float64(logicalW) * scale))
+	h := int32(math.Round(float64(logicalH) * scale))
+	box.Scale = float32(scale)
+	box.Viewport = Rect{X: (outputW - w) / 2, Y: (outputH - h) / 2, W: w, H: h}
+	return box
+}
+
+// ToLogical converts output coordinates, e.g. the mouse position in a window
+// without high-DPI support, to logical coordinates. inside is false for points
+// on the black bars.
+func (b Letterbox) ToLogical(x, y int32) (logicalX, logicalY float32, inside bool) {
+	if b.Scale == 0 {
+		return 0, 0, false
+	}
+	logicalX = float32(x-b.Viewport.X) / b.Scale
+	logicalY = float32(y-b.Viewport.Y) / b.Scale
+	inside = 0 <= logicalX && logicalX < float32(b.LogicalW) &&
+		0 <= logicalY && logicalY < float32(b.LogicalH)
+	return
+}
+
+// ToOutput converts logical coordinates to output coordinates.
+func (b Letterbox) ToOutput(logicalX, logicalY float32) (x, y int32) {
+	x = b.Viewport.X + int32(math.Round(float64(logicalX*b.Scale)))
+	y = b.Viewport.Y + int32(math.Round(float64(logicalY*b.Scale)))
+	return
+}
+
+// Locale is a language and an optional country, see GetPreferredLocales.
+// (https://wiki.libsdl.org/SDL_Locale)
+type Locale struct {
+	Language string // an ISO-639 language specifier like "en" or "de"
+	Country  string // an ISO-3166 country code like "US" or "DE", may be empty
+}
+
+// String returns the locale in the form "en_US", or "en" if it has no
+// country.
+func (l Locale) String() string {
+	if l.Country == "" {
+		return l.Language
+	}
+	return l.Language + "_" + l.Country
+}
+
+// LogCategory is a predefined or custom log category. Categories from
+// LOG_CATEGORY_CUSTOM on are free for application use.
+// (https://wiki.libsdl.org/SDL_LOG_CATEGORY)
+type LogCategory int
+
+var logCategoryNames = [...]string{
+	"application",
+	"error",
+	"assert",
+	"system",
+	"audio",
+	"video",
+	"render",
+	"input",
+	"test",
+}
+
+// String returns the lower-case name of the category, e.g. "video". Custom
+// categories are written as "custom", "custom+1" and so on.
+func (c LogCategory) String() string {
+	switch {
+	case 0 <= c && int(c) < len(logCategoryNames):
+		return logCategoryNames[c]
+	case c == LOG_CATEGORY_CUSTOM:
+		return "custom"
+	case c > LOG_CATEGORY_CUSTOM:
+		return fmt.Sprintf("custom+%d", c-LOG_CATEGORY_CUSTOM)
+	case c >= LOG_CATEGORY_RESERVED1:
+		return fmt.Sprintf("reserved%d", c-LOG_CATEGORY_RESERVED1+1)
+	}
+	return fmt.Sprintf("LogCategory(%d)", int(c))
 }
 
 // LogOutputFunction is the function to call instead of the default
-type LogOutputFunction func(data interface{}, category int, pri LogPriority, message string)
+type LogOutputFunction func(data interface{}, category LogCategory, pri LogPriority, message string)
 
 // LogGetOutputFunction returns the current log output function.
 // (https://wiki.libsdl.org/SDL_LogGetOutputFunction)
@@ -6161,13 +9892,70 @@ func LogGetOutputFunction() (LogOutputFunction, interface{}) {
 // (https://wiki.libsdl.org/SDL_LogPriority)
 type LogPriority uint32
 
+var logPriorityNames = [...]string{
+	LOG_PRIORITY_VERBOSE:  "verbose",
+	LOG_PRIORITY_DEBUG:    "debug",
+	LOG_PRIORITY_INFO:     "info",
+	LOG_PRIORITY_WARN:     "warn",
+	LOG_PRIORITY_ERROR:    "error",
+	LOG_PRIORITY_CRITICAL: "critical",
+}
+
+// String returns the lower-case name of the priority, e.g. "warn".
+func (p LogPriority) String() string {
+	if LOG_PRIORITY_VERBOSE <= p && p < NUM_LOG_PRIORITIES {
+		return logPriorityNames[p]
+	}
+	return fmt.Sprintf("LogPriority(%d)", uint32(p))
+}
+
 // LogGetPriority returns the priority of a particular log category.
 // (https://wiki.libsdl.org/SDL_LogGetPriority)
-func LogGetPriority(category int) LogPriority {
+func LogGetPriority(category LogCategory) LogPriority {
 	ret, _, _ := logGetPriority.Call(uintptr(category))
 	return LogPriority(ret)
 }
 
+// MemoryAllocator provides the memory for SDL, see SetMemoryAllocator.
+type MemoryAllocator interface {
+	Malloc(size uintptr) unsafe.Pointer
+	Calloc(n, size uintptr) unsafe.Pointer
+	Realloc(mem unsafe.Pointer, size uintptr) unsafe.Pointer
+	Free(mem unsafe.Pointer)
+}
+
+// MemoryFunctions holds pointers to the C functions SDL uses to allocate
+// memory. Its methods call these functions so it implements MemoryAllocator.
+type MemoryFunctions struct {
+	MallocFunc  uintptr // void *malloc(size_t size)
+	CallocFunc  uintptr // void *calloc(size_t nmemb, size_t size)
+	ReallocFunc uintptr // void *realloc(void *mem, size_t size)
+	FreeFunc    uintptr // void free(void *mem)
+}
+
+// Calloc calls the calloc function.
+func (f MemoryFunctions) Calloc(n, size uintptr) unsafe.Pointer {
+	ret, _, _ := syscall.Syscall(f.CallocFunc, 2, n, size, 0)
+	return unsafe.Pointer(ret)
+}
+
+// Free calls the free function.
+func (f MemoryFunctions) Free(mem unsafe.Pointer) {
+	syscall.Syscall(f.FreeFunc, 1, uintptr(mem), 0, 0)
+}
+
+// Malloc calls the malloc function.
+func (f MemoryFunctions) Malloc(size uintptr) unsafe.Pointer {
+	ret, _, _ := syscall.Syscall(f.MallocFunc, 1, size, 0, 0)
+	return unsafe.Pointer(ret)
+}
+
+// Realloc calls the realloc function.
+func (f MemoryFunctions) Realloc(mem unsafe.Pointer, size uintptr) unsafe.Pointer {
+	ret, _, _ := syscall.Syscall(f.ReallocFunc, 2, uintptr(mem), size, 0)
+	return unsafe.Pointer(ret)
+}
+
 // MessageBoxButtonData contains individual button data for a message box.
 // (https://wiki.libsdl.org/SDL_MessageBoxButtonData)
 type MessageBoxButtonData struct {
@@ -6296,7 +10084,26 @@ func (e *MultiGestureEvent) GetType() uint32 {
 	return e.Type
 }
 
+// RotationDegrees returns DTheta, which is in radians, in degrees.
+func (e *MultiGestureEvent) RotationDegrees() float32 {
+	return e.DTheta * 180 / math.Pi
+}
+
+// ZoomFactor returns the factor by which the fingers spread apart during this
+// motion, to be multiplied onto the current zoom level for pinch gestures. It
+// is greater than 1 when the fingers move apart and less than 1 when they
+// pinch together.
+func (e *MultiGestureEvent) ZoomFactor() float32 {
+	return 1 + e.DDist
+}
+
 // Mutex is the SDL mutex structure.
+//
+// Prefer Go's sync.Mutex in pure Go code. Use a Mutex when the lock is shared
+// with C code that runs on SDL's own threads, e.g. a C audio callback, or when
+// it is passed to Cond.Wait. Note that Go code can use LockAudioDevice to
+// synchronize with an audio callback. Use Locker to pass a Mutex to code that
+// expects a sync.Locker.
 type Mutex struct {
 	Recursive int
 	Owner     ThreadID
@@ -6329,6 +10136,29 @@ func (mutex *Mutex) Lock() error {
 	return nil
 }
 
+// Locker returns the mutex as a sync.Locker. Its Lock and Unlock methods
+// panic if SDL fails to lock or unlock the mutex, which only happens for an
+// invalid mutex.
+func (mutex *Mutex) Locker() sync.Locker {
+	return mutexLocker{mutex}
+}
+
+type mutexLocker struct {
+	mutex *Mutex
+}
+
+func (l mutexLocker) Lock() {
+	if err := l.mutex.Lock(); err != nil {
+		panic(err)
+	}
+}
+
+func (l mutexLocker) Unlock() {
+	if err := l.mutex.Unlock(); err != nil {
+		panic(err)
+	}
+}
+
 // TryLock tries to lock a mutex without blocking.
 // (https://wiki.libsdl.org/SDL_TryLockMutex)
 func (mutex *Mutex) TryLock() error {
@@ -6439,6 +10269,14 @@ func AllocFormat(format uint) (*PixelFormat, error) {
 	return (*PixelFormat)(unsafe.Pointer(ret)), nil
 }
 
+// ColorAt decodes the given pixel value, which is in this format, into its
+// color components. Note that the color is not alpha-premultiplied, it
+// contains the same values as GetRGBA returns.
+func (format *PixelFormat) ColorAt(pixel uint32) color.RGBA {
+	r, g, b, a := GetRGBA(pixel, format)
+	return color.RGBA{R: r, G: g, B: b, A: a}
+}
+
 // Free frees the PixelFormat structure allocated by AllocFormat().
 // (https://wiki.libsdl.org/SDL_FreeFormat)
 func (format *PixelFormat) Free() {
@@ -6458,6 +10296,151 @@ func (format *PixelFormat) SetPalette(palette *Palette) error {
 	return nil
 }
 
+// PixelFormatEnum is one of the PIXELFORMAT values. It decodes the
+// information that SDL packs into these values, like the SDL_PIXELTYPE and
+// SDL_ISPIXELFORMAT_* macros do.
+// (https://wiki.libsdl.org/SDL_PixelFormatEnum)
+type PixelFormatEnum uint32
+
+// Flag returns the pixel flag, it is 1 for all non-FourCC formats.
+func (f PixelFormatEnum) Flag() int {
+	return int((f >> 28) & 0x0F)
+}
+
+// Type returns one of the PIXELTYPE values.
+func (f PixelFormatEnum) Type() int {
+	return int((f >> 24) & 0x0F)
+}
+
+// Order returns one of the BITMAPORDER, PACKEDORDER or ARRAYORDER values,
+// depending on the Type of the format.
+func (f PixelFormatEnum) Order() int {
+	return int((f >> 20) & 0x0F)
+}
+
+// Layout returns one of the PACKEDLAYOUT values.
+func (f PixelFormatEnum) Layout() int {
+	return int((f >> 16) & 0x0F)
+}
+
+// BitsPerPixel returns the number of significant bits in a pixel.
+func (f PixelFormatEnum) BitsPerPixel() int {
+	return int((f >> 8) & 0xFF)
+}
+
+// BytesPerPixel returns the number of bytes required to hold a pixel.
+func (f PixelFormatEnum) BytesPerPixel() int {
+	if f.IsFourCC() {
+		if f == PIXELFORMAT_YUY2 ||
+			f == PIXELFORMAT_UYVY ||
+			f == PIXELFORMAT_YVYU {
+			return 2
+		}
+		return 1
+	}
+	return int(f & 0xFF)
+}
+
+// IsFourCC reports whether the format is a FourCC code, e.g. one of the YUV
+// formats.
+func (f PixelFormatEnum) IsFourCC() bool {
+	return f != 0 && f.Flag() != 1
+}
+
+// IsIndexed reports whether pixels of the format are indices into a palette.
+func (f PixelFormatEnum) IsIndexed() bool {
+	if f.IsFourCC() {
+		return false
+	}
+	t := f.Type()
+	return t == PIXELTYPE_INDEX1 || t == PIXELTYPE_INDEX4 || t == PIXELTYPE_INDEX8
+}
+
+// IsPacked reports whether the color components of a pixel are packed into a
+// single 8, 16 or 32 bit value.
+func (f PixelFormatEnum) IsPacked() bool {
+	if f.IsFourCC() {
+		return false
+	}
+	t := f.Type()
+	return t == PIXELTYPE_PACKED8 || t == PIXELTYPE_PACKED16 || t == PIXELTYPE_PACKED32
+}
+
+// IsArray reports whether the color components of a pixel are stored as an
+// array of values.
+func (f PixelFormatEnum) IsArray() bool {
+	if f.IsFourCC() {
+		return false
+	}
+	t := f.Type()
+	return t >= PIXELTYPE_ARRAYU8 && t <= PIXELTYPE_ARRAYF32
+}
+
+// IsAlpha reports whether the format has an alpha channel.
+func (f PixelFormatEnum) IsAlpha() bool {
+	o := f.Order()
+	if f.IsPacked() {
+		return o == PACKEDORDER_ARGB || o == PACKEDORDER_RGBA ||
+			o == PACKEDORDER_ABGR || o == PACKEDORDER_BGRA
+	}
+	if f.IsArray() {
+		return o == ARRAYORDER_ARGB || o == ARRAYORDER_RGBA ||
+			o == ARRAYORDER_ABGR || o == ARRAYORDER_BGRA
+	}
+	return false
+}
+
+// String returns the name of the PIXELFORMAT constant, e.g.
+// "PIXELFORMAT_RGBA8888". Unknown values are printed in hexadecimal.
+func (f PixelFormatEnum) String() string {
+	if name, ok := pixelFormatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("PixelFormatEnum(0x%08X)", uint32(f))
+}
+
+var pixelFormatNames = map[PixelFormatEnum]string{
+	PIXELFORMAT_UNKNOWN:      "PIXELFORMAT_UNKNOWN",
+	PIXELFORMAT_INDEX1LSB:    "PIXELFORMAT_INDEX1LSB",
+	PIXELFORMAT_INDEX1MSB:    "PIXELFORMAT_INDEX1MSB",
+	PIXELFORMAT_INDEX4LSB:    "PIXELFORMAT_INDEX4LSB",
+	PIXELFORMAT_INDEX4MSB:    "PIXELFORMAT_INDEX4MSB",
+	PIXELFORMAT_INDEX8:       "PIXELFORMAT_INDEX8",
+	PIXELFORMAT_RGB332:       "PIXELFORMAT_RGB332",
+	PIXELFORMAT_RGB444:       "PIXELFORMAT_RGB444",
+	PIXELFORMAT_RGB555:       "PIXELFORMAT_RGB555",
+	PIXELFORMAT_BGR555:       "PIXELFORMAT_BGR555",
+	PIXELFORMAT_ARGB4444:     "PIXELFORMAT_ARGB4444",
+	PIXELFORMAT_RGBA4444:     "PIXELFORMAT_RGBA4444",
+	PIXELFORMAT_ABGR4444:     "PIXELFORMAT_ABGR4444",
+	PIXELFORMAT_BGRA4444:     "PIXELFORMAT_BGRA4444",
+	PIXELFORMAT_ARGB1555:     "PIXELFORMAT_ARGB1555",
+	PIXELFORMAT_RGBA5551:     "PIXELFORMAT_RGBA5551",
+	PIXELFORMAT_ABGR1555:     "PIXELFORMAT_ABGR1555",
+	PIXELFORMAT_BGRA5551:     "PIXELFORMAT_BGRA5551",
+	PIXELFORMAT_RGB565:       "PIXELFORMAT_RGB565",
+	PIXELFORMAT_BGR565:       "PIXELFORMAT_BGR565",
+	PIXELFORMAT_RGB24:        "PIXELFORMAT_RGB24",
+	PIXELFORMAT_BGR24:        "PIXELFORMAT_BGR24",
+	PIXELFORMAT_RGB888:       "PIXELFORMAT_RGB888",
+	PIXELFORMAT_RGBX8888:     "PIXELFORMAT_RGBX8888",
+	PIXELFORMAT_BGR888:       "PIXELFORMAT_BGR888",
+	PIXELFORMAT_BGRX8888:     "PIXELFORMAT_BGRX8888",
+	PIXELFORMAT_ARGB8888:     "PIXELFORMAT_ARGB8888",
+	PIXELFORMAT_RGBA8888:     "PIXELFORMAT_RGBA8888",
+	PIXELFORMAT_ABGR8888:     "PIXELFORMAT_ABGR8888",
+	PIXELFORMAT_BGRA8888:     "PIXELFORMAT_BGRA8888",
+	PIXELFORMAT_ARGB2101010:  "PIXELFORMAT_ARGB2101010",
+	PIXELFORMAT_YV12:         "PIXELFORMAT_YV12",
+	PIXELFORMAT_IYUV:         "PIXELFORMAT_IYUV",
+	PIXELFORMAT_YUY2:         "PIXELFORMAT_YUY2",
+	PIXELFORMAT_UYVY:         "PIXELFORMAT_UYVY",
+	PIXELFORMAT_YVYU:         "PIXELFORMAT_YVYU",
+	PIXELFORMAT_NV12:         "PIXELFORMAT_NV12",
+	PIXELFORMAT_NV21:         "PIXELFORMAT_NV21",
+	PIXELFORMAT_EXTERNAL_OES: "PIXELFORMAT_EXTERNAL_OES",
+}
+
 // Point defines a two dimensional point.
 // (https://wiki.libsdl.org/SDL_Point)
 type Point struct {
@@ -6479,6 +10462,88 @@ func (p *Point) InRect(r *Rect) bool {
 // (https://wiki.libsdl.org/SDL_PowerState)
 type PowerState uint32
 
+// Profiler collects the times spent in named sections of code, e.g. to show
+// them in a debug overlay:
+//
+// 	var profiler sdl.Profiler
+// 	for running {
+// 		endUpdate := profiler.Scope("update")
+// 		update()
+// 		endUpdate()
+// 		endRender := profiler.Scope("render")
+// 		render()
+// 		endRender()
+// 	}
+//
+// The zero value is ready to use. A Profiler is safe for concurrent use.
+type Profiler struct {
+	mu       sync.Mutex
+	sections map[string]*ProfileResult
+}
+
+// ProfileResult contains the times measured for one section of code.
+type ProfileResult struct {
+	Name  string
+	Count int           // number of times the section ran
+	Total time.Duration // sum of all times
+	Max   time.Duration // longest time
+}
+
+// Average returns the average time of the section.
+func (r ProfileResult) Average() time.Duration {
+	if r.Count == 0 {
+		return 0
+	}
+	return r.Total / time.Duration(r.Count)
+}
+
+// Reset removes all measurements.
+func (p *Profiler) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sections = nil
+}
+
+// Results returns the measurements of all sections, the section with the
+// largest total time comes first.
+func (p *Profiler) Results() []ProfileResult {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	results := make([]ProfileResult, 0, len(p.sections))
+	for _, r := range p.sections {
+		results = append(results, *r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Total != results[j].Total {
+			return results[i].Total > results[j].Total
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// Scope starts timing the named section. Call the returned function at the
+// end of the section.
+func (p *Profiler) Scope(name string) (end func()) {
+	return Scope(func(elapsed time.Duration) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.sections == nil {
+			p.sections = make(map[string]*ProfileResult)
+		}
+		r, ok := p.sections[name]
+		if !ok {
+			r = &ProfileResult{Name: name}
+			p.sections[name] = r
+		}
+		r.Count++
+		r.Total += elapsed
+		if elapsed > r.Max {
+			r.Max = elapsed
+		}
+	})
+}
+
 // QuitEvent contains the "quit requested" event.
 // (https://wiki.libsdl.org/SDL_QuitEvent)
 type QuitEvent struct {
@@ -6497,6 +10562,13 @@ func (e *QuitEvent) GetType() uint32 {
 }
 
 // RWops provides an abstract interface to stream I/O. Applications can generally ignore the specifics of this structure's internals and treat them as opaque pointers. The details are important to lower-level code that might need to implement one of these, however.
+//
+// *RWops implements io.ReadWriteSeeker and io.Closer, the RW_SEEK_* constants
+// have the same values as io.SeekStart, io.SeekCurrent and io.SeekEnd.
+//
+// Binary formats can be parsed and written with the endian aware methods
+// ReadU8, ReadBE16, ReadLE16, ReadBE32, ReadLE32, ReadBE64 and ReadLE64 and
+// their Write counterparts, like SDL_ReadBE16 and friends in C code.
 // (https://wiki.libsdl.org/SDL_RWops)
 type RWops struct {
 	size  uintptr
@@ -6514,6 +10586,31 @@ func AllocRW() *RWops {
 	return (*RWops)(unsafe.Pointer(ret))
 }
 
+// RWFromBytes prepares a read-write memory stream on b without copying it.
+// Unlike with RWFromMem, the RWops keeps b referenced so the garbage collector
+// does not free it while SDL still uses the memory, even if the caller drops
+// all other references to it. The reference is released in Close.
+//
+// Functions like LoadBMPRW can close the RWops themselves if freeSrc is true,
+// bypassing Close. In that case b stays referenced for the rest of the program,
+// so prefer passing false and calling Close.
+func RWFromBytes(b []byte) (*RWops, error) {
+	rw, err := RWFromMem(b)
+	if err != nil {
+		return nil, err
+	}
+	rwBytesMutex.Lock()
+	rwBytes[rw] = b
+	rwBytesMutex.Unlock()
+	return rw, nil
+}
+
+var (
+	// rwBytes keeps the memory of RWops created with RWFromBytes alive.
+	rwBytes      = make(map[*RWops][]byte)
+	rwBytesMutex sync.Mutex
+)
+
 // RWFromFile creates a new RWops structure for reading from and/or writing to a named file.
 // (https://wiki.libsdl.org/SDL_RWFromFile)
 func RWFromFile(file, mode string) *RWops {
@@ -6555,6 +10652,9 @@ func (rwops *RWops) Close() error {
 		0,
 		0,
 	)
+	rwBytesMutex.Lock()
+	delete(rwBytes, rwops)
+	rwBytesMutex.Unlock()
 	if ret != 0 {
 		return GetError()
 	}
@@ -6586,10 +10686,18 @@ func (src *RWops) LoadFileRW(freesrc bool) (data []byte, size int) {
 	return
 }
 
-// Read reads from a data source.
+// Read reads from a data source. As required by io.Reader, it returns io.EOF
+// when no more data can be read.
 // (https://wiki.libsdl.org/SDL_RWread)
 func (rwops *RWops) Read(buf []byte) (n int, err error) {
-	return rwops.Read2(buf, 1, uint(len(buf)))
+	// SDL_RWread returns 0 both at the end of the data and on errors, clear
+	// the error to tell the two apart.
+	ClearError()
+	n, err = rwops.Read2(buf, 1, uint(len(buf)))
+	if n == 0 && len(buf) > 0 && err == nil {
+		err = io.EOF
+	}
+	return
 }
 
 // Read2 reads from a data source (native).
@@ -6674,10 +10782,17 @@ func (rwops *RWops) Tell() (int64, error) {
 	return rwops.Seek(0, RW_SEEK_CUR)
 }
 
-// Write writes to the RWops data stream.
+// Write writes to the RWops data stream. As required by io.Writer, it returns
+// an error if not all of buf was written, io.ErrShortWrite if SDL does not
+// report one, e.g. when a memory stream is full.
 // (https://wiki.libsdl.org/SDL_RWwrite)
 func (rwops *RWops) Write(buf []byte) (n int, err error) {
-	return rwops.Write2(buf, 1, uint(len(buf)))
+	ClearError()
+	n, err = rwops.Write2(buf, 1, uint(len(buf)))
+	if n < len(buf) && err == nil {
+		err = io.ErrShortWrite
+	}
+	return
 }
 
 // Write2 writes to the RWops data stream (native).
@@ -6869,6 +10984,11 @@ func GetDisplayUsableBounds(displayIndex int) (rect Rect, err error) {
 	return
 }
 
+// Contains reports whether the point resides inside the rectangle.
+func (a *Rect) Contains(p Point) bool {
+	return a != nil && p.InRect(a)
+}
+
 // Empty reports whether a rectangle has no area.
 // (https://wiki.libsdl.org/SDL_RectEmpty)
 func (a *Rect) Empty() bool {
@@ -7172,6 +11292,39 @@ func CreateRenderer(window *Window, index int, flags uint32) (*Renderer, error)
 	return (*Renderer)(unsafe.Pointer(ret)), nil
 }
 
+// CreateRendererWithOptions returns a new 2D rendering context for a window,
+// using the render driver with the name given in the options, e.g.
+//
+//	sdl.CreateRendererWithOptions(window, sdl.RendererOptions{
+//		Driver:        "direct3d11",
+//		VSync:         true,
+//		TargetTexture: true,
+//	})
+//
+// It returns an error if there is no driver with that name.
+func CreateRendererWithOptions(window *Window, options RendererOptions) (*Renderer, error) {
+	index := -1
+	if options.Driver != "" {
+		var err error
+		index, err = renderDriverIndex(options.Driver)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return CreateRenderer(window, index, options.flags())
+}
+
+// renderDriverIndex returns the index of the render driver with the name,
+// ignoring case.
+func renderDriverIndex(name string) (int, error) {
+	for i, info := range RenderDrivers() {
+		if strings.EqualFold(info.Name, name) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("sdl: unknown render driver %q", name)
+}
+
 // CreateSoftwareRenderer returns a new 2D software rendering context for a surface.
 // (https://wiki.libsdl.org/SDL_CreateSoftwareRenderer)
 func CreateSoftwareRenderer(surface *Surface) (*Renderer, error) {
@@ -7182,6 +11335,24 @@ func CreateSoftwareRenderer(surface *Surface) (*Renderer, error) {
 	return (*Renderer)(unsafe.Pointer(ret)), nil
 }
 
+// NewSoftwareRenderer creates a 32 bit surface of the given size and a
+// software renderer that draws into it. No window or video subsystem is
+// needed, which makes it possible to render thumbnails or previews headless.
+// Use Surface.ToRGBA to get the result as an image. Destroy the renderer
+// before freeing the surface.
+func NewSoftwareRenderer(width, height int32) (*Renderer, *Surface, error) {
+	surface, err := CreateRGBSurfaceWithFormat(0, width, height, 32, PIXELFORMAT_ABGR8888)
+	if err != nil {
+		return nil, nil, err
+	}
+	renderer, err := CreateSoftwareRenderer(surface)
+	if err != nil {
+		surface.Free()
+		return nil, nil, err
+	}
+	return renderer, surface, nil
+}
+
 // Clear clears the current rendering target with the drawing color.
 // (https://wiki.libsdl.org/SDL_RenderClear)
 func (renderer *Renderer) Clear() error {
@@ -7203,7 +11374,8 @@ func (renderer *Renderer) Copy(texture *Texture, src, dst *Rect) error {
 
 // CopyF copies a portion of the texture to the current rendering target.
 // TODO: (https://wiki.libsdl.org/SDL_RenderCopyF)
-func (renderer *Renderer) CopyF(texture *Texture, src, dst *FRect) error {
+// The source rectangle is in texture pixels, so src is a Rect, like in Copy.
+func (renderer *Renderer) CopyF(texture *Texture, src *Rect, dst *FRect) error {
 	ret, _, _ := renderCopyF.Call(
 		uintptr(unsafe.Pointer(renderer)),
 		uintptr(unsafe.Pointer(texture)),
@@ -7247,6 +11419,9 @@ func (renderer *Renderer) CreateTextureFromSurface(surface *Surface) (*Texture,
 func (renderer *Renderer) Destroy() error {
 	lastErr := GetError()
 	ClearError()
+	delete(rendererStates, renderer)
+	delete(drawColorStacks, renderer)
+	delete(targetStacks, renderer)
 	destroyRenderer.Call(uintptr(unsafe.Pointer(renderer)))
 	err := GetError()
 	if err != nil {
@@ -7256,7 +11431,62 @@ func (renderer *Renderer) Destroy() error {
 	return nil
 }
 
-// DrawLine draws a line on the current rendering target.
+// DrawCircle draws the outline of a circle with the draw color, using the
+// midpoint circle algorithm. All points are drawn with a single call into SDL.
+func (renderer *Renderer) DrawCircle(centerX, centerY, radius int32) error {
+	if radius < 0 {
+		return nil
+	}
+	octant := circleOctant(radius)
+	points := make([]Point, 0, 8*len(octant))
+	for _, p := range octant {
+		mirrored := [8]Point{
+			{X: centerX + p.X, Y: centerY + p.Y},
+			{X: centerX - p.X, Y: centerY + p.Y},
+			{X: centerX + p.X, Y: centerY - p.Y},
+			{X: centerX - p.X, Y: centerY - p.Y},
+			{X: centerX + p.Y, Y: centerY + p.X},
+			{X: centerX - p.Y, Y: centerY + p.X},
+			{X: centerX + p.Y, Y: centerY - p.X},
+			{X: centerX - p.Y, Y: centerY - p.X},
+		}
+		// Points on the axes and on the diagonals are their own mirror
+		// images. Draw them only once so translucent colors are not blended
+		// twice.
+	nextPoint:
+		for i, q := range mirrored {
+			for _, drawn := range mirrored[:i] {
+				if q == drawn {
+					continue nextPoint
+				}
+			}
+			points = append(points, q)
+		}
+	}
+	return renderer.DrawPoints(points)
+}
+
+// circleOctant returns the points of the midpoint circle around 0,0 from the
+// positive x-axis up to the diagonal, i.e. with x >= y >= 0.
+func circleOctant(radius int32) []Point {
+	var points []Point
+	x, y := radius, int32(0)
+	decision := 1 - radius
+	for x >= y {
+		points = append(points, Point{X: x, Y: y})
+		y++
+		if decision < 0 {
+			decision += 2*y + 1
+		} else {
+			x--
+			decision += 2*(y-x) + 1
+		}
+	}
+	return points
+}
+
+// DrawLine draws a line on the current rendering target. For many connected
+// lines, DrawLines needs only a single call into SDL.
 // (https://wiki.libsdl.org/SDL_RenderDrawLine)
 func (renderer *Renderer) DrawLine(x1, y1, x2, y2 int32) error {
 	ret, _, _ := renderDrawLine.Call(
@@ -7274,10 +11504,10 @@ func (renderer *Renderer) DrawLine(x1, y1, x2, y2 int32) error {
 func (renderer *Renderer) DrawLineF(x1, y1, x2, y2 float32) error {
 	ret, _, _ := renderDrawLineF.Call(
 		uintptr(unsafe.Pointer(renderer)),
-		uintptr(x1),
-		uintptr(y1),
-		uintptr(x2),
-		uintptr(y2),
+		uintptr(math.Float32bits(x1)),
+		uintptr(math.Float32bits(y1)),
+		uintptr(math.Float32bits(x2)),
+		uintptr(math.Float32bits(y2)),
 	)
 	return errorFromInt(int(ret))
 }
@@ -7310,7 +11540,76 @@ func (renderer *Renderer) DrawLinesF(points []FPoint) error {
 	return errorFromInt(int(ret))
 }
 
-// DrawPoint draws a point on the current rendering target.
+// DrawNinePatch draws the src part of the texture, or the whole texture if src
+// is nil, stretched to dst without distorting its borders, e.g. for UI panels
+// and buttons of any size. The source is divided into nine parts by the
+// borders: the corners are drawn unscaled, the top and bottom edges are
+// stretched horizontally, the left and right edges vertically and the center
+// in both directions. If dst is smaller than the borders, they are shrunk
+// proportionally. Like in Copy, a nil dst fills the whole viewport, which is
+// the whole output unless a viewport or logical size was set.
+func (renderer *Renderer) DrawNinePatch(texture *Texture, src *Rect, borders Borders, dst *Rect) error {
+	if dst == nil {
+		viewport := renderer.GetViewport()
+		dst = &Rect{W: viewport.W, H: viewport.H}
+	}
+	var s Rect
+	if src != nil {
+		s = *src
+	} else {
+		_, _, w, h, err := texture.Query()
+		if err != nil {
+			return err
+		}
+		s = Rect{W: w, H: h}
+	}
+	// The source borders are kept within the source so the center does not
+	// get a negative size.
+	srcLeft, srcRight := fitBorders(borders.Left, borders.Right, s.W)
+	srcTop, srcBottom := fitBorders(borders.Top, borders.Bottom, s.H)
+	dstLeft, dstRight := fitBorders(srcLeft, srcRight, dst.W)
+	dstTop, dstBottom := fitBorders(srcTop, srcBottom, dst.H)
+
+	srcXs := [4]int32{s.X, s.X + srcLeft, s.X + s.W - srcRight, s.X + s.W}
+	srcYs := [4]int32{s.Y, s.Y + srcTop, s.Y + s.H - srcBottom, s.Y + s.H}
+	dstXs := [4]int32{dst.X, dst.X + dstLeft, dst.X + dst.W - dstRight, dst.X + dst.W}
+	dstYs := [4]int32{dst.Y, dst.Y + dstTop, dst.Y + dst.H - dstBottom, dst.Y + dst.H}
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 3; x++ {
+			from := Rect{X: srcXs[x], Y: srcYs[y], W: srcXs[x+1] - srcXs[x], H: srcYs[y+1] - srcYs[y]}
+			to := Rect{X: dstXs[x], Y: dstYs[y], W: dstXs[x+1] - dstXs[x], H: dstYs[y+1] - dstYs[y]}
+			if from.Empty() || to.Empty() {
+				continue
+			}
+			if err := renderer.Copy(texture, &from, &to); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fitBorders shrinks the two opposite borders a and b proportionally so they
+// fit into size.
+func fitBorders(a, b, size int32) (int32, int32) {
+	if a < 0 {
+		a = 0
+	}
+	if b < 0 {
+		b = 0
+	}
+	if size <= 0 {
+		return 0, 0
+	}
+	if a+b <= size {
+		return a, b
+	}
+	fitA := int32(int64(a) * int64(size) / int64(a+b))
+	return fitA, size - fitA
+}
+
+// DrawPoint draws a point on the current rendering target. DrawPoints draws
+// a whole slice of points with a single call into SDL.
 // (https://wiki.libsdl.org/SDL_RenderDrawPoint)
 func (renderer *Renderer) DrawPoint(x, y int32) error {
 	ret, _, _ := renderDrawPoint.Call(
@@ -7326,8 +11625,8 @@ func (renderer *Renderer) DrawPoint(x, y int32) error {
 func (renderer *Renderer) DrawPointF(x, y float32) error {
 	ret, _, _ := renderDrawPointF.Call(
 		uintptr(unsafe.Pointer(renderer)),
-		uintptr(x),
-		uintptr(y),
+		uintptr(math.Float32bits(x)),
+		uintptr(math.Float32bits(y)),
 	)
 	return errorFromInt(int(ret))
 }
@@ -7360,6 +11659,19 @@ func (renderer *Renderer) DrawPointsF(points []FPoint) error {
 	return errorFromInt(int(ret))
 }
 
+// DrawPolygon draws the closed outline through the points with the draw color,
+// connecting the last point back to the first one, with a single call into
+// SDL.
+func (renderer *Renderer) DrawPolygon(points []Point) error {
+	if len(points) == 0 {
+		return nil
+	}
+	closed := make([]Point, len(points)+1)
+	copy(closed, points)
+	closed[len(points)] = points[0]
+	return renderer.DrawLines(closed)
+}
+
 // DrawRect draws a rectangle on the current rendering target.
 // (https://wiki.libsdl.org/SDL_RenderDrawRect)
 func (renderer *Renderer) DrawRect(rect *Rect) error {
@@ -7408,7 +11720,38 @@ func (renderer *Renderer) DrawRectsF(rects []FRect) error {
 	return errorFromInt(int(ret))
 }
 
+// FillCircle fills a circle with the draw color. It covers the same pixels as
+// DrawCircle and its inside. The circle is drawn as horizontal lines with a
+// single call into SDL.
+func (renderer *Renderer) FillCircle(centerX, centerY, radius int32) error {
+	if radius < 0 {
+		return nil
+	}
+	// halfWidths[dy] is the horizontal distance from the center to the edge
+	// of the circle at dy rows above or below the center.
+	halfWidths := make([]int32, radius+1)
+	for _, p := range circleOctant(radius) {
+		if p.X > halfWidths[p.Y] {
+			halfWidths[p.Y] = p.X
+		}
+		if p.Y > halfWidths[p.X] {
+			halfWidths[p.X] = p.Y
+		}
+	}
+	rects := make([]Rect, 0, 2*len(halfWidths))
+	for dy, half := range halfWidths {
+		row := Rect{X: centerX - half, Y: centerY + int32(dy), W: 2*half + 1, H: 1}
+		rects = append(rects, row)
+		if dy > 0 {
+			row.Y = centerY - int32(dy)
+			rects = append(rects, row)
+		}
+	}
+	return renderer.FillRects(rects)
+}
+
 // FillRect fills a rectangle on the current rendering target with the drawing color.
+// Use FillRects to fill many rectangles at once.
 // (https://wiki.libsdl.org/SDL_RenderFillRect)
 func (renderer *Renderer) FillRect(rect *Rect) error {
 	ret, _, _ := renderFillRect.Call(
@@ -7463,6 +11806,33 @@ func (renderer *Renderer) Flush() error {
 	return errorFromInt(int(ret))
 }
 
+// Geometry renders a list of triangles, optionally using a texture and
+// indices into the vertex array. If indices is empty, the vertices are drawn
+// in order, three at a time.
+// This function requires SDL 2.0.18 or newer.
+// (https://wiki.libsdl.org/SDL_RenderGeometry)
+func (renderer *Renderer) Geometry(texture *Texture, vertices []Vertex, indices []int32) error {
+	if err := renderGeometry.Find(); err != nil {
+		return err
+	}
+	if len(vertices) == 0 {
+		return nil
+	}
+	var indexPtr unsafe.Pointer
+	if len(indices) > 0 {
+		indexPtr = unsafe.Pointer(&indices[0])
+	}
+	ret, _, _ := renderGeometry.Call(
+		uintptr(unsafe.Pointer(renderer)),
+		uintptr(unsafe.Pointer(texture)),
+		uintptr(unsafe.Pointer(&vertices[0])),
+		uintptr(len(vertices)),
+		uintptr(indexPtr),
+		uintptr(len(indices)),
+	)
+	return errorFromInt(int(ret))
+}
+
 // GetClipRect returns the clip rectangle for the current target.
 // (https://wiki.libsdl.org/SDL_RenderGetClipRect)
 func (renderer *Renderer) GetClipRect() (rect Rect) {
@@ -7473,6 +11843,12 @@ func (renderer *Renderer) GetClipRect() (rect Rect) {
 	return
 }
 
+// GetColor returns the color used for drawing operations.
+func (renderer *Renderer) GetColor() (Color, error) {
+	r, g, b, a, err := renderer.GetDrawColor()
+	return Color{R: r, G: g, B: b, A: a}, err
+}
+
 // GetDrawBlendMode returns the blend mode used for drawing operations.
 // (https://wiki.libsdl.org/SDL_GetRenderDrawBlendMode)
 func (renderer *Renderer) GetDrawBlendMode(bm *BlendMode) error {
@@ -7500,15 +11876,21 @@ func (renderer *Renderer) GetDrawColor() (r, g, b, a uint8, err error) {
 // GetInfo returns information about a rendering context.
 // (https://wiki.libsdl.org/SDL_GetRendererInfo)
 func (renderer *Renderer) GetInfo() (RendererInfo, error) {
-	var info RendererInfo
+	var cInfo struct {
+		name uintptr
+		RendererInfoData
+	}
 	ret, _, _ := getRendererInfo.Call(
 		uintptr(unsafe.Pointer(renderer)),
-		uintptr(unsafe.Pointer(&info)),
+		uintptr(unsafe.Pointer(&cInfo)),
 	)
 	if ret != 0 {
-		return info, GetError()
+		return RendererInfo{}, GetError()
 	}
-	return info, nil
+	return RendererInfo{
+		Name:             sdlToGoString(cInfo.name),
+		RendererInfoData: cInfo.RendererInfoData,
+	}, nil
 }
 
 // GetIntegerScale reports whether integer scales are forced for
@@ -7594,12 +11976,157 @@ func (renderer *Renderer) GetViewport() (rect Rect) {
 	return
 }
 
+// IsClipEnabled returns whether clipping is enabled on the renderer.
+// (https://wiki.libsdl.org/SDL_RenderIsClipEnabled)
+func (renderer *Renderer) IsClipEnabled() bool {
+	ret, _, _ := renderIsClipEnabled.Call(uintptr(unsafe.Pointer(renderer)))
+	return ret != 0
+}
+
+// rendererState is the draw state saved by Renderer.PushState.
+type rendererState struct {
+	target     *Texture
+	scaleX     float32
+	scaleY     float32
+	viewport   Rect
+	clipRect   Rect
+	r, g, b, a uint8
+	blendMode  BlendMode
+}
+
+// rendererStates holds the PushState stacks of all renderers.
+var rendererStates = make(map[*Renderer][]rendererState)
+
+// drawColorStacks holds the PushDrawColor stacks of all renderers.
+var drawColorStacks = make(map[*Renderer][]Color)
+
+// targetStacks holds the PushTarget stacks of all renderers.
+var targetStacks = make(map[*Renderer][]*Texture)
+
+// PopDrawColor restores the draw color that was active before the last call to
+// PushDrawColor.
+func (renderer *Renderer) PopDrawColor() error {
+	stack := drawColorStacks[renderer]
+	if len(stack) == 0 {
+		return errors.New("sdl: Renderer.PopDrawColor called without a matching PushDrawColor")
+	}
+	c := stack[len(stack)-1]
+	if len(stack) == 1 {
+		delete(drawColorStacks, renderer)
+	} else {
+		drawColorStacks[renderer] = stack[:len(stack)-1]
+	}
+	return renderer.SetColor(c)
+}
+
+// PopState restores the draw state that was saved by the last call to
+// PushState.
+func (renderer *Renderer) PopState() error {
+	stack := rendererStates[renderer]
+	if len(stack) == 0 {
+		return errors.New("sdl: Renderer.PopState called without a matching PushState")
+	}
+	state := stack[len(stack)-1]
+	if len(stack) == 1 {
+		delete(rendererStates, renderer)
+	} else {
+		rendererStates[renderer] = stack[:len(stack)-1]
+	}
+
+	// Viewport, clip rect and scale belong to the render target so it has to
+	// be restored first. The scale is applied to viewport and clip rect so it
+	// comes before them.
+	if err := renderer.SetRenderTarget(state.target); err != nil {
+		return err
+	}
+	if err := renderer.SetScale(state.scaleX, state.scaleY); err != nil {
+		return err
+	}
+	if err := renderer.SetViewport(&state.viewport); err != nil {
+		return err
+	}
+	clip := &state.clipRect
+	if clip.Empty() {
+		clip = nil
+	}
+	if err := renderer.SetClipRect(clip); err != nil {
+		return err
+	}
+	if err := renderer.SetDrawColor(state.r, state.g, state.b, state.a); err != nil {
+		return err
+	}
+	return renderer.SetDrawBlendMode(state.blendMode)
+}
+
+// PopTarget restores the render target that was active before the last call
+// to PushTarget.
+func (renderer *Renderer) PopTarget() error {
+	stack := targetStacks[renderer]
+	if len(stack) == 0 {
+		return errors.New("sdl: Renderer.PopTarget called without a matching PushTarget")
+	}
+	target := stack[len(stack)-1]
+	if len(stack) == 1 {
+		delete(targetStacks, renderer)
+	} else {
+		targetStacks[renderer] = stack[:len(stack)-1]
+	}
+	return renderer.SetRenderTarget(target)
+}
+
 // Present updates the screen with any rendering performed since the previous call.
 // (https://wiki.libsdl.org/SDL_RenderPresent)
 func (renderer *Renderer) Present() {
 	renderPresent.Call(uintptr(unsafe.Pointer(renderer)))
 }
 
+// PushDrawColor saves the current draw color and then sets it to c. Call
+// PopDrawColor to go back to the saved color. Calls can be nested.
+func (renderer *Renderer) PushDrawColor(c color.Color) error {
+	old, err := renderer.GetColor()
+	if err != nil {
+		return err
+	}
+	if err := renderer.SetColor(c); err != nil {
+		return err
+	}
+	drawColorStacks[renderer] = append(drawColorStacks[renderer], old)
+	return nil
+}
+
+// PushTarget saves the current render target and then renders to the texture,
+// which must have been created with TEXTUREACCESS_TARGET. A nil texture
+// renders to the window. Call PopTarget to go back to the saved target. Calls
+// can be nested.
+func (renderer *Renderer) PushTarget(texture *Texture) error {
+	old := renderer.GetRenderTarget()
+	if err := renderer.SetRenderTarget(texture); err != nil {
+		return err
+	}
+	targetStacks[renderer] = append(targetStacks[renderer], old)
+	return nil
+}
+
+// PushState saves the current draw color, blend mode, clip rect, viewport,
+// scale and render target. Call PopState to restore them. Calls can be nested.
+func (renderer *Renderer) PushState() error {
+	var state rendererState
+	var err error
+	state.r, state.g, state.b, state.a, err = renderer.GetDrawColor()
+	if err != nil {
+		return err
+	}
+	if err := renderer.GetDrawBlendMode(&state.blendMode); err != nil {
+		return err
+	}
+	state.target = renderer.GetRenderTarget()
+	state.scaleX, state.scaleY = renderer.GetScale()
+	state.viewport = renderer.GetViewport()
+	state.clipRect = renderer.GetClipRect()
+	rendererStates[renderer] = append(rendererStates[renderer], state)
+	return nil
+}
+
 // ReadPixels reads pixels from the current rendering target.
 // (https://wiki.libsdl.org/SDL_RenderReadPixels)
 func (renderer *Renderer) ReadPixels(rect *Rect, format uint32, pixels unsafe.Pointer, pitch int) error {
@@ -7631,6 +12158,12 @@ func (renderer *Renderer) SetClipRect(rect *Rect) error {
 	return errorFromInt(int(ret))
 }
 
+// SetColor sets the color used for drawing operations to any color.Color.
+func (renderer *Renderer) SetColor(c color.Color) error {
+	sdlColor := toColor(c)
+	return renderer.SetDrawColor(sdlColor.R, sdlColor.G, sdlColor.B, sdlColor.A)
+}
+
 // SetDrawBlendMode sets the blend mode used for drawing operations (Fill and Line).
 // (https://wiki.libsdl.org/SDL_SetRenderDrawBlendMode)
 func (renderer *Renderer) SetDrawBlendMode(bm BlendMode) error {
@@ -7677,6 +12210,33 @@ func (renderer *Renderer) SetIntegerScale(v bool) error {
 	return errorFromInt(int(ret))
 }
 
+// SetLetterboxedLogicalSize sets the scale and viewport so that drawing at
+// logical coordinates from 0,0 to w,h fills the largest area of the output with
+// the same aspect ratio, centered with black bars at the sides. Unlike
+// SetLogicalSize it returns the mapping, which converts mouse coordinates to
+// logical coordinates. Call it again when the output size changes.
+func (renderer *Renderer) SetLetterboxedLogicalSize(w, h int32) (Letterbox, error) {
+	outputW, outputH, err := renderer.GetOutputSize()
+	if err != nil {
+		return Letterbox{}, err
+	}
+	box := NewLetterbox(outputW, outputH, w, h)
+	if box.Scale == 0 {
+		return box, fmt.Errorf("sdl: invalid logical size %dx%d for output size %dx%d", w, h, outputW, outputH)
+	}
+	if err := renderer.SetScale(box.Scale, box.Scale); err != nil {
+		return box, err
+	}
+	// The viewport is given in scaled coordinates.
+	viewport := Rect{
+		X: int32(math.Round(float64(float32(box.Viewport.X) / box.Scale))),
+		Y: int32(math.Round(float64(float32(box.Viewport.Y) / box.Scale))),
+		W: w,
+		H: h,
+	}
+	return box, renderer.SetViewport(&viewport)
+}
+
 // SetLogicalSize sets a device independent resolution for rendering.
 // (https://wiki.libsdl.org/SDL_RenderSetLogicalSize)
 func (renderer *Renderer) SetLogicalSize(w, h int32) error {
@@ -7719,6 +12279,22 @@ func (renderer *Renderer) SetViewport(rect *Rect) error {
 	return errorFromInt(int(ret))
 }
 
+// WithTarget renders to the texture while fn runs and then restores the
+// previous render target, even if fn panics. It returns an error if the
+// texture cannot be set as the render target, in which case fn is not called.
+func (renderer *Renderer) WithTarget(texture *Texture, fn func()) (err error) {
+	if err := renderer.PushTarget(texture); err != nil {
+		return err
+	}
+	defer func() {
+		if popErr := renderer.PopTarget(); err == nil {
+			err = popErr
+		}
+	}()
+	fn()
+	return nil
+}
+
 // RendererFlip is an enumeration of flags that can be used in the flip parameter for Renderer.CopyEx().
 // (https://wiki.libsdl.org/SDL_RendererFlip)
 type RendererFlip uint32
@@ -7730,6 +12306,55 @@ type RendererInfo struct {
 	RendererInfoData
 }
 
+// FlagNames returns the names of the renderer flags that are set, e.g.
+// "accelerated" or "vsync".
+func (info RendererInfo) FlagNames() []string {
+	var names []string
+	for _, flag := range []struct {
+		mask uint32
+		name string
+	}{
+		{RENDERER_SOFTWARE, "software"},
+		{RENDERER_ACCELERATED, "accelerated"},
+		{RENDERER_PRESENTVSYNC, "vsync"},
+		{RENDERER_TARGETTEXTURE, "target texture"},
+	} {
+		if info.Flags&flag.mask != 0 {
+			names = append(names, flag.name)
+		}
+	}
+	return names
+}
+
+// FormatNames returns the names of the supported texture formats without
+// their "SDL_PIXELFORMAT_" prefix, e.g. "ARGB8888".
+func (info RendererInfo) FormatNames() []string {
+	n := int(info.NumTextureFormats)
+	if n > len(info.TextureFormats) {
+		n = len(info.TextureFormats)
+	}
+	names := make([]string, n)
+	for i := range names {
+		name := GetPixelFormatName(uint(uint32(info.TextureFormats[i])))
+		names[i] = strings.TrimPrefix(name, "SDL_PIXELFORMAT_")
+	}
+	return names
+}
+
+// String returns a single line description of the renderer's capabilities,
+// e.g.
+//
+//	direct3d (accelerated, vsync, target texture), max texture 16384x16384, formats ARGB8888, YV12, IYUV
+func (info RendererInfo) String() string {
+	return fmt.Sprintf(
+		"%s (%s), max texture %dx%d, formats %s",
+		info.Name,
+		strings.Join(info.FlagNames(), ", "),
+		info.MaxTextureWidth, info.MaxTextureHeight,
+		strings.Join(info.FormatNames(), ", "),
+	)
+}
+
 // RendererInfoData contains information on the capabilities of a render driver or the current render context.
 // (https://wiki.libsdl.org/SDL_RendererInfo)
 type RendererInfoData struct {
@@ -7740,6 +12365,46 @@ type RendererInfoData struct {
 	MaxTextureHeight  int32     // the maximum texture height
 }
 
+// RendererOptions are used by CreateRendererWithOptions instead of a driver
+// index and RENDERER_* flags.
+type RendererOptions struct {
+	// Driver is the name of the render driver, e.g. "direct3d", "direct3d11",
+	// "opengl" or "software". If empty, the first driver that supports the
+	// other options is used.
+	Driver string
+	// Software requests a software fallback renderer (RENDERER_SOFTWARE).
+	Software bool
+	// Accelerated requests a renderer that uses hardware acceleration
+	// (RENDERER_ACCELERATED).
+	Accelerated bool
+	// VSync synchronizes Present with the refresh rate
+	// (RENDERER_PRESENTVSYNC).
+	VSync bool
+	// TargetTexture requests support for rendering to textures
+	// (RENDERER_TARGETTEXTURE).
+	TargetTexture bool
+}
+
+func (o RendererOptions) flags() uint32 {
+	var flags uint32
+	if o.Software {
+		flags |= RENDERER_SOFTWARE
+	}
+	if o.Accelerated {
+		flags |= RENDERER_ACCELERATED
+	}
+	if o.VSync {
+		flags |= RENDERER_PRESENTVSYNC
+	}
+	if o.TargetTexture {
+		flags |= RENDERER_TARGETTEXTURE
+	}
+	return flags
+}
+
+// ScaleQuality is a value of HINT_RENDER_SCALE_QUALITY, see Config.
+type ScaleQuality string
+
 // Scancode is an SDL keyboard scancode representation.
 // (https://wiki.libsdl.org/SDL_Scancode)
 type Scancode uint32
@@ -7777,6 +12442,34 @@ func CreateSemaphore(initialValue uint32) (*Sem, error) {
 	return (*Sem)(unsafe.Pointer(ret)), nil
 }
 
+// Chan returns a channel that receives a value every time the semaphore is
+// decremented for the receiver. This lets Go code select on a semaphore, e.g.
+// one posted by C code on an SDL thread. A background goroutine waits on the
+// semaphore until the context is done, then the channel is closed. A value
+// that was taken from the semaphore but not received is posted back.
+func (sem *Sem) Chan(ctx context.Context) <-chan struct{} {
+	c := make(chan struct{})
+	go func() {
+		defer close(c)
+		for ctx.Err() == nil {
+			ok, err := sem.TryWaitTimeout(condPollInterval)
+			if err != nil {
+				return
+			}
+			if !ok {
+				continue
+			}
+			select {
+			case c <- struct{}{}:
+			case <-ctx.Done():
+				sem.Post()
+				return
+			}
+		}
+	}()
+	return c
+}
+
 // Destroy destroys a semaphore.
 // (https://wiki.libsdl.org/SDL_DestroySemaphore)
 func (sem *Sem) Destroy() {
@@ -7803,6 +12496,30 @@ func (sem *Sem) TryWait() error {
 	return nil
 }
 
+// TryWaitTimeout waits until a semaphore has a positive value and then
+// decrements it. It returns false if the timeout passes first. The timeout is
+// rounded up to whole milliseconds.
+// (https://wiki.libsdl.org/SDL_SemWaitTimeout)
+func (sem *Sem) TryWaitTimeout(timeout time.Duration) (bool, error) {
+	ms := uint32(MUTEX_MAXWAIT - 1)
+	if timeout <= 0 {
+		ms = 0
+	} else if timeout < time.Duration(ms)*time.Millisecond {
+		ms = uint32((timeout + time.Millisecond - 1) / time.Millisecond)
+	}
+	ret, _, _ := semWaitTimeout.Call(
+		uintptr(unsafe.Pointer(sem)),
+		uintptr(ms),
+	)
+	if ret == MUTEX_TIMEDOUT {
+		return false, nil
+	}
+	if ret != 0 {
+		return false, GetError()
+	}
+	return true, nil
+}
+
 // Value returns the current value of a semaphore.
 // (https://wiki.libsdl.org/SDL_SemValue)
 func (sem *Sem) Value() uint32 {
@@ -8026,6 +12743,267 @@ func SIMDGetAlignment() int {
 	return int(ret)
 }
 
+// SpriteBatch collects texture copies and draws them with as few calls into
+// SDL as possible. Sprites are grouped by texture and, if SDL_RenderGeometry
+// is available (SDL 2.0.18), every group is drawn in a single call. With older
+// versions of SDL every sprite is drawn with Renderer.CopyEx.
+type SpriteBatch struct {
+	// SortByTexture groups the sprites by texture before drawing them. This
+	// changes the draw order of sprites with different textures, disable it if
+	// overlapping sprites must be drawn in the order they were added.
+	SortByTexture bool
+
+	renderer *Renderer
+	sprites  []batchSprite
+	vertices []Vertex
+	indices  []int32
+}
+
+type batchSprite struct {
+	texture *Texture
+	src     Rect
+	fullSrc bool
+	dst     Rect
+	angle   float64
+	flip    RendererFlip
+}
+
+// NewSpriteBatch returns an empty sprite batch that draws to the given
+// renderer. Sorting by texture is enabled.
+func NewSpriteBatch(renderer *Renderer) *SpriteBatch {
+	return &SpriteBatch{
+		SortByTexture: true,
+		renderer:      renderer,
+	}
+}
+
+// Clear removes all sprites from the batch without drawing them.
+func (batch *SpriteBatch) Clear() {
+	batch.sprites = batch.sprites[:0]
+}
+
+// Draw adds a copy of the texture to the batch. The arguments are the same as
+// for Renderer.CopyEx, the sprite is rotated by angle degrees clockwise around
+// the center of dst. A nil src means the whole texture.
+func (batch *SpriteBatch) Draw(texture *Texture, src *Rect, dst Rect, angle float64, flip RendererFlip) {
+	s := batchSprite{
+		texture: texture,
+		fullSrc: src == nil,
+		dst:     dst,
+		angle:   angle,
+		flip:    flip,
+	}
+	if src != nil {
+		s.src = *src
+	}
+	batch.sprites = append(batch.sprites, s)
+}
+
+// Flush draws all sprites in the batch and empties it, even if an error
+// occurs.
+func (batch *SpriteBatch) Flush() error {
+	defer batch.Clear()
+
+	if batch.SortByTexture {
+		sort.SliceStable(batch.sprites, func(i, j int) bool {
+			return uintptr(unsafe.Pointer(batch.sprites[i].texture)) <
+				uintptr(unsafe.Pointer(batch.sprites[j].texture))
+		})
+	}
+
+	if renderGeometry.Find() != nil {
+		return batch.flushCopyEx()
+	}
+	return batch.flushGeometry()
+}
+
+// flushCopyEx draws every sprite with its own call to Renderer.CopyEx, for
+// versions of SDL without SDL_RenderGeometry.
+func (batch *SpriteBatch) flushCopyEx() error {
+	for i := range batch.sprites {
+		s := &batch.sprites[i]
+		var src *Rect
+		if !s.fullSrc {
+			src = &s.src
+		}
+		err := batch.renderer.CopyEx(s.texture, src, &s.dst, s.angle, nil, s.flip)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flushGeometry draws each group of sprites with the same texture in a single
+// call to Renderer.Geometry.
+func (batch *SpriteBatch) flushGeometry() error {
+	for start := 0; start < len(batch.sprites); {
+		texture := batch.sprites[start].texture
+		_, _, w, h, err := texture.Query()
+		if err != nil {
+			return err
+		}
+		batch.vertices = batch.vertices[:0]
+		batch.indices = batch.indices[:0]
+		end := start
+		for end < len(batch.sprites) && batch.sprites[end].texture == texture {
+			batch.appendQuad(&batch.sprites[end], float32(w), float32(h))
+			end++
+		}
+		err = batch.renderer.Geometry(texture, batch.vertices, batch.indices)
+		if err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+func (batch *SpriteBatch) appendQuad(s *batchSprite, texW, texH float32) {
+	u0, v0, u1, v1 := float32(0), float32(0), float32(1), float32(1)
+	if !s.fullSrc {
+		u0 = float32(s.src.X) / texW
+		v0 = float32(s.src.Y) / texH
+		u1 = float32(s.src.X+s.src.W) / texW
+		v1 = float32(s.src.Y+s.src.H) / texH
+	}
+	if s.flip&FLIP_HORIZONTAL != 0 {
+		u0, u1 = u1, u0
+	}
+	if s.flip&FLIP_VERTICAL != 0 {
+		v0, v1 = v1, v0
+	}
+
+	halfW, halfH := float64(s.dst.W)/2, float64(s.dst.H)/2
+	centerX, centerY := float64(s.dst.X)+halfW, float64(s.dst.Y)+halfH
+	sin, cos := math.Sincos(s.angle * math.Pi / 180)
+	corner := func(x, y float64, u, v float32) Vertex {
+		return Vertex{
+			Position: FPoint{
+				X: float32(centerX + x*cos - y*sin),
+				Y: float32(centerY + x*sin + y*cos),
+			},
+			Color:    Color{R: 255, G: 255, B: 255, A: 255},
+			TexCoord: FPoint{X: u, Y: v},
+		}
+	}
+
+	base := int32(len(batch.vertices))
+	batch.vertices = append(batch.vertices,
+		corner(-halfW, -halfH, u0, v0),
+		corner(halfW, -halfH, u1, v0),
+		corner(halfW, halfH, u1, v1),
+		corner(-halfW, halfH, u0, v1),
+	)
+	batch.indices = append(batch.indices,
+		base, base+1, base+2,
+		base, base+2, base+3,
+	)
+}
+
+// Len returns the number of sprites waiting to be drawn.
+func (batch *SpriteBatch) Len() int {
+	return len(batch.sprites)
+}
+
+// StickState is the position of an analog stick, with X and Y in the range -1
+// to 1. Positive X is right and positive Y is down, like the raw axes.
+type StickState struct {
+	X, Y float32
+}
+
+// NewStickState combines the raw values of a stick's two axes and applies a
+// radial dead zone: if the stick's distance from the center is below the dead
+// zone, the state is 0, otherwise the distance is rescaled to start at 0 right
+// outside the dead zone while keeping the direction. Unlike NormalizeAxis on
+// both axes, this does not snap diagonals to the axes and it limits the
+// magnitude to 1, even in the corners of square stick gates.
+func NewStickState(x, y int16, deadZone float32) StickState {
+	fx := math.Max(-1, float64(x)/32767)
+	fy := math.Max(-1, float64(y)/32767)
+	magnitude := math.Hypot(fx, fy)
+	if magnitude == 0 {
+		return StickState{}
+	}
+	scaled := applyDeadZone(float32(math.Min(magnitude, 1)), deadZone)
+	scale := float64(scaled) / magnitude
+	return StickState{X: float32(fx * scale), Y: float32(fy * scale)}
+}
+
+// Angle returns the direction of the stick in radians, 0 pointing right and
+// Pi/2 pointing down.
+func (s StickState) Angle() float32 {
+	return float32(math.Atan2(float64(s.Y), float64(s.X)))
+}
+
+// Magnitude returns the distance of the stick from the center, from 0 to 1.
+func (s StickState) Magnitude() float32 {
+	return float32(math.Hypot(float64(s.X), float64(s.Y)))
+}
+
+// Stopwatch measures elapsed time with the high resolution performance
+// counter. The zero value is a stopped Stopwatch with no elapsed time.
+type Stopwatch struct {
+	start   uint64 // the counter value when the stopwatch was last started
+	elapsed uint64 // counts accumulated before the last start
+	running bool
+}
+
+// NewStopwatch returns a running Stopwatch.
+func NewStopwatch() *Stopwatch {
+	var s Stopwatch
+	s.Start()
+	return &s
+}
+
+// Elapsed returns the total time that the stopwatch was running.
+func (s *Stopwatch) Elapsed() time.Duration {
+	return countsToDuration(s.counts())
+}
+
+// Lap returns the elapsed time and restarts the stopwatch from zero.
+func (s *Stopwatch) Lap() time.Duration {
+	now := GetPerformanceCounter()
+	elapsed := s.elapsed
+	if s.running {
+		elapsed += now - s.start
+	}
+	s.start, s.elapsed, s.running = now, 0, true
+	return countsToDuration(elapsed)
+}
+
+// Reset stops the stopwatch and sets the elapsed time to zero.
+func (s *Stopwatch) Reset() {
+	*s = Stopwatch{}
+}
+
+// Running returns whether the stopwatch is running.
+func (s *Stopwatch) Running() bool {
+	return s.running
+}
+
+// Start starts or resumes the stopwatch. It does nothing if the stopwatch is
+// already running.
+func (s *Stopwatch) Start() {
+	if !s.running {
+		s.start = GetPerformanceCounter()
+		s.running = true
+	}
+}
+
+// Stop pauses the stopwatch, Start resumes it.
+func (s *Stopwatch) Stop() {
+	s.elapsed = s.counts()
+	s.running = false
+}
+
+func (s *Stopwatch) counts() uint64 {
+	if s.running {
+		return s.elapsed + GetPerformanceCounter() - s.start
+	}
+	return s.elapsed
+}
+
 // Surface contains a collection of pixels used in software blitting.
 // (https://wiki.libsdl.org/SDL_Surface)
 type Surface struct {
@@ -8118,7 +13096,11 @@ func CreateRGBSurfaceWithFormatFrom(pixels unsafe.Pointer, width, height, depth,
 // LoadBMP loads a surface from a BMP file.
 // (https://wiki.libsdl.org/SDL_LoadBMP)
 func LoadBMP(file string) (*Surface, error) {
-	return LoadBMPRW(RWFromFile(file, "rb"), true)
+	src := RWFromFile(file, "rb")
+	if src == nil {
+		return nil, GetError()
+	}
+	return LoadBMPRW(src, true)
 }
 
 // LoadBMPRW loads a BMP image from a seekable SDL data stream (memory or file).
@@ -8134,6 +13116,20 @@ func LoadBMPRW(src *RWops, freeSrc bool) (*Surface, error) {
 	return (*Surface)(unsafe.Pointer(ret)), nil
 }
 
+// ReadBMP loads a surface from BMP data read from r.
+func ReadBMP(r io.Reader) (*Surface, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	src, err := RWFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	return LoadBMPRW(src, false)
+}
+
 // At returns the pixel color at (x, y)
 func (surface *Surface) At(x, y int) color.Color {
 	pix := surface.Pixels()
@@ -8205,6 +13201,21 @@ func (surface *Surface) ColorModel() color.Model {
 	}
 }
 
+// Composite draws src over the top-left corner of the surface, blending it
+// with the given mode, e.g. BLENDMODE_BLEND to draw a UI element with alpha
+// or BLENDMODE_NONE to copy it. The blend mode of src is restored afterwards.
+func (surface *Surface) Composite(src *Surface, mode BlendMode) error {
+	old, err := src.GetBlendMode()
+	if err != nil {
+		return err
+	}
+	if err := src.SetBlendMode(mode); err != nil {
+		return err
+	}
+	defer src.SetBlendMode(old)
+	return src.Blit(nil, surface, nil)
+}
+
 // Convert copies the existing surface into a new one that is optimized for blitting to a surface of a specified pixel format.
 // (https://wiki.libsdl.org/SDL_ConvertSurface)
 func (surface *Surface) Convert(fmt *PixelFormat, flags uint32) (*Surface, error) {
@@ -8410,10 +13421,52 @@ func (surface *Surface) Pixels() []byte {
 	return b
 }
 
+// pitchedPixels returns all rows of pixels, including the padding at the end of
+// each row, i.e. Pitch*H bytes.
+func (surface *Surface) pitchedPixels() []byte {
+	var b []byte
+	length := int(surface.Pitch * surface.H)
+	sliceHeader := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	sliceHeader.Cap = length
+	sliceHeader.Len = length
+	sliceHeader.Data = uintptr(surface.pixels)
+	return b
+}
+
+// PremultiplyAlpha multiplies the color channels of all pixels by their alpha
+// values, in place. The surface has to be in PIXELFORMAT_ARGB8888.
+// This function requires SDL 2.0.18 or newer.
+func (surface *Surface) PremultiplyAlpha() error {
+	if err := premultiplyAlpha.Find(); err != nil {
+		return err
+	}
+	if surface.MustLock() {
+		if err := surface.Lock(); err != nil {
+			return err
+		}
+		defer surface.Unlock()
+	}
+	ret, _, _ := premultiplyAlpha.Call(
+		uintptr(surface.W),
+		uintptr(surface.H),
+		uintptr(surface.Format.Format),
+		uintptr(surface.pixels),
+		uintptr(surface.Pitch),
+		uintptr(surface.Format.Format),
+		uintptr(surface.pixels),
+		uintptr(surface.Pitch),
+	)
+	return errorFromInt(int(ret))
+}
+
 // SaveBMP saves the surface to a BMP file.
 // (https://wiki.libsdl.org/SDL_SaveBMP)
 func (surface *Surface) SaveBMP(file string) error {
-	return surface.SaveBMPRW(RWFromFile(file, "wb"), true)
+	dst := RWFromFile(file, "wb")
+	if dst == nil {
+		return GetError()
+	}
+	return surface.SaveBMPRW(dst, true)
 }
 
 // SaveBMPRW save the surface to a seekable SDL data stream (memory or file) in BMP format.
@@ -8570,6 +13623,100 @@ func (surface *Surface) SoftStretch(srcRect *Rect, dst *Surface, dstRect *Rect)
 	return nil
 }
 
+// SoftStretchLinear performs bilinear scaling between two surfaces of the same format, 32BPP.
+// It returns ErrInvalidParameters if a surface is nil or empty or if a
+// rectangle does not lie inside its surface.
+// This function requires SDL 2.0.16 or newer.
+// (https://wiki.libsdl.org/SDL_SoftStretchLinear)
+func (surface *Surface) SoftStretchLinear(srcRect *Rect, dst *Surface, dstRect *Rect) error {
+	if !isSurfaceArea(surface, srcRect) || !isSurfaceArea(dst, dstRect) {
+		return ErrInvalidParameters
+	}
+	if err := softStretchLinear.Find(); err != nil {
+		return err
+	}
+	ret, _, _ := softStretchLinear.Call(
+		uintptr(unsafe.Pointer(surface)),
+		uintptr(unsafe.Pointer(srcRect)),
+		uintptr(unsafe.Pointer(dst)),
+		uintptr(unsafe.Pointer(dstRect)),
+	)
+	if ret != 0 {
+		return GetError()
+	}
+	return nil
+}
+
+// isSurfaceArea returns true if the surface has pixels and rect, if not nil, is
+// a non-empty area inside it.
+func isSurfaceArea(surface *Surface, rect *Rect) bool {
+	if surface == nil || surface.W <= 0 || surface.H <= 0 {
+		return false
+	}
+	return rect == nil ||
+		rect.W > 0 && rect.H > 0 && rect.X >= 0 && rect.Y >= 0 &&
+			rect.X+rect.W <= surface.W && rect.Y+rect.H <= surface.H
+}
+
+// CreateSurfaceFromImage copies the image into a new PIXELFORMAT_ABGR8888
+// surface, which has the same byte order as image.NRGBA. The surface does not
+// reference the image, free it when it is no longer needed.
+func CreateSurfaceFromImage(img image.Image) (*Surface, error) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	nrgba, ok := img.(*image.NRGBA)
+	if !ok || b.Min != (image.Point{}) {
+		nrgba = image.NewNRGBA(image.Rect(0, 0, w, h))
+		draw.Draw(nrgba, nrgba.Bounds(), img, b.Min, draw.Src)
+	}
+	surface, err := CreateRGBSurfaceWithFormat(0, int32(w), int32(h), 32, PIXELFORMAT_ABGR8888)
+	if err != nil {
+		return nil, err
+	}
+	pixels := surface.pitchedPixels()
+	for y := 0; y < h; y++ {
+		copy(pixels[y*int(surface.Pitch):], nrgba.Pix[y*nrgba.Stride:y*nrgba.Stride+w*4])
+	}
+	return surface, nil
+}
+
+// ToRGBA copies the surface into a new image. Surfaces in other formats than
+// PIXELFORMAT_ABGR8888 are converted first.
+func (surface *Surface) ToRGBA() (*image.RGBA, error) {
+	src := surface
+	if surface.Format.Format != PIXELFORMAT_ABGR8888 {
+		converted, err := surface.ConvertFormat(PIXELFORMAT_ABGR8888, 0)
+		if err != nil {
+			return nil, err
+		}
+		defer converted.Free()
+		src = converted
+	}
+	if src.MustLock() {
+		if err := src.Lock(); err != nil {
+			return nil, err
+		}
+		defer src.Unlock()
+	}
+
+	pixels := src.pitchedPixels()
+	w, h := int(src.W), int(src.H)
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+w*4]
+		copy(row, pixels[y*int(src.Pitch):])
+		// Surface colors are not premultiplied, image.RGBA colors are.
+		for i := 0; i < len(row); i += 4 {
+			if a := uint32(row[i+3]); a != 255 {
+				row[i+0] = uint8(uint32(row[i+0]) * a / 255)
+				row[i+1] = uint8(uint32(row[i+1]) * a / 255)
+				row[i+2] = uint8(uint32(row[i+2]) * a / 255)
+			}
+		}
+	}
+	return img, nil
+}
+
 // Unlock releases the surface after directly accessing the pixels.
 // (https://wiki.libsdl.org/SDL_UnlockSurface)
 func (surface *Surface) Unlock() {
@@ -8606,6 +13753,29 @@ func (surface *Surface) UpperBlitScaled(srcRect *Rect, dst *Surface, dstRect *Re
 	return nil
 }
 
+// WriteBMP writes the surface to w in BMP format.
+func (surface *Surface) WriteBMP(w io.Writer) error {
+	// SDL writes at most 32 bits per pixel, a palette of 256 colors and the
+	// largest BMP header.
+	const maxHeaderSize = 14 + 124 + 256*4
+	rowSize := (int(surface.W)*4 + 3) &^ 3
+	buf := make([]byte, maxHeaderSize+rowSize*int(surface.H))
+	dst, err := RWFromBytes(buf)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+	if err := surface.SaveBMPRW(dst, false); err != nil {
+		return err
+	}
+	n, err := dst.Tell()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf[:n])
+	return err
+}
+
 // SysWMEvent contains a video driver dependent system event.
 // (https://wiki.libsdl.org/SDL_SysWMEvent)
 type SysWMEvent struct {
@@ -8624,6 +13794,13 @@ func (e *SysWMEvent) GetType() uint32 {
 	return e.Type
 }
 
+// Msg returns the driver dependent message data. It is only valid until the
+// next event is polled. Call Windows on the result to get the Windows message.
+// SYSWMEVENT is disabled by default, use EventState to enable it.
+func (e *SysWMEvent) Msg() *SysWMmsg {
+	return (*SysWMmsg)(e.msg)
+}
+
 // SysWMInfo contains system-dependent information about a window.
 // (https://wiki.libsdl.org/SDL_SysWMinfo)
 type SysWMInfo struct {
@@ -8660,6 +13837,34 @@ func (info *SysWMInfo) GetX11Info() *X11Info {
 // SystemCursor is a system cursor created by CreateSystemCursor().
 type SystemCursor uint32
 
+// SystemInfo is returned by GetSystemInfo.
+type SystemInfo struct {
+	Platform         string   // e.g. "Windows"
+	Version          Version  // the version of the loaded SDL2.dll
+	Revision         string   // the source code revision of the loaded SDL2.dll
+	SystemRAM        int      // in MiB
+	CPUCount         int      // number of logical CPU cores
+	CPUCacheLineSize int      // L1 cache line size in bytes
+	Locales          []Locale // the preferred locales, empty before SDL 2.0.14
+}
+
+// String formats the information as one line per field.
+func (info SystemInfo) String() string {
+	locales := make([]string, len(info.Locales))
+	for i := range info.Locales {
+		locales[i] = info.Locales[i].String()
+	}
+	return fmt.Sprintf(
+		"Platform: %s\nSDL: %d.%d.%d (%s)\nRAM: %d MiB\nCPUs: %d\nCache line: %d bytes\nLocales: %s",
+		info.Platform,
+		info.Version.Major, info.Version.Minor, info.Version.Patch, info.Revision,
+		info.SystemRAM,
+		info.CPUCount,
+		info.CPUCacheLineSize,
+		strings.Join(locales, ", "),
+	)
+}
+
 // TextEditingEvent contains keyboard text editing event information.
 // (https://wiki.libsdl.org/SDL_TextEditingEvent)
 type TextEditingEvent struct {
@@ -8671,14 +13876,10 @@ type TextEditingEvent struct {
 	Length    int32                          // the number of characters to edit from the start point
 }
 
-// GetText returns the text as string.
+// GetText returns the text up to the terminating zero. Invalid UTF-8 is
+// replaced by utf8.RuneError.
 func (e *TextEditingEvent) GetText() string {
-	for i, b := range e.Text {
-		if b == 0 {
-			return string(e.Text[:i])
-		}
-	}
-	return string(e.Text[:])
+	return eventText(e.Text[:])
 }
 
 // GetTimestamp returns the timestamp of the event.
@@ -8691,6 +13892,31 @@ func (e *TextEditingEvent) GetType() uint32 {
 	return e.Type
 }
 
+// Runes returns the characters of the text, see GetText.
+func (e *TextEditingEvent) Runes() []rune {
+	return []rune(e.GetText())
+}
+
+// Selection splits the editing text at Start and Length, which count
+// characters, not bytes. An input method uses them to mark the part of the
+// composition that is being converted, or with a Length of 0, the cursor
+// position. Out of range values are clamped to the text.
+func (e *TextEditingEvent) Selection() (before, selected, after string) {
+	runes := e.Runes()
+	clamp := func(i int) int {
+		if i < 0 {
+			return 0
+		}
+		if i > len(runes) {
+			return len(runes)
+		}
+		return i
+	}
+	start := clamp(int(e.Start))
+	end := clamp(start + int(e.Length))
+	return string(runes[:start]), string(runes[start:end]), string(runes[end:])
+}
+
 // TextInputEvent contains keyboard text input event information.
 // (https://wiki.libsdl.org/SDL_TextInputEvent)
 type TextInputEvent struct {
@@ -8700,14 +13926,10 @@ type TextInputEvent struct {
 	Text      [TEXTINPUTEVENT_TEXT_SIZE]byte // the null-terminated input text in UTF-8 encoding
 }
 
-// GetText returns the text as string.
+// GetText returns the text up to the terminating zero. Invalid UTF-8 is
+// replaced by utf8.RuneError.
 func (e *TextInputEvent) GetText() string {
-	for i, b := range e.Text {
-		if b == 0 {
-			return string(e.Text[:i])
-		}
-	}
-	return string(e.Text[:])
+	return eventText(e.Text[:])
 }
 
 // GetTimestamp returns the timestamp of the event.
@@ -8720,6 +13942,22 @@ func (e *TextInputEvent) GetType() uint32 {
 	return e.Type
 }
 
+// Runes returns the characters of the text, see GetText.
+func (e *TextInputEvent) Runes() []rune {
+	return []rune(e.GetText())
+}
+
+// eventText converts the zero-terminated UTF-8 text of a text event.
+func eventText(text []byte) string {
+	if i := bytes.IndexByte(text, 0); i != -1 {
+		text = text[:i]
+	}
+	if !utf8.Valid(text) {
+		text = bytes.ToValidUTF8(text, []byte(string(utf8.RuneError)))
+	}
+	return string(text)
+}
+
 // Texture contains an efficient, driver-specific representation of pixel data.
 // (https://wiki.libsdl.org/SDL_Texture)
 type Texture struct{}
@@ -8739,6 +13977,7 @@ func (texture *Texture) Destroy() error {
 }
 
 // GLBind binds an OpenGL/ES/ES2 texture to the current context for use with OpenGL instructions when rendering OpenGL primitives directly.
+// texw and texh may be nil, see GLBindScale for a version returning them.
 // (https://wiki.libsdl.org/SDL_GL_BindTexture)
 func (texture *Texture) GLBind(texw, texh *float32) error {
 	ret, _, _ := gl_BindTexture.Call(
@@ -8749,6 +13988,15 @@ func (texture *Texture) GLBind(texw, texh *float32) error {
 	return errorFromInt(int(ret))
 }
 
+// GLBindScale binds the texture like GLBind and returns the texture
+// coordinates of its bottom-right corner. These are 1 unless the OpenGL driver
+// had to round the texture size up to a power of two. Call GLUnbind when done
+// drawing with it.
+func (texture *Texture) GLBindScale() (texW, texH float32, err error) {
+	err = texture.GLBind(&texW, &texH)
+	return
+}
+
 // GLUnbind unbinds an OpenGL/ES/ES2 texture from the current context.
 // (https://wiki.libsdl.org/SDL_GL_UnbindTexture)
 func (texture *Texture) GLUnbind() error {
@@ -8778,10 +14026,44 @@ func (texture *Texture) GetBlendMode() (bm BlendMode, err error) {
 	return
 }
 
-// Lock locks a portion of the texture for write-only pixel access.
+// GetColorMod returns the additional color value multiplied into render copy operations.
+// (https://wiki.libsdl.org/SDL_GetTextureColorMod)
+func (texture *Texture) GetColorMod() (r, g, b uint8, err error) {
+	ret, _, _ := getTextureColorMod.Call(
+		uintptr(unsafe.Pointer(texture)),
+		uintptr(unsafe.Pointer(&r)),
+		uintptr(unsafe.Pointer(&g)),
+		uintptr(unsafe.Pointer(&b)),
+	)
+	err = errorFromInt(int(ret))
+	return
+}
+
+// GetTint returns the color and alpha modulation of the texture as one color.
+func (texture *Texture) GetTint() (Color, error) {
+	r, g, b, err := texture.GetColorMod()
+	if err != nil {
+		return Color{}, err
+	}
+	a, err := texture.GetAlphaMod()
+	return Color{R: r, G: g, B: b, A: a}, err
+}
+
+// Info returns the attributes of a texture in a single struct.
+// (https://wiki.libsdl.org/SDL_QueryTexture)
+func (texture *Texture) Info() (TextureInfo, error) {
+	var info TextureInfo
+	var err error
+	info.Format, info.Access, info.W, info.H, err = texture.Query()
+	return info, err
+}
+
+// Lock locks a portion of the texture for write-only pixel access. A nil rect
+// locks the whole texture. The returned slice starts at the top-left pixel of
+// rect and ends after its bottom-right pixel, rows are pitch bytes apart.
 // (https://wiki.libsdl.org/SDL_LockTexture)
 func (texture *Texture) Lock(rect *Rect) ([]byte, int, error) {
-	var pitch int
+	var pitch int32
 	var pixels unsafe.Pointer
 	ret, _, _ := lockTexture.Call(
 		uintptr(unsafe.Pointer(texture)),
@@ -8790,28 +14072,50 @@ func (texture *Texture) Lock(rect *Rect) ([]byte, int, error) {
 		uintptr(unsafe.Pointer(&pitch)),
 	)
 	if ret != 0 {
-		return nil, pitch, GetError()
+		return nil, int(pitch), GetError()
 	}
 
-	_, _, w, h, err := texture.Query()
+	format, _, _, h, err := texture.Query()
 	if err != nil {
-		return nil, pitch, GetError()
+		texture.Unlock()
+		return nil, int(pitch), err
 	}
 
 	var b []byte
-	var length int
+	length := int(pitch) * int(h)
 	if rect != nil {
-		bytesPerPixel := int32(pitch) / w
-		length = int(bytesPerPixel * (w*rect.H - rect.X - (w - rect.X - rect.W)))
-	} else {
-		length = pitch * int(h)
+		length = 0
+		if rect.W > 0 && rect.H > 0 {
+			length = int(pitch)*int(rect.H-1) + int(rect.W)*BytesPerPixel(format)
+		}
 	}
 	sliceHeader := (*reflect.SliceHeader)(unsafe.Pointer(&b))
-	sliceHeader.Cap = int(length)
-	sliceHeader.Len = int(length)
+	sliceHeader.Cap = length
+	sliceHeader.Len = length
 	sliceHeader.Data = uintptr(pixels)
 
-	return b, pitch, nil
+	return b, int(pitch), nil
+}
+
+// LockToSurface locks a portion of the texture for write-only pixel access
+// and exposes it as a surface. A nil rect locks the whole texture. The surface
+// belongs to the texture and is freed by Unlock, do not free it yourself.
+// This function requires SDL 2.0.12 or newer.
+// (https://wiki.libsdl.org/SDL_LockTextureToSurface)
+func (texture *Texture) LockToSurface(rect *Rect) (*Surface, error) {
+	if err := lockTextureToSurface.Find(); err != nil {
+		return nil, err
+	}
+	var surface *Surface
+	ret, _, _ := lockTextureToSurface.Call(
+		uintptr(unsafe.Pointer(texture)),
+		uintptr(unsafe.Pointer(rect)),
+		uintptr(unsafe.Pointer(&surface)),
+	)
+	if ret != 0 {
+		return nil, GetError()
+	}
+	return surface, nil
 }
 
 // Query returns the attributes of a texture.
@@ -8862,6 +14166,17 @@ func (texture *Texture) SetColorMod(r uint8, g uint8, b uint8) error {
 	return errorFromInt(int(ret))
 }
 
+// SetTint sets both the color and the alpha modulation of the texture. The
+// color's red, green and blue are used for the color mod, its alpha for the
+// alpha mod. Use color.White to draw the texture unchanged.
+func (texture *Texture) SetTint(c color.Color) error {
+	tint := toColor(c)
+	if err := texture.SetColorMod(tint.R, tint.G, tint.B); err != nil {
+		return err
+	}
+	return texture.SetAlphaMod(tint.A)
+}
+
 // Unlock unlocks a texture, uploading the changes to video memory, if needed.
 // (https://wiki.libsdl.org/SDL_UnlockTexture)
 func (texture *Texture) Unlock() {
@@ -8924,6 +14239,13 @@ func (texture *Texture) UpdateYUV(rect *Rect, yPlane []byte, yPitch int, uPlane
 	return errorFromInt(int(ret))
 }
 
+// TextureInfo contains the attributes of a texture, see Texture.Info.
+type TextureInfo struct {
+	Format uint32 // one of the PIXELFORMAT_* values
+	Access int    // one of the TEXTUREACCESS_* values
+	W, H   int32  // width and height in pixels
+}
+
 // ThreadID is the thread identifier for a thread.
 type ThreadID uint64
 
@@ -8949,6 +14271,7 @@ type TouchFingerEvent struct {
 	DX        float32  // the distance moved in the x-axis, normalized (-1...1)
 	DY        float32  // the distance moved in the y-axis, normalized (-1...1)
 	Pressure  float32  // the quantity of pressure applied, normalized (0...1)
+	WindowID  uint32   // the window underneath the finger, if any (>= SDL 2.0.22)
 }
 
 // GetTimestamp returns the timestamp of the event.
@@ -8961,6 +14284,20 @@ func (e *TouchFingerEvent) GetType() uint32 {
 	return e.Type
 }
 
+// PressureCurve returns the pressure, clamped to 0...1, raised to the power of
+// gamma. A gamma below 1 makes light touches count more, a gamma above 1 makes
+// them count less.
+func (e *TouchFingerEvent) PressureCurve(gamma float32) float32 {
+	p := e.Pressure
+	if p <= 0 {
+		return 0
+	}
+	if p >= 1 {
+		return 1
+	}
+	return float32(math.Pow(float64(p), float64(gamma)))
+}
+
 // TouchID is the ID of a touch device.
 type TouchID int64
 
@@ -8970,6 +14307,10 @@ type UIKitInfo struct {
 }
 
 // UserEvent contains an application-defined event type.
+// PushEvent is safe to call from any goroutine, pushing a UserEvent wakes up a
+// WaitEvent on the main thread. Since the event is stored in C memory, Data1
+// and Data2 must not hold pointers to Go memory; use SetData to store integer
+// values, e.g. keys into a Go map.
 // (https://wiki.libsdl.org/SDL_UserEvent)
 type UserEvent struct {
 	Type      uint32         // value obtained from RegisterEvents()
@@ -8980,6 +14321,12 @@ type UserEvent struct {
 	Data2     unsafe.Pointer // user defined data pointer
 }
 
+// NewUserEvent returns a user event of the type, which is USEREVENT or a type
+// returned by RegisterEvents, with the code. Use PushEvent to send it.
+func NewUserEvent(typ uint32, code int32) *UserEvent {
+	return &UserEvent{Type: typ, Code: code}
+}
+
 // GetTimestamp returns the timestamp of the event.
 func (e *UserEvent) GetTimestamp() uint32 {
 	return e.Timestamp
@@ -8990,6 +14337,28 @@ func (e *UserEvent) GetType() uint32 {
 	return e.Type
 }
 
+// Data returns the integer values stored with SetData.
+func (e *UserEvent) Data() (data1, data2 uintptr) {
+	return uintptr(e.Data1), uintptr(e.Data2)
+}
+
+// SetData stores two integer values in Data1 and Data2.
+func (e *UserEvent) SetData(data1, data2 uintptr) {
+	e.Data1 = unsafe.Pointer(data1)
+	e.Data2 = unsafe.Pointer(data2)
+}
+
+// SetWindow associates the event with the window. Such events are also sent
+// to the window's Events channel.
+func (e *UserEvent) SetWindow(window *Window) error {
+	id, err := window.GetID()
+	if err != nil {
+		return err
+	}
+	e.WindowID = id
+	return nil
+}
+
 // Version contains information about the version of SDL in use.
 // (https://wiki.libsdl.org/SDL_version)
 type Version struct {
@@ -8998,13 +14367,53 @@ type Version struct {
 	Patch uint8 // update version (patchlevel)
 }
 
+// Vertex is a corner of a triangle drawn with Renderer.Geometry.
+// TODO: (https://wiki.libsdl.org/SDL_Vertex)
+type Vertex struct {
+	Position FPoint // vertex position, in renderer coordinates
+	Color    Color  // vertex color
+	TexCoord FPoint // normalized texture coordinates, if needed
+}
+
+// Volume is the volume used when mixing audio with MixAudio and
+// MixAudioFormat. It ranges from 0 (silence) to MIX_MAXVOLUME (full volume).
+type Volume int
+
+// Clamp returns the volume limited to the range 0 to MIX_MAXVOLUME.
+func (v Volume) Clamp() Volume {
+	if v < 0 {
+		return 0
+	}
+	if v > MIX_MAXVOLUME {
+		return MIX_MAXVOLUME
+	}
+	return v
+}
+
+// WakeEvent is sent by WakeEventLoop to wake up WaitEvent. It carries no
+// data.
+type WakeEvent struct {
+	Type      uint32 // the user event type reserved for WakeEvents
+	Timestamp uint32 // timestamp of the event
+}
+
+// GetTimestamp returns the timestamp of the event.
+func (e *WakeEvent) GetTimestamp() uint32 {
+	return e.Timestamp
+}
+
+// GetType returns the event type.
+func (e *WakeEvent) GetType() uint32 {
+	return e.Type
+}
+
 // Window is a type used to identify a window.
 type Window struct{}
 
 // CreateWindow creates a window with the specified position, dimensions, and flags.
 // (https://wiki.libsdl.org/SDL_CreateWindow)
 func CreateWindow(title string, x, y, w, h int32, flags uint32) (*Window, error) {
-	t := append([]byte(title), 0)
+	t := goToSDLString(title)
 	ret, _, _ := createWindow.Call(
 		uintptr(unsafe.Pointer(&t[0])),
 		uintptr(x),
@@ -9016,7 +14425,9 @@ func CreateWindow(title string, x, y, w, h int32, flags uint32) (*Window, error)
 	if ret == 0 {
 		return nil, GetError()
 	}
-	return (*Window)(unsafe.Pointer(ret)), nil
+	window := (*Window)(unsafe.Pointer(ret))
+	registerWindow(window)
+	return window, nil
 }
 
 // CreateWindowFrom creates an SDL window from an existing native window.
@@ -9026,7 +14437,9 @@ func CreateWindowFrom(data unsafe.Pointer) (*Window, error) {
 	if ret == 0 {
 		return nil, GetError()
 	}
-	return (*Window)(unsafe.Pointer(ret)), nil
+	window := (*Window)(unsafe.Pointer(ret))
+	registerWindow(window)
+	return window, nil
 }
 
 // GetKeyboardFocus returns the window which currently has keyboard focus.
@@ -9053,9 +14466,60 @@ func GetWindowFromID(id uint32) (*Window, error) {
 	return (*Window)(unsafe.Pointer(ret)), nil
 }
 
+// windows holds all windows that were created and not yet destroyed, in the
+// order of their creation.
+var windows []*Window
+
+// windowEvents holds the channels returned by Window.Events, by window ID.
+var windowEvents = make(map[uint32]chan Event)
+
+// windowedGeometry holds the position and size of windows before they were
+// put into fullscreen mode by ToggleFullscreen or SetFullscreenMode.
+var windowedGeometry = make(map[*Window]Rect)
+
+// WindowEventBufferSize is the capacity of the channels returned by
+// Window.Events. Events are dropped while a channel is full.
+var WindowEventBufferSize = 64
+
+func registerWindow(window *Window) {
+	windows = append(windows, window)
+}
+
+// Windows returns all windows created with CreateWindow, CreateWindowFrom or
+// CreateWindowAndRenderer that were not yet destroyed.
+func Windows() []*Window {
+	return append([]*Window(nil), windows...)
+}
+
+// CenterOn centers the window on the display, including its borders if the
+// size of the borders is known.
+func (window *Window) CenterOn(displayIndex int) error {
+	bounds, err := GetDisplayBounds(displayIndex)
+	if err != nil {
+		return err
+	}
+	x, y := window.framePositionIn(bounds, 0.5, 0.5)
+	window.SetPosition(x, y)
+	return nil
+}
+
 // Destroy destroys the window.
 // (https://wiki.libsdl.org/SDL_DestroyWindow)
 func (window *Window) Destroy() error {
+	for i := range windows {
+		if windows[i] == window {
+			windows = append(windows[:i], windows[i+1:]...)
+			break
+		}
+	}
+	if id, err := window.GetID(); err == nil {
+		if c, ok := windowEvents[id]; ok {
+			close(c)
+			delete(windowEvents, id)
+		}
+	}
+	delete(windowedGeometry, window)
+
 	lastErr := GetError()
 	ClearError()
 	destroyWindow.Call(uintptr(unsafe.Pointer(window)))
@@ -9067,6 +14531,26 @@ func (window *Window) Destroy() error {
 	return nil
 }
 
+// Events returns a channel that receives all events for this window, i.e.
+// window, keyboard, text, mouse, drop and user events with this window's ID.
+// The events are still returned by PollEvent, WaitEvent and
+// WaitEventTimeout, which route them to the channel; so one of them has to be
+// called for the channel to receive anything. The channel is closed when the
+// window is destroyed. Events are dropped while the channel is full, see
+// WindowEventBufferSize.
+func (window *Window) Events() (<-chan Event, error) {
+	id, err := window.GetID()
+	if err != nil {
+		return nil, err
+	}
+	c, ok := windowEvents[id]
+	if !ok {
+		c = make(chan Event, WindowEventBufferSize)
+		windowEvents[id] = c
+	}
+	return c, nil
+}
+
 // GLCreateContext creates an OpenGL context for use with an OpenGL window, and make it current.
 // (https://wiki.libsdl.org/SDL_GL_CreateContext)
 func (window *Window) GLCreateContext() (GLContext, error) {
@@ -9167,6 +14651,31 @@ func (window *Window) GetGrab() bool {
 	return ret != 0
 }
 
+// GetICCProfile returns the raw ICC profile data for the screen the window
+// is currently on.
+// This function requires SDL 2.0.18 or newer.
+// (https://wiki.libsdl.org/SDL_GetWindowICCProfile)
+func (window *Window) GetICCProfile() ([]byte, error) {
+	if err := getWindowICCProfile.Find(); err != nil {
+		return nil, err
+	}
+	var size uintptr
+	ret, _, _ := getWindowICCProfile.Call(
+		uintptr(unsafe.Pointer(window)),
+		uintptr(unsafe.Pointer(&size)),
+	)
+	if ret == 0 {
+		return nil, GetError()
+	}
+	defer free.Call(ret)
+	var data []byte
+	sliceHeader := (*reflect.SliceHeader)(unsafe.Pointer(&data))
+	sliceHeader.Cap = int(size)
+	sliceHeader.Len = int(size)
+	sliceHeader.Data = ret
+	return append([]byte(nil), data...), nil
+}
+
 // GetID returns the numeric ID of the window, for logging purposes.
 //  (https://wiki.libsdl.org/SDL_GetWindowID)
 func (window *Window) GetID() (uint32, error) {
@@ -9199,6 +14708,22 @@ func (window *Window) GetMinimumSize() (w, h int32) {
 	return
 }
 
+// GetMouseRect returns the area of the window that the mouse is confined to.
+// ok is false if the mouse is not confined.
+// This function requires SDL 2.0.18 or newer, with older versions ok is
+// always false.
+// (https://wiki.libsdl.org/SDL_GetWindowMouseRect)
+func (window *Window) GetMouseRect() (rect Rect, ok bool) {
+	if getWindowMouseRect.Find() != nil {
+		return Rect{}, false
+	}
+	ret, _, _ := getWindowMouseRect.Call(uintptr(unsafe.Pointer(window)))
+	if ret == 0 {
+		return Rect{}, false
+	}
+	return *(*Rect)(unsafe.Pointer(ret)), true
+}
+
 // GetPixelFormat returns the pixel format associated with the window.
 // (https://wiki.libsdl.org/SDL_GetWindowPixelFormat)
 func (window *Window) GetPixelFormat() (uint32, error) {
@@ -9251,7 +14776,9 @@ func (window *Window) GetSurface() (*Surface, error) {
 	return (*Surface)(unsafe.Pointer(ret)), nil
 }
 
-// GetTitle returns the title of the window.
+// GetTitle returns the title of the window. SDL stores titles as UTF-8 and
+// sets the native window title from it, so non-ASCII titles survive a
+// round-trip through SetTitle and GetTitle.
 // (https://wiki.libsdl.org/SDL_GetWindowTitle)
 func (window *Window) GetTitle() string {
 	ret, _, _ := getWindowTitle.Call(uintptr(unsafe.Pointer(window)))
@@ -9283,12 +14810,47 @@ func (window *Window) GetWindowOpacity() (opacity float32, err error) {
 	return opacity, errorFromInt(int(ret))
 }
 
+// HasKeyboardFocus reports whether the window has keyboard focus and thus
+// receives keyboard and text input events.
+func (window *Window) HasKeyboardFocus() bool {
+	return GetKeyboardFocus() == window
+}
+
 // Hide hides the window.
 // (https://wiki.libsdl.org/SDL_HideWindow)
 func (window *Window) Hide() {
 	hideWindow.Call(uintptr(unsafe.Pointer(window)))
 }
 
+// IsFullscreen reports whether the window is in real or desktop fullscreen
+// mode.
+func (window *Window) IsFullscreen() bool {
+	return window.GetFlags()&WINDOW_FULLSCREEN != 0
+}
+
+// IsMaximized reports whether the window is maximized.
+func (window *Window) IsMaximized() bool {
+	return window.GetFlags()&WINDOW_MAXIMIZED != 0
+}
+
+// IsMinimized reports whether the window is minimized.
+func (window *Window) IsMinimized() bool {
+	return window.GetFlags()&WINDOW_MINIMIZED != 0
+}
+
+// IsScreenKeyboardShown reports whether the screen keyboard is shown for the
+// window.
+// (https://wiki.libsdl.org/SDL_IsScreenKeyboardShown)
+func (window *Window) IsScreenKeyboardShown() bool {
+	return IsScreenKeyboardShown(window)
+}
+
+// IsShown reports whether the window is visible, i.e. not hidden. A minimized
+// window still counts as shown.
+func (window *Window) IsShown() bool {
+	return window.GetFlags()&WINDOW_SHOWN != 0
+}
+
 // Maximize makes the window as large as possible.
 // (https://wiki.libsdl.org/SDL_MaximizeWindow)
 func (window *Window) Maximize() {
@@ -9301,6 +14863,82 @@ func (window *Window) Minimize() {
 	minimizeWindow.Call(uintptr(unsafe.Pointer(window)))
 }
 
+// MoveToDisplay moves the window to the display, keeping its relative
+// position, e.g. a window in the top right corner of one display ends up in
+// the top right corner of the other display. The window is moved completely
+// onto the display if possible.
+func (window *Window) MoveToDisplay(displayIndex int) error {
+	from, err := window.GetDisplayIndex()
+	if err != nil {
+		return err
+	}
+	fromBounds, err := GetDisplayBounds(from)
+	if err != nil {
+		return err
+	}
+	toBounds, err := GetDisplayBounds(displayIndex)
+	if err != nil {
+		return err
+	}
+
+	// The relative position is the fraction of the free space on the display
+	// that is left of and above the window.
+	top, left, bottom, right := window.bordersSize()
+	w, h := window.GetSize()
+	w, h = w+left+right, h+top+bottom
+	x, y := window.GetPosition()
+	relX := relativePosition(x-left-fromBounds.X, fromBounds.W-w)
+	relY := relativePosition(y-top-fromBounds.Y, fromBounds.H-h)
+	x, y = window.framePositionIn(toBounds, relX, relY)
+	window.SetPosition(x, y)
+	return nil
+}
+
+// bordersSize returns GetBordersSize or all zeros if the sizes are unknown,
+// e.g. for borderless windows or windows that are not shown yet.
+func (window *Window) bordersSize() (top, left, bottom, right int32) {
+	if getWindowBordersSize.Find() != nil {
+		return 0, 0, 0, 0
+	}
+	top, left, bottom, right, err := window.GetBordersSize()
+	if err != nil {
+		return 0, 0, 0, 0
+	}
+	return top, left, bottom, right
+}
+
+// framePositionIn returns the window position that places the window frame,
+// i.e. the window including its borders, inside the bounds. relX and relY go
+// from 0 for the left/top to 1 for the right/bottom edge. If the window is
+// larger than the bounds, its top-left corner is placed at the top-left of the
+// bounds.
+func (window *Window) framePositionIn(bounds Rect, relX, relY float64) (x, y int32) {
+	top, left, bottom, right := window.bordersSize()
+	w, h := window.GetSize()
+	w, h = w+left+right, h+top+bottom
+	freeW, freeH := bounds.W-w, bounds.H-h
+	if freeW < 0 {
+		freeW = 0
+	}
+	if freeH < 0 {
+		freeH = 0
+	}
+	x = bounds.X + int32(math.Round(relX*float64(freeW))) + left
+	y = bounds.Y + int32(math.Round(relY*float64(freeH))) + top
+	return
+}
+
+// relativePosition returns offset/space clamped to [0..1].
+func relativePosition(offset, space int32) float64 {
+	if space <= 0 || offset <= 0 {
+		return 0
+	}
+	if offset >= space {
+		return 1
+	}
+	return float64(offset) / float64(space)
+}
+
 // Raise raises the window above other windows and set the input focus.
 // (https://wiki.libsdl.org/SDL_RaiseWindow)
 func (window *Window) Raise() {
@@ -9364,6 +15002,28 @@ func (window *Window) SetFullscreen(flags uint32) error {
 	return errorFromInt(int(ret))
 }
 
+// SetFullscreenMode puts the window into real fullscreen mode, changing the
+// display to the given mode. Use ChooseDisplayMode to get a valid mode.
+// ToggleFullscreen leaves fullscreen mode again and restores the window's
+// previous position and size.
+func (window *Window) SetFullscreenMode(mode DisplayMode) error {
+	if err := window.SetDisplayMode(&mode); err != nil {
+		return err
+	}
+	return window.enterFullscreen(WINDOW_FULLSCREEN)
+}
+
+// enterFullscreen remembers the windowed geometry, unless the window already
+// is fullscreen, and sets the fullscreen flags.
+func (window *Window) enterFullscreen(flags uint32) error {
+	if !window.IsFullscreen() {
+		x, y := window.GetPosition()
+		w, h := window.GetSize()
+		windowedGeometry[window] = Rect{X: x, Y: y, W: w, H: h}
+	}
+	return window.SetFullscreen(flags)
+}
+
 // SetGammaRamp sets the gamma ramp for the display that owns the given window.
 // (https://wiki.libsdl.org/SDL_SetWindowGammaRamp)
 func (window *Window) SetGammaRamp(red, green, blue *[256]uint16) error {
@@ -9394,6 +15054,19 @@ func (window *Window) SetIcon(icon *Surface) {
 	)
 }
 
+// SetIconFromImage sets the icon for the window from an image, keeping its
+// alpha channel. Windows scales the icon as needed, 32x32 or 64x64 pixel
+// images work well.
+func (window *Window) SetIconFromImage(img image.Image) error {
+	icon, err := CreateSurfaceFromImage(img)
+	if err != nil {
+		return err
+	}
+	defer icon.Free()
+	window.SetIcon(icon)
+	return nil
+}
+
 // SetMaximumSize sets the maximum size of the window's client area.
 // (https://wiki.libsdl.org/SDL_SetWindowMaximumSize)
 func (window *Window) SetMaximumSize(maxW, maxH int32) {
@@ -9443,10 +15116,11 @@ func (window *Window) SetSize(w, h int32) {
 	)
 }
 
-// SetTitle sets the title of the window.
+// SetTitle sets the title of the window. Invalid UTF-8 in the title is
+// replaced by utf8.RuneError.
 // (https://wiki.libsdl.org/SDL_SetWindowTitle)
 func (window *Window) SetTitle(title string) {
-	t := append([]byte(title), 0)
+	t := goToSDLString(title)
 	setWindowTitle.Call(
 		uintptr(unsafe.Pointer(window)),
 		uintptr(unsafe.Pointer(&t[0])),
@@ -9469,6 +15143,56 @@ func (window *Window) Show() {
 	showWindow.Call(uintptr(unsafe.Pointer(window)))
 }
 
+// StartTextInput starts text input for a text box in this window. The rect,
+// in window coordinates, is the area of the text box; input methods place
+// their candidate list next to it. A nil rect leaves the previous one.
+// Text input events go to the window with keyboard focus, see
+// HasKeyboardFocus.
+func (window *Window) StartTextInput(rect *Rect) {
+	if rect != nil {
+		SetTextInputRect(rect)
+	}
+	StartTextInput()
+}
+
+// StopTextInput stops text input that was started with StartTextInput and
+// clears any unfinished input method composition, if the SDL version supports
+// ClearComposition.
+func (window *Window) StopTextInput() {
+	if clearComposition.Find() == nil {
+		ClearComposition()
+	}
+	StopTextInput()
+}
+
+// Title returns the title of the window, like GetTitle.
+func (window *Window) Title() string {
+	return window.GetTitle()
+}
+
+// ToggleFullscreen switches the window between windowed and fullscreen mode.
+// If desktop is true, fullscreen mode keeps the desktop's resolution
+// (WINDOW_FULLSCREEN_DESKTOP), otherwise the display changes to the window's
+// display mode (WINDOW_FULLSCREEN). When leaving fullscreen mode, the window
+// gets back the position and size it had before.
+func (window *Window) ToggleFullscreen(desktop bool) error {
+	if !window.IsFullscreen() {
+		if desktop {
+			return window.enterFullscreen(WINDOW_FULLSCREEN_DESKTOP)
+		}
+		return window.enterFullscreen(WINDOW_FULLSCREEN)
+	}
+	if err := window.SetFullscreen(0); err != nil {
+		return err
+	}
+	if r, ok := windowedGeometry[window]; ok {
+		delete(windowedGeometry, window)
+		window.SetSize(r.W, r.H)
+		window.SetPosition(r.X, r.Y)
+	}
+	return nil
+}
+
 // UpdateSurface copies the window surface to the screen.
 // (https://wiki.libsdl.org/SDL_UpdateWindowSurface)
 func (window *Window) UpdateSurface() error {
@@ -9491,25 +15215,22 @@ func (window *Window) UpdateSurfaceRects(rects []Rect) error {
 }
 
 // VulkanCreateSurface creates a Vulkan rendering surface for a window.
+// The instance is a VkInstance handle and the returned value is the created
+// VkSurfaceKHR handle.
 // (https://wiki.libsdl.org/SDL_Vulkan_CreateSurface)
-func (window *Window) VulkanCreateSurface(instance interface{}) (surface unsafe.Pointer, err error) {
-	// TODO
-	return nil, nil
-	//if instance == nil {
-	//	return 0, errors.New("vulkan: instance is nil")
-	//}
-	//val := reflect.ValueOf(instance)
-	//if val.Kind() != reflect.Ptr {
-	//	return 0, errors.New("vulkan: instance is not a VkInstance (expected kind Ptr, got " + val.Kind().String() + ")")
-	//}
-	//var vulkanSurface C.VkSurfaceKHR
-	//if C.SDL_Vulkan_CreateSurface(window.cptr(),
-	//	(C.VkInstance)(unsafe.Pointer(val.Pointer())),
-	//	(*C.VkSurfaceKHR)(unsafe.Pointer(&vulkanSurface))) == C.SDL_FALSE {
-	//
-	//	return 0, GetError()
-	//}
-	//return uintptr(unsafe.Pointer(&vulkanSurface)), nil
+func (window *Window) VulkanCreateSurface(instance uintptr) (surface uint64, err error) {
+	if instance == 0 {
+		return 0, errors.New("vulkan: instance is nil")
+	}
+	ret, _, _ := vulkan_CreateSurface.Call(
+		uintptr(unsafe.Pointer(window)),
+		instance,
+		uintptr(unsafe.Pointer(&surface)),
+	)
+	if ret == 0 {
+		return 0, GetError()
+	}
+	return surface, nil
 }
 
 // VulkanGetDrawableSize gets the size of a window's underlying drawable in pixels (for use with setting viewport, scissor & etc).
@@ -9526,21 +15247,29 @@ func (window *Window) VulkanGetDrawableSize() (w, h int32) {
 // VulkanGetInstanceExtensions gets the names of the Vulkan instance extensions needed to create a surface with VulkanCreateSurface().
 // (https://wiki.libsdl.org/SDL_Vulkan_GetInstanceExtensions)
 func (window *Window) VulkanGetInstanceExtensions() []string {
-	// TODO
-	return nil
-	//var count C.uint
-	//C.SDL_Vulkan_GetInstanceExtensions(window.cptr(), &count, nil)
-	//if count == 0 {
-	//	return nil
-	//}
-	//
-	//strptrs := make([]*C.char, uint(count))
-	//C.SDL_Vulkan_GetInstanceExtensions(window.cptr(), &count, &strptrs[0])
-	//extensions := make([]string, uint(count))
-	//for i := range strptrs {
-	//	extensions[i] = C.GoString(strptrs[i])
-	//}
-	//return extensions
+	var count uint32
+	ret, _, _ := vulkan_GetInstanceExtensions.Call(
+		uintptr(unsafe.Pointer(window)),
+		uintptr(unsafe.Pointer(&count)),
+		0,
+	)
+	if ret == 0 || count == 0 {
+		return nil
+	}
+	names := make([]uintptr, count)
+	ret, _, _ = vulkan_GetInstanceExtensions.Call(
+		uintptr(unsafe.Pointer(window)),
+		uintptr(unsafe.Pointer(&count)),
+		uintptr(unsafe.Pointer(&names[0])),
+	)
+	if ret == 0 {
+		return nil
+	}
+	extensions := make([]string, count)
+	for i := range extensions {
+		extensions[i] = sdlToGoString(names[i])
+	}
+	return extensions
 }
 
 // WarpMouseInWindow moves the mouse to the given position within the window.
@@ -9553,6 +15282,139 @@ func (window *Window) WarpMouseInWindow(x, y int32) {
 	)
 }
 
+// WindowCanvas helps with software rendering to a window surface. It keeps
+// track of the areas that were drawn to since the last call to Present and
+// only copies those to the screen.
+type WindowCanvas struct {
+	window   *Window
+	surface  *Surface
+	w, h     int32
+	dirty    []Rect
+	allDirty bool
+}
+
+// maxDirtyRects is the number of separate dirty rectangles a WindowCanvas
+// tracks before it updates the whole window instead.
+const maxDirtyRects = 64
+
+// NewWindowCanvas returns a canvas drawing to the window's surface. The whole
+// window is updated on the first call to Present.
+func NewWindowCanvas(window *Window) (*WindowCanvas, error) {
+	surface, err := window.GetSurface()
+	if err != nil {
+		return nil, err
+	}
+	return &WindowCanvas{
+		window:   window,
+		surface:  surface,
+		w:        surface.W,
+		h:        surface.H,
+		allDirty: true,
+	}, nil
+}
+
+// Blit copies src onto the canvas and marks the destination as dirty. See
+// Surface.Blit for the meaning of the arguments.
+func (c *WindowCanvas) Blit(src *Surface, srcRect *Rect, dstRect *Rect) error {
+	var dst Rect
+	if dstRect != nil {
+		dst = *dstRect
+	}
+	if err := src.Blit(srcRect, c.surface, &dst); err != nil {
+		return err
+	}
+	// Blit writes the final blit rectangle back to dst.
+	c.MarkDirty(dst)
+	return nil
+}
+
+// FillRect fills the rect with the color, see Surface.FillRect, and marks it
+// as dirty. A nil rect fills the whole canvas.
+func (c *WindowCanvas) FillRect(rect *Rect, color uint32) error {
+	if err := c.surface.FillRect(rect, color); err != nil {
+		return err
+	}
+	if rect == nil {
+		c.MarkAllDirty()
+	} else {
+		c.MarkDirty(*rect)
+	}
+	return nil
+}
+
+// MarkAllDirty makes the next call to Present update the whole window.
+func (c *WindowCanvas) MarkAllDirty() {
+	c.allDirty = true
+	c.dirty = c.dirty[:0]
+}
+
+// MarkDirty adds r to the areas that are updated by the next call to Present.
+// Call it after drawing to the surface directly. Overlapping dirty rectangles
+// are merged.
+func (c *WindowCanvas) MarkDirty(r Rect) {
+	if c.allDirty {
+		return
+	}
+	bounds := Rect{W: c.w, H: c.h}
+	r, ok := r.Intersect(&bounds)
+	if !ok {
+		return
+	}
+	for i := 0; i < len(c.dirty); {
+		if r.HasIntersection(&c.dirty[i]) {
+			r = r.Union(&c.dirty[i])
+			c.dirty = append(c.dirty[:i], c.dirty[i+1:]...)
+			i = 0
+		} else {
+			i++
+		}
+	}
+	c.dirty = append(c.dirty, r)
+	if len(c.dirty) > maxDirtyRects {
+		c.MarkAllDirty()
+	}
+}
+
+// Present copies the dirty areas of the canvas to the screen. If the window
+// was resized, its new surface is fetched and all of it is updated.
+func (c *WindowCanvas) Present() error {
+	var err error
+	if c.allDirty {
+		err = c.window.UpdateSurface()
+	} else {
+		err = c.window.UpdateSurfaceRects(c.dirty)
+	}
+	c.allDirty = false
+	c.dirty = c.dirty[:0]
+	refreshErr := c.Refresh()
+	if err != nil {
+		return err
+	}
+	return refreshErr
+}
+
+// Refresh fetches the window surface again. It has to be called after the
+// window was resized and before drawing to the new surface. If the surface
+// changed, all of it is marked dirty.
+func (c *WindowCanvas) Refresh() error {
+	surface, err := c.window.GetSurface()
+	if err != nil {
+		return err
+	}
+	if surface != c.surface || surface.W != c.w || surface.H != c.h {
+		c.surface = surface
+		c.w, c.h = surface.W, surface.H
+		c.MarkAllDirty()
+	}
+	return nil
+}
+
+// Surface returns the window surface that the canvas draws to. Call
+// MarkDirty after changing its pixels directly.
+func (c *WindowCanvas) Surface() *Surface {
+	return c.surface
+}
+
 // WindowEvent contains window state change event data.
 // (https://wiki.libsdl.org/SDL_WindowEvent)
 type WindowEvent struct {
@@ -9607,14 +15469,66 @@ func GetYUVConversionModeForResolution(width, height int) YUV_CONVERSION_MODE {
 	return YUV_CONVERSION_MODE(ret)
 }
 
+// MaxStringLength is the maximum number of bytes that are read from strings
+// returned by SDL, e.g. window titles, clipboard text or device names. Longer
+// strings are cut off. This guards against reading arbitrary memory if a DLL
+// returns a string that is not zero-terminated.
+var MaxStringLength = 1 << 20
+
+// sdlToGoString converts a zero-terminated C string to a Go string. At most
+// MaxStringLength bytes are read and invalid UTF-8 sequences are replaced by
+// the Unicode replacement character.
 func sdlToGoString(p uintptr) string {
 	if p == 0 {
 		return ""
 	}
+	n := 0
+	for n < MaxStringLength && *(*byte)(unsafe.Pointer(p + uintptr(n))) != 0 {
+		n++
+	}
 	var buf []byte
-	for b := *((*byte)(unsafe.Pointer(p))); b != 0; b = *((*byte)(unsafe.Pointer(p))) {
-		buf = append(buf, b)
-		p++
+	sliceHeader := (*reflect.SliceHeader)(unsafe.Pointer(&buf))
+	sliceHeader.Cap = n
+	sliceHeader.Len = n
+	sliceHeader.Data = p
+	s := string(buf) // copies the bytes
+	if !utf8.ValidString(s) {
+		s = strings.ToValidUTF8(s, string(utf8.RuneError))
+	}
+	return s
+}
+
+// takeSDLString converts a string that SDL allocated for the caller and frees
+// it.
+func takeSDLString(p uintptr) string {
+	if p == 0 {
+		return ""
+	}
+	defer free.Call(p)
+	return sdlToGoString(p)
+}
+
+// goToSDLString returns s as a zero-terminated UTF-8 string for SDL. Invalid
+// UTF-8 sequences are replaced by utf8.RuneError and the string is cut off at
+// the first zero byte, where SDL would stop reading anyway.
+func goToSDLString(s string) []byte {
+	if i := strings.IndexByte(s, 0); i != -1 {
+		s = s[:i]
+	}
+	if !utf8.ValidString(s) {
+		s = strings.ToValidUTF8(s, string(utf8.RuneError))
+	}
+	return append([]byte(s), 0)
+}
+
+// newSDLString copies s into memory allocated by SDL, for strings that SDL
+// frees itself or that are freed with free. It returns nil if the memory
+// cannot be allocated.
+func newSDLString(s string) unsafe.Pointer {
+	b := goToSDLString(s)
+	p := Malloc(len(b))
+	if p != nil {
+		copy((*[1 << 30]byte)(p)[:len(b):len(b)], b)
 	}
-	return string(buf)
+	return p
 }
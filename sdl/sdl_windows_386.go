@@ -62,7 +62,7 @@ func (rwops *RWops) Seek(offset int64, whence int) (int64, error) {
 	}
 	a := uint32(offset)
 	b := uint32(offset >> 32)
-	ret, _, _ := syscall.Syscall6(
+	r1, r2, _ := syscall.Syscall6(
 		rwops.seek,
 		4,
 		uintptr(unsafe.Pointer(rwops)),
@@ -72,15 +72,19 @@ func (rwops *RWops) Seek(offset int64, whence int) (int64, error) {
 		0,
 		0,
 	)
-	if ret < 0 {
-		return int64(ret), GetError()
+	n := int64(uint64(r2)<<32 + uint64(r1))
+	if n < 0 {
+		return n, GetError()
 	}
-	return int64(ret), nil
+	return n, nil
 }
 
 // Size returns the size of the data stream in the RWops.
 // (https://wiki.libsdl.org/SDL_RWsize)
 func (rwops *RWops) Size() (int64, error) {
+	if rwops == nil {
+		return -1, ErrInvalidParameters
+	}
 	r1, r2, _ := syscall.Syscall(
 		rwops.size,
 		1,
@@ -150,7 +154,7 @@ func GameControllerMappingForGUID(guid JoystickGUID) string {
 		uintptr(*((*uint32)(unsafe.Pointer(&guid.data[8])))),
 		uintptr(*((*uint32)(unsafe.Pointer(&guid.data[12])))),
 	)
-	return sdlToGoString(ret)
+	return takeSDLString(ret)
 }
 
 // JoystickGetGUIDString returns an ASCII string representation for a given JoystickGUID.
@@ -189,7 +193,8 @@ func (renderer *Renderer) CopyEx(texture *Texture, src, dst *Rect, angle float64
 
 // CopyExF copies a portion of the texture to the current rendering target, optionally rotating it by angle around the given center and also flipping it top-bottom and/or left-right.
 // TODO: (https://wiki.libsdl.org/SDL_RenderCopyExF)
-func (renderer *Renderer) CopyExF(texture *Texture, src, dst *FRect, angle float64, center *FPoint, flip RendererFlip) error {
+// The source rectangle is in texture pixels, so src is a Rect, like in CopyEx.
+func (renderer *Renderer) CopyExF(texture *Texture, src *Rect, dst *FRect, angle float64, center *FPoint, flip RendererFlip) error {
 	angleBits := math.Float64bits(angle)
 	a := uint32(angleBits)
 	b := uint32(angleBits >> 32)
@@ -55,15 +55,19 @@ func (rwops *RWops) Seek(offset int64, whence int) (int64, error) {
 		uintptr(offset), // TODO what about 32 bit systems? a uintptr is only 32 bytes there
 		uintptr(whence),
 	)
-	if ret < 0 {
-		return int64(ret), GetError()
+	n := int64(ret)
+	if n < 0 {
+		return n, GetError()
 	}
-	return int64(ret), nil
+	return n, nil
 }
 
 // Size returns the size of the data stream in the RWops.
 // (https://wiki.libsdl.org/SDL_RWsize)
 func (rwops *RWops) Size() (int64, error) {
+	if rwops == nil {
+		return -1, ErrInvalidParameters
+	}
 	ret, _, _ := syscall.Syscall(
 		rwops.size,
 		1,
@@ -119,14 +123,10 @@ func LoadDollarTemplates(t TouchID, src *RWops) int {
 // given GUID.
 // (https://wiki.libsdl.org/SDL_GameControllerMappingForGUID)
 func GameControllerMappingForGUID(guid JoystickGUID) string {
-	// JoystickGUID contains
-	// 	data [16]byte
-	// that we need to pass in 64 bit uintptrs
-	ret, _, _ := gameControllerMappingForGUID.Call(
-		uintptr(*((*uint64)(unsafe.Pointer(&guid.data[0])))),
-		uintptr(*((*uint64)(unsafe.Pointer(&guid.data[8])))),
-	)
-	return sdlToGoString(ret)
+	// On 64 bit Windows, structs larger than 8 bytes are passed as a pointer to
+	// a copy, JoystickGUID has 16 bytes.
+	ret, _, _ := gameControllerMappingForGUID.Call(uintptr(unsafe.Pointer(&guid)))
+	return takeSDLString(ret)
 }
 
 // JoystickGetGUIDString returns an ASCII string representation for a given JoystickGUID.
@@ -134,8 +134,7 @@ func GameControllerMappingForGUID(guid JoystickGUID) string {
 func JoystickGetGUIDString(guid JoystickGUID) string {
 	buf := make([]byte, 1024)
 	joystickGetGUIDString.Call(
-		uintptr(*((*uint64)(unsafe.Pointer(&guid.data[0])))),
-		uintptr(*((*uint64)(unsafe.Pointer(&guid.data[8])))),
+		uintptr(unsafe.Pointer(&guid)),
 		uintptr(unsafe.Pointer(&buf[0])),
 		uintptr(len(buf)),
 	)
@@ -159,7 +158,8 @@ func (renderer *Renderer) CopyEx(texture *Texture, src, dst *Rect, angle float64
 
 // CopyExF copies a portion of the texture to the current rendering target, optionally rotating it by angle around the given center and also flipping it top-bottom and/or left-right.
 // TODO: (https://wiki.libsdl.org/SDL_RenderCopyExF)
-func (renderer *Renderer) CopyExF(texture *Texture, src, dst *FRect, angle float64, center *FPoint, flip RendererFlip) error {
+// The source rectangle is in texture pixels, so src is a Rect, like in CopyEx.
+func (renderer *Renderer) CopyExF(texture *Texture, src *Rect, dst *FRect, angle float64, center *FPoint, flip RendererFlip) error {
 	ret, _, _ := renderCopyExF.Call(
 		uintptr(unsafe.Pointer(renderer)),
 		uintptr(unsafe.Pointer(texture)),
@@ -0,0 +1,177 @@
+package sdl
+
+import (
+	"image"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"runtime/debug"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/gonutz/check"
+)
+
+func test(f func()) {
+	Main(func() {
+		Do(f)
+	})
+}
+
+func TestStackSummaryListsFunctionsBelowPanic(t *testing.T) {
+	stack := panicStack()
+	summary := stackSummary(stack, 2)
+	lines := strings.Split(strings.TrimSuffix(summary, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("want 2 functions with their files, got:\n%s", summary)
+	}
+	check.Eq(t, strings.HasSuffix(lines[0], "sdl.panicNow"), true)
+	check.Eq(t, strings.HasPrefix(lines[1], "\t"), true)
+	check.Eq(t, strings.Contains(lines[1], "sdl_windows_internal_test.go:"), true)
+	check.Eq(t, strings.Contains(lines[1], "+0x"), false)
+	check.Eq(t, strings.HasSuffix(lines[2], "sdl.panicStack"), true)
+	check.Eq(t, strings.HasPrefix(lines[3], "\t"), true)
+
+	check.Eq(t, stackSummary(stack, 0), "")
+	check.Eq(t, stackSummary(debug.Stack(), 5), "") // no panic in the stack
+}
+
+func TestReportPanicWritesCrashLog(t *testing.T) {
+	var title, message string
+	defer replacePanicMessage(&title, &message)()
+
+	reportPanic("something broke", panicStack())
+
+	check.Eq(t, title, "Error")
+	check.Eq(t, strings.Contains(message, "something broke"), true)
+	check.Eq(t, strings.Contains(message, "sdl.panicNow"), true)
+	logPath := crashLogPath(t, message)
+	defer os.Remove(logPath)
+	log, err := ioutil.ReadFile(logPath)
+	check.Eq(t, err, nil)
+	check.Eq(t, strings.Contains(string(log), "panic: something broke"), true)
+	check.Eq(t, strings.Contains(string(log), "sdl.panicNow"), true)
+}
+
+func TestHandlePanicReportsAndPanicsAgain(t *testing.T) {
+	var title, message string
+	defer replacePanicMessage(&title, &message)()
+
+	defer func() {
+		check.Eq(t, recover(), "handled")
+		check.Eq(t, strings.Contains(message, "handled"), true)
+		os.Remove(crashLogPath(t, message))
+	}()
+	func() {
+		defer HandlePanic()
+		panic("handled")
+	}()
+	t.Error("HandlePanic did not panic again")
+}
+
+func TestHandlePanicWithoutPanicDoesNothing(t *testing.T) {
+	shown := false
+	old := showPanicMessage
+	defer func() { showPanicMessage = old }()
+	showPanicMessage = func(string, string) { shown = true }
+
+	func() {
+		defer HandlePanic()
+	}()
+	check.Eq(t, shown, false)
+}
+
+// replacePanicMessage records the message box of reported panics instead of
+// showing it and returns a function to restore the original behavior.
+func replacePanicMessage(title, message *string) (restore func()) {
+	old := showPanicMessage
+	showPanicMessage = func(t, m string) {
+		*title = t
+		*message = m
+	}
+	return func() { showPanicMessage = old }
+}
+
+// crashLogPath returns the log file path mentioned in the panic message.
+func crashLogPath(t *testing.T, message string) string {
+	const prefix = "A crash log was written to\n"
+	i := strings.Index(message, prefix)
+	if i == -1 {
+		t.Fatalf("no crash log in message:\n%s", message)
+	}
+	return message[i+len(prefix):]
+}
+
+// panicStack returns the stack trace as seen in a deferred recover.
+func panicStack() (stack []byte) {
+	defer func() {
+		recover()
+		stack = debug.Stack()
+	}()
+	panicNow()
+	return nil
+}
+
+func panicNow() {
+	panic("test")
+}
+
+func TestSpriteBatchPathsDrawTheSamePixels(t *testing.T) {
+	test(func() {
+		renderer, surface, err := NewSoftwareRenderer(4, 2)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+
+		red := color.RGBA{R: 255, A: 255}
+		green := color.RGBA{G: 255, A: 255}
+		strip := image.NewRGBA(image.Rect(0, 0, 2, 1))
+		strip.SetRGBA(0, 0, red)
+		strip.SetRGBA(1, 0, green)
+		stripSurface, err := CreateRGBSurfaceWithFormatFrom(
+			unsafe.Pointer(&strip.Pix[0]), 2, 1, 32, int32(strip.Stride), PIXELFORMAT_ABGR8888,
+		)
+		check.Eq(t, err, nil)
+		defer stripSurface.Free()
+		texture, err := renderer.CreateTextureFromSurface(stripSurface)
+		check.Eq(t, err, nil)
+		defer texture.Destroy()
+
+		batch := NewSpriteBatch(renderer)
+		drawWith := func(flush func() error) *image.RGBA {
+			check.Eq(t, renderer.SetDrawColor(0, 0, 0, 255), nil)
+			check.Eq(t, renderer.Clear(), nil)
+			batch.Draw(texture, nil, Rect{X: 0, Y: 0, W: 2, H: 1}, 0, FLIP_NONE)
+			batch.Draw(texture, &Rect{X: 1, Y: 0, W: 1, H: 1}, Rect{X: 2, Y: 0, W: 2, H: 1}, 0, FLIP_NONE)
+			batch.Draw(texture, nil, Rect{X: 0, Y: 1, W: 2, H: 1}, 180, FLIP_NONE)
+			batch.Draw(texture, nil, Rect{X: 2, Y: 1, W: 2, H: 1}, 0, FLIP_HORIZONTAL)
+			check.Eq(t, flush(), nil)
+			batch.Clear()
+			renderer.Present()
+			img, err := surface.ToRGBA()
+			check.Eq(t, err, nil)
+			return img
+		}
+		checkPixels := func(img *image.RGBA) {
+			want := [2][4]color.RGBA{
+				{red, green, green, green},
+				{green, red, green, red},
+			}
+			for y := range want {
+				for x, c := range want[y] {
+					if img.RGBAAt(x, y) != c {
+						t.Errorf("pixel %d,%d is %v but should be %v", x, y, img.RGBAAt(x, y), c)
+					}
+				}
+			}
+		}
+
+		checkPixels(drawWith(batch.flushCopyEx))
+		if renderGeometry.Find() != nil {
+			t.Log("SDL_RenderGeometry is not available, only the CopyEx path was tested")
+			return
+		}
+		checkPixels(drawWith(batch.flushGeometry))
+	})
+}
@@ -1,11 +1,23 @@
 package sdl_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
 	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+	"unsafe"
 
 	"github.com/gonutz/check"
 	"github.com/gonutz/go-sdl2/sdl"
@@ -135,6 +147,159 @@ func TestBtoi(t *testing.T) {
 	check.Eq(t, sdl.Btoi(true), 1)
 }
 
+func TestGetBasePath(t *testing.T) {
+	test(func() {
+		path, err := sdl.GetBasePath()
+		check.Eq(t, err, nil)
+		check.Neq(t, path, "")
+		check.Eq(t, strings.HasSuffix(path, `\`), true)
+	})
+}
+
+func TestGetPrefPath(t *testing.T) {
+	test(func() {
+		org := "go-sdl2 test"
+		app := fmt.Sprintf("pref path %d", time.Now().UnixNano())
+		path, err := sdl.GetPrefPath(org, app)
+		check.Eq(t, err, nil)
+		defer os.Remove(filepath.Dir(path))
+		defer os.Remove(path)
+		check.Eq(t, strings.HasSuffix(path, `\`+app+`\`), true)
+		info, err := os.Stat(path)
+		check.Eq(t, err, nil)
+		check.Eq(t, info.IsDir(), true)
+	})
+}
+
+func TestLeakCheckCountsSDLAllocations(t *testing.T) {
+	outstanding := sdl.StartLeakCheck()
+	src := []byte("abc")
+	mem := sdl.Malloc(len(src))
+	check.Neq(t, mem, nil)
+	check.Eq(t, outstanding(), 1)
+	sdl.Memcpy(mem, unsafe.Pointer(&src[0]), len(src))
+	check.Eq(t, *(*[3]byte)(mem), [3]byte{'a', 'b', 'c'})
+	sdl.Free(mem)
+	check.Eq(t, outstanding(), 0)
+}
+
+type countingAllocator struct {
+	sdl.MemoryFunctions
+	mallocs int64
+}
+
+func (a *countingAllocator) Malloc(size uintptr) unsafe.Pointer {
+	atomic.AddInt64(&a.mallocs, 1)
+	return a.MemoryFunctions.Malloc(size)
+}
+
+func TestMemoryAllocatorCanWrapSDLFunctions(t *testing.T) {
+	original := sdl.GetMemoryFunctions()
+	check.Neq(t, original.MallocFunc, uintptr(0))
+	defer sdl.SetMemoryFunctions(original)
+
+	a := &countingAllocator{MemoryFunctions: original}
+	check.Eq(t, sdl.SetMemoryAllocator(a), nil)
+	sdl.Free(sdl.Malloc(8))
+	check.Eq(t, atomic.LoadInt64(&a.mallocs), int64(1))
+}
+
+func TestGetCPUFeatures(t *testing.T) {
+	f := sdl.GetCPUFeatures()
+	check.Eq(t, f.Count, sdl.GetCPUCount())
+	check.Eq(t, f.SIMDAlignment >= 16, true)
+	check.Eq(t, f.HasSSE2, sdl.HasSSE2())
+	check.Eq(t, f.HasNEON, false)
+	check.Eq(t, f.HasLSX, false)
+}
+
+func TestGetSystemInfo(t *testing.T) {
+	info := sdl.GetSystemInfo()
+	check.Eq(t, info.Platform, "Windows")
+	check.Eq(t, info.Version.Major, uint8(2))
+	check.Eq(t, info.CPUCount, sdl.GetCPUCount())
+	check.Eq(t, strings.HasPrefix(info.String(), "Platform: Windows\nSDL: 2."), true)
+
+	check.Eq(t, sdl.Locale{Language: "en", Country: "US"}.String(), "en_US")
+	check.Eq(t, sdl.Locale{Language: "de"}.String(), "de")
+}
+
+func TestJoystickGUIDSurvivesStringRoundTrip(t *testing.T) {
+	// The GUID is a 16 byte struct that is passed and returned by value, this
+	// checks the calling convention on both 386 and amd64.
+	const s = "030000005e0400008e02000000007801"
+	guid := sdl.JoystickGetGUIDFromString(s)
+	check.Eq(t, sdl.JoystickGetGUIDString(guid), s)
+	check.Neq(t, guid, sdl.JoystickGetGUIDFromString("03000000000000000000000000000001"))
+
+	test(func() {
+		check.Eq(t, sdl.Init(sdl.INIT_GAMECONTROLLER), nil)
+		defer sdl.Quit()
+		mapping := s + ",GUID Pad,a:b0,platform:Windows,"
+		check.Eq(t, sdl.GameControllerAddMapping(mapping) >= 0, true)
+		check.Eq(t, strings.HasPrefix(sdl.GameControllerMappingForGUID(guid), s+",GUID Pad,"), true)
+	})
+}
+
+func TestGameControllerMappingsCanBeSavedAndLoaded(t *testing.T) {
+	test(func() {
+		check.Eq(t, sdl.Init(sdl.INIT_GAMECONTROLLER), nil)
+		defer sdl.Quit()
+
+		// The mapping has no platform field, without one SDL would not load
+		// it from the saved file.
+		mapping := "03000000000000000000000000000001,Test Pad,a:b0,b:b1"
+		check.Eq(t, sdl.GameControllerAddMapping(mapping), 1)
+
+		var buf strings.Builder
+		check.Eq(t, sdl.GameControllerSaveMappings(&buf), nil)
+		check.Eq(t, strings.Contains(buf.String(), "Test Pad,a:b0,b:b1,platform:Windows,\n"), true)
+
+		// Restart SDL so the test mapping is gone and has to be loaded again.
+		sdl.Quit()
+		check.Eq(t, sdl.Init(sdl.INIT_GAMECONTROLLER), nil)
+		guid := sdl.JoystickGetGUIDFromString("03000000000000000000000000000001")
+		check.Eq(t, sdl.GameControllerMappingForGUID(guid), "")
+
+		rw, err := sdl.RWFromBytes([]byte(buf.String()))
+		check.Eq(t, err, nil)
+		defer rw.Close()
+		check.Eq(t, sdl.GameControllerAddMappingsFromRW(rw, false) > 0, true)
+		check.Eq(t, strings.Contains(sdl.GameControllerMappingForGUID(guid), "Test Pad"), true)
+	})
+}
+
+func TestHapticsListsAllDevices(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_HAPTIC); err != nil {
+			t.Skip("haptics not available: ", err)
+		}
+		defer sdl.Quit()
+
+		n, err := sdl.NumHaptics()
+		check.Eq(t, err, nil)
+		haptics := sdl.Haptics()
+		check.Eq(t, len(haptics), n)
+		for i, h := range haptics {
+			check.Eq(t, h.Index, i)
+		}
+	})
+}
+
+func TestButtonAndAxisLabels(t *testing.T) {
+	check.Eq(t, sdl.ButtonLabel(sdl.CONTROLLER_TYPE_XBOXONE, sdl.CONTROLLER_BUTTON_A), "A")
+	check.Eq(t, sdl.ButtonLabel(sdl.CONTROLLER_TYPE_PS4, sdl.CONTROLLER_BUTTON_A), "Cross")
+	check.Eq(t, sdl.ButtonLabel(sdl.CONTROLLER_TYPE_PS5, sdl.CONTROLLER_BUTTON_BACK), "Create")
+	check.Eq(t, sdl.ButtonLabel(sdl.CONTROLLER_TYPE_XBOX360, sdl.CONTROLLER_BUTTON_START), "Start")
+	check.Eq(t, sdl.ButtonLabel(sdl.CONTROLLER_TYPE_NINTENDO_SWITCH_PRO, sdl.CONTROLLER_BUTTON_START), "+")
+	check.Eq(t, sdl.ButtonLabel(sdl.CONTROLLER_TYPE_UNKNOWN, sdl.CONTROLLER_BUTTON_Y), "Y")
+	check.Eq(t, sdl.ButtonLabel(sdl.CONTROLLER_TYPE_PS4, sdl.CONTROLLER_BUTTON_MAX), "")
+
+	check.Eq(t, sdl.AxisLabel(sdl.CONTROLLER_TYPE_PS3, sdl.CONTROLLER_AXIS_TRIGGERLEFT), "L2")
+	check.Eq(t, sdl.AxisLabel(sdl.CONTROLLER_TYPE_NINTENDO_SWITCH_PRO, sdl.CONTROLLER_AXIS_TRIGGERRIGHT), "ZR")
+	check.Eq(t, sdl.AxisLabel(sdl.CONTROLLER_TYPE_XBOXONE, sdl.CONTROLLER_AXIS_LEFTX), "Left Stick X")
+}
+
 func TestRWFromMem(t *testing.T) {
 	rw, err := sdl.RWFromMem(make([]byte, 10))
 	check.Eq(t, err, nil)
@@ -143,6 +308,41 @@ func TestRWFromMem(t *testing.T) {
 	check.Eq(t, n, 10)
 }
 
+func TestRWFromBytesKeepsMemoryAlive(t *testing.T) {
+	rw, err := sdl.RWFromBytes([]byte("data"))
+	check.Eq(t, err, nil)
+	runtime.GC()
+	data, err := ioutil.ReadAll(rw)
+	check.Eq(t, err, nil)
+	check.Eq(t, string(data), "data")
+	check.Eq(t, rw.Close(), nil)
+
+	_, err = sdl.RWFromBytes(nil)
+	check.Neq(t, err, nil)
+}
+
+func TestWriteAndReadBMP(t *testing.T) {
+	test(func() {
+		surface, err := sdl.CreateRGBSurfaceWithFormat(0, 3, 2, 32, sdl.PIXELFORMAT_RGBA8888)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		surface.Set(2, 1, color.RGBA{R: 10, G: 20, B: 30, A: 255})
+
+		var buf strings.Builder
+		check.Eq(t, surface.WriteBMP(&buf), nil)
+		check.Eq(t, strings.HasPrefix(buf.String(), "BM"), true)
+
+		loaded, err := sdl.ReadBMP(strings.NewReader(buf.String()))
+		check.Eq(t, err, nil)
+		defer loaded.Free()
+		check.Eq(t, loaded.W, int32(3))
+		check.Eq(t, loaded.H, int32(2))
+
+		_, err = sdl.LoadBMP("this file does not exist.bmp")
+		check.Neq(t, err, nil)
+	})
+}
+
 func TestRWFromFileAndRWClose(t *testing.T) {
 	// create a test file
 	path := filepath.Join(os.Getenv("APPDATA"), "sdl_test_rw")
@@ -232,9 +432,34 @@ func TestRWReadWrite(t *testing.T) {
 	check.Eq(t, read2Buf[:], []byte("123456"))
 }
 
+func TestRWopsImplementsIOInterfaces(t *testing.T) {
+	rw, err := sdl.RWFromMem(make([]byte, 5))
+	check.Eq(t, err, nil)
+	var rwc interface {
+		io.ReadWriteSeeker
+		io.Closer
+	} = rw
+
+	n, err := rwc.Write([]byte("abcdefg"))
+	check.Eq(t, n, 5)
+	check.Neq(t, err, nil)
+
+	pos, err := rwc.Seek(-2, io.SeekEnd)
+	check.Eq(t, err, nil)
+	check.Eq(t, pos, 3)
+	data, err := ioutil.ReadAll(rwc)
+	check.Eq(t, err, nil)
+	check.Eq(t, data, []byte("de"))
+
+	n, err = rwc.Read(make([]byte, 1))
+	check.Eq(t, n, 0)
+	check.Eq(t, err, io.EOF)
+	check.Eq(t, rwc.Close(), nil)
+}
+
 func TestLog(t *testing.T) {
 	var x []interface{}
-	f := func(data interface{}, category int, pri sdl.LogPriority, message string) {
+	f := func(data interface{}, category sdl.LogCategory, pri sdl.LogPriority, message string) {
 		x = append(x, data, category, pri, message)
 	}
 
@@ -354,3 +579,2070 @@ func TestBytesPerPixel(t *testing.T) {
 	check.Eq(t, sdl.BytesPerPixel(sdl.PIXELFORMAT_BGRA8888), 4)
 	check.Eq(t, sdl.BytesPerPixel(sdl.PIXELFORMAT_YUY2), 2)
 }
+
+func TestPixelFormatEnum(t *testing.T) {
+	rgba := sdl.PixelFormatEnum(sdl.PIXELFORMAT_RGBA8888)
+	check.Eq(t, rgba.BitsPerPixel(), 32)
+	check.Eq(t, rgba.BytesPerPixel(), 4)
+	check.Eq(t, rgba.IsAlpha(), true)
+	check.Eq(t, rgba.IsIndexed(), false)
+	check.Eq(t, rgba.IsFourCC(), false)
+	check.Eq(t, rgba.String(), "PIXELFORMAT_RGBA8888")
+
+	rgb := sdl.PixelFormatEnum(sdl.PIXELFORMAT_RGB888)
+	check.Eq(t, rgb.IsAlpha(), false)
+	check.Eq(t, rgb.BitsPerPixel(), 24)
+
+	index8 := sdl.PixelFormatEnum(sdl.PIXELFORMAT_INDEX8)
+	check.Eq(t, index8.IsIndexed(), true)
+	check.Eq(t, index8.IsAlpha(), false)
+
+	yuy2 := sdl.PixelFormatEnum(sdl.PIXELFORMAT_YUY2)
+	check.Eq(t, yuy2.IsFourCC(), true)
+	check.Eq(t, yuy2.IsIndexed(), false)
+	check.Eq(t, yuy2.BytesPerPixel(), 2)
+
+	check.Eq(t, sdl.PixelFormatEnum(sdl.PIXELFORMAT_UNKNOWN).IsFourCC(), false)
+	check.Eq(t, sdl.PixelFormatEnum(0x12345678).String(), "PixelFormatEnum(0x12345678)")
+}
+
+func TestFRectIntersectAndUnion(t *testing.T) {
+	a := sdl.FRect{X: 0, Y: 0, W: 10, H: 10}
+	b := sdl.FRect{X: 5, Y: 2.5, W: 10, H: 5}
+	check.Eq(t, a.HasIntersection(&b), true)
+	i, ok := a.Intersect(&b)
+	check.Eq(t, ok, true)
+	check.Eq(t, i, sdl.FRect{X: 5, Y: 2.5, W: 5, H: 5})
+	check.Eq(t, a.Union(&b), sdl.FRect{X: 0, Y: 0, W: 15, H: 10})
+
+	c := sdl.FRect{X: 10, Y: 0, W: 1, H: 1}
+	check.Eq(t, a.HasIntersection(&c), false)
+	_, ok = a.Intersect(&c)
+	check.Eq(t, ok, false)
+
+	check.Eq(t, a.Contains(sdl.FPoint{X: 9.5, Y: 0}), true)
+	check.Eq(t, a.Contains(sdl.FPoint{X: 10, Y: 0}), false)
+
+	x1, y1, x2, y2 := float32(-5), float32(5), float32(15), float32(5)
+	check.Eq(t, a.IntersectLine(&x1, &y1, &x2, &y2), true)
+	check.Eq(t, []float32{x1, y1, x2, y2}, []float32{0, 5, 10, 5})
+}
+
+func TestVolumeClamp(t *testing.T) {
+	check.Eq(t, sdl.Volume(-1).Clamp(), sdl.Volume(0))
+	check.Eq(t, sdl.Volume(64).Clamp(), sdl.Volume(64))
+	check.Eq(t, sdl.Volume(200).Clamp(), sdl.Volume(sdl.MIX_MAXVOLUME))
+}
+
+func TestCallErrorIsComparableToSentinels(t *testing.T) {
+	err := error(&sdl.CallError{Func: "Renderer.Clear", Msg: "Invalid renderer"})
+	check.Eq(t, err.Error(), "Renderer.Clear: Invalid renderer")
+	check.Eq(t, errors.Is(err, sdl.ErrInvalidRenderer), true)
+	check.Eq(t, errors.Is(err, sdl.ErrUnsupported), false)
+}
+
+func TestPixelFormatColorAt(t *testing.T) {
+	test(func() {
+		rgba, err := sdl.AllocFormat(sdl.PIXELFORMAT_RGBA8888)
+		check.Eq(t, err, nil)
+		defer rgba.Free()
+		check.Eq(t, rgba.ColorAt(0x11223344), color.RGBA{R: 0x11, G: 0x22, B: 0x33, A: 0x44})
+		r, g, b := sdl.GetRGB(0x11223344, rgba)
+		check.Eq(t, []uint8{r, g, b}, []uint8{0x11, 0x22, 0x33})
+
+		argb, err := sdl.AllocFormat(sdl.PIXELFORMAT_ARGB8888)
+		check.Eq(t, err, nil)
+		defer argb.Free()
+		check.Eq(t, argb.ColorAt(0x11223344), color.RGBA{R: 0x22, G: 0x33, B: 0x44, A: 0x11})
+
+		rgb565, err := sdl.AllocFormat(sdl.PIXELFORMAT_RGB565)
+		check.Eq(t, err, nil)
+		defer rgb565.Free()
+		check.Eq(t, rgb565.ColorAt(0xF800), color.RGBA{R: 0xFF, G: 0, B: 0, A: 0xFF})
+		check.Eq(t, rgb565.ColorAt(sdl.MapRGB(rgb565, 0, 0xFF, 0)), color.RGBA{R: 0, G: 0xFF, B: 0, A: 0xFF})
+	})
+}
+
+func TestOpenDefaultAudioDeviceWithoutCallback(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_AUDIO); err != nil {
+			t.Skip("audio not available:", err)
+		}
+		defer sdl.Quit()
+		if sdl.GetNumAudioDevices(false) <= 0 {
+			t.Skip("no audio output device")
+		}
+
+		desired := sdl.AudioSpec{
+			Freq:     44100,
+			Format:   sdl.AUDIO_S16SYS,
+			Channels: 2,
+			Samples:  1024,
+		}
+		var obtained sdl.AudioSpec
+		dev, err := sdl.OpenAudioDevice("", false, &desired, &obtained, 0)
+		check.Eq(t, err, nil)
+		check.Eq(t, obtained.Freq, int32(44100))
+		check.Eq(t, obtained.Format, sdl.AudioFormat(sdl.AUDIO_S16SYS))
+		check.Eq(t, obtained.Channels, uint8(2))
+		check.Eq(t, sdl.QueueAudio(dev, make([]byte, 4*1024)), nil)
+		check.Eq(t, sdl.GetQueuedAudioSize(dev), uint32(4*1024))
+		sdl.CloseAudioDevice(dev)
+
+		// obtained may be nil
+		dev, err = sdl.OpenAudioDevice("", false, &desired, nil, 0)
+		check.Eq(t, err, nil)
+		sdl.CloseAudioDevice(dev)
+	})
+}
+
+func TestColorIsNotPremultiplied(t *testing.T) {
+	r, g, b, a := sdl.Color{R: 255, G: 128, B: 0, A: 128}.RGBA()
+	check.Eq(t, [4]uint32{r, g, b, a}, [4]uint32{0x8080, 0x4080, 0, 0x8080})
+}
+
+func TestRendererDrawColorStack(t *testing.T) {
+	test(func() {
+		surface, err := sdl.CreateRGBSurfaceWithFormat(0, 4, 4, 32, sdl.PIXELFORMAT_RGBA8888)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		renderer, err := sdl.CreateSoftwareRenderer(surface)
+		check.Eq(t, err, nil)
+		defer renderer.Destroy()
+
+		red := color.RGBA{R: 255, A: 255}
+		check.Eq(t, renderer.SetColor(red), nil)
+		check.Eq(t, renderer.PushDrawColor(color.Gray{Y: 10}), nil)
+		c, err := renderer.GetColor()
+		check.Eq(t, err, nil)
+		check.Eq(t, c, sdl.Color{R: 10, G: 10, B: 10, A: 255})
+		check.Eq(t, renderer.PopDrawColor(), nil)
+		c, _ = renderer.GetColor()
+		check.Eq(t, c, sdl.Color(red))
+		check.Neq(t, renderer.PopDrawColor(), nil)
+	})
+}
+
+func TestRendererStateStack(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(8, 8)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+
+		type state struct {
+			r, g, b, a uint8
+			blendMode  sdl.BlendMode
+			viewport   sdl.Rect
+			clipRect   sdl.Rect
+		}
+		get := func() state {
+			var s state
+			var err error
+			s.r, s.g, s.b, s.a, err = renderer.GetDrawColor()
+			check.Eq(t, err, nil)
+			check.Eq(t, renderer.GetDrawBlendMode(&s.blendMode), nil)
+			s.viewport = renderer.GetViewport()
+			s.clipRect = renderer.GetClipRect()
+			return s
+		}
+
+		check.Eq(t, renderer.SetDrawColor(1, 2, 3, 4), nil)
+		check.Eq(t, renderer.SetDrawBlendMode(sdl.BLENDMODE_NONE), nil)
+		outer := get()
+		check.Eq(t, outer.clipRect.Empty(), true)
+
+		check.Eq(t, renderer.PushState(), nil)
+		check.Eq(t, renderer.SetDrawColor(5, 6, 7, 8), nil)
+		check.Eq(t, renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND), nil)
+		check.Eq(t, renderer.SetViewport(&sdl.Rect{X: 1, Y: 1, W: 6, H: 6}), nil)
+		check.Eq(t, renderer.SetClipRect(&sdl.Rect{X: 0, Y: 0, W: 3, H: 3}), nil)
+		inner := get()
+		check.Neq(t, inner, outer)
+
+		check.Eq(t, renderer.PushState(), nil)
+		check.Eq(t, renderer.SetDrawColor(9, 9, 9, 9), nil)
+		check.Eq(t, renderer.SetDrawBlendMode(sdl.BLENDMODE_ADD), nil)
+		check.Eq(t, renderer.SetViewport(&sdl.Rect{X: 2, Y: 2, W: 2, H: 2}), nil)
+		check.Eq(t, renderer.SetClipRect(nil), nil)
+		check.Eq(t, renderer.PopState(), nil)
+		check.Eq(t, get(), inner)
+
+		check.Eq(t, renderer.PopState(), nil)
+		check.Eq(t, get(), outer)
+
+		// Popping an empty stack is an error and changes nothing.
+		check.Neq(t, renderer.PopState(), nil)
+		check.Eq(t, get(), outer)
+	})
+}
+
+func TestTextureTint(t *testing.T) {
+	test(func() {
+		surface, err := sdl.CreateRGBSurfaceWithFormat(0, 4, 4, 32, sdl.PIXELFORMAT_RGBA8888)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		renderer, err := sdl.CreateSoftwareRenderer(surface)
+		check.Eq(t, err, nil)
+		defer renderer.Destroy()
+		texture, err := renderer.CreateTexture(sdl.PIXELFORMAT_RGBA8888, sdl.TEXTUREACCESS_STATIC, 2, 2)
+		check.Eq(t, err, nil)
+		defer texture.Destroy()
+
+		check.Eq(t, texture.SetTint(color.NRGBA{R: 1, G: 2, B: 3, A: 4}), nil)
+		r, g, b, err := texture.GetColorMod()
+		check.Eq(t, err, nil)
+		check.Eq(t, [3]uint8{r, g, b}, [3]uint8{1, 2, 3})
+		tint, err := texture.GetTint()
+		check.Eq(t, err, nil)
+		check.Eq(t, tint, sdl.Color{R: 1, G: 2, B: 3, A: 4})
+	})
+}
+
+func TestLockTextureRect(t *testing.T) {
+	test(func() {
+		surface, err := sdl.CreateRGBSurfaceWithFormat(0, 4, 4, 32, sdl.PIXELFORMAT_RGBA8888)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		renderer, err := sdl.CreateSoftwareRenderer(surface)
+		check.Eq(t, err, nil)
+		defer renderer.Destroy()
+		texture, err := renderer.CreateTexture(sdl.PIXELFORMAT_RGBA8888, sdl.TEXTUREACCESS_STREAMING, 4, 4)
+		check.Eq(t, err, nil)
+		defer texture.Destroy()
+
+		pixels, pitch, err := texture.Lock(nil)
+		check.Eq(t, err, nil)
+		check.Eq(t, len(pixels), 4*pitch)
+		texture.Unlock()
+
+		pixels, pitch, err = texture.Lock(&sdl.Rect{X: 1, Y: 1, W: 2, H: 2})
+		check.Eq(t, err, nil)
+		check.Eq(t, len(pixels), pitch+2*4)
+		texture.Unlock()
+	})
+}
+
+func TestTextureInfo(t *testing.T) {
+	test(func() {
+		surface, err := sdl.CreateRGBSurfaceWithFormat(0, 4, 4, 32, sdl.PIXELFORMAT_RGBA8888)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		renderer, err := sdl.CreateSoftwareRenderer(surface)
+		check.Eq(t, err, nil)
+		defer renderer.Destroy()
+		texture, err := renderer.CreateTexture(sdl.PIXELFORMAT_ARGB8888, sdl.TEXTUREACCESS_STREAMING, 3, 5)
+		check.Eq(t, err, nil)
+		defer texture.Destroy()
+
+		info, err := texture.Info()
+		check.Eq(t, err, nil)
+		check.Eq(t, info, sdl.TextureInfo{
+			Format: sdl.PIXELFORMAT_ARGB8888,
+			Access: sdl.TEXTUREACCESS_STREAMING,
+			W:      3,
+			H:      5,
+		})
+	})
+}
+
+func TestWindowRegistry(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+
+		a, err := sdl.CreateWindow("a", 0, 0, 10, 10, sdl.WINDOW_HIDDEN)
+		check.Eq(t, err, nil)
+		b, err := sdl.CreateWindow("b", 0, 0, 10, 10, sdl.WINDOW_HIDDEN)
+		check.Eq(t, err, nil)
+		check.Eq(t, sdl.Windows(), []*sdl.Window{a, b})
+
+		events, err := a.Events()
+		check.Eq(t, err, nil)
+		check.Eq(t, a.Destroy(), nil)
+		check.Eq(t, sdl.Windows(), []*sdl.Window{b})
+		_, open := <-events
+		check.Eq(t, open, false)
+		check.Eq(t, b.Destroy(), nil)
+		check.Eq(t, len(sdl.Windows()), 0)
+	})
+}
+
+func TestWindowEventsReceiveACopy(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+
+		window, err := sdl.CreateWindow("", 0, 0, 10, 10, sdl.WINDOW_HIDDEN)
+		check.Eq(t, err, nil)
+		defer window.Destroy()
+		id, err := window.GetID()
+		check.Eq(t, err, nil)
+		events, err := window.Events()
+		check.Eq(t, err, nil)
+
+		_, err = sdl.PushEvent(&sdl.UserEvent{Type: sdl.USEREVENT, WindowID: id, Code: 7})
+		check.Eq(t, err, nil)
+		var polled *sdl.UserEvent
+		for e := sdl.PollEvent(); e != nil; e = sdl.PollEvent() {
+			if u, ok := e.(*sdl.UserEvent); ok {
+				polled = u
+			}
+		}
+		if polled == nil {
+			t.Error("the user event was not polled")
+			return
+		}
+		var routed *sdl.UserEvent
+		for len(events) > 0 {
+			if u, ok := (<-events).(*sdl.UserEvent); ok {
+				routed = u
+			}
+		}
+		if routed == nil {
+			t.Error("the user event was not routed to the window")
+			return
+		}
+		check.Eq(t, routed, polled)
+		polled.Code = 8
+		check.Eq(t, routed.Code, int32(7))
+	})
+}
+
+func TestInhibitScreensaverIsRefCounted(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+
+		sdl.EnableScreenSaver()
+		release1 := sdl.InhibitScreensaver()
+		release2 := sdl.InhibitScreensaver()
+		check.Eq(t, sdl.IsScreenSaverEnabled(), false)
+		release1()
+		release1()
+		check.Eq(t, sdl.IsScreenSaverEnabled(), false)
+		release2()
+		check.Eq(t, sdl.IsScreenSaverEnabled(), true)
+	})
+}
+
+func TestRunEventLoopStopsWhenContextIsDone(t *testing.T) {
+	test(func() {
+		check.Eq(t, sdl.Init(sdl.INIT_EVENTS), nil)
+		defer sdl.Quit()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var frames []time.Duration
+		sdl.RunEventLoop(ctx, nil, func(dt time.Duration) {
+			frames = append(frames, dt)
+			if len(frames) == 3 {
+				cancel()
+			}
+		})
+		check.Eq(t, frames, []time.Duration{
+			sdl.EventLoopTimestep,
+			sdl.EventLoopTimestep,
+			sdl.EventLoopTimestep,
+		})
+	})
+}
+
+func TestClipboardImageRoundTrip(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+		window, err := sdl.CreateWindow("", 0, 0, 10, 10, sdl.WINDOW_HIDDEN)
+		check.Eq(t, err, nil)
+		defer window.Destroy()
+
+		img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+		img.SetNRGBA(0, 0, color.NRGBA{R: 255, A: 255})
+		img.SetNRGBA(1, 0, color.NRGBA{B: 255, A: 128})
+		check.Eq(t, sdl.SetClipboardImage(window, img), nil)
+
+		got, err := sdl.GetClipboardImage(window)
+		check.Eq(t, err, nil)
+		check.Eq(t, got.Bounds(), img.Bounds())
+		check.Eq(t, color.NRGBAModel.Convert(got.At(0, 0)), img.At(0, 0))
+		check.Eq(t, color.NRGBAModel.Convert(got.At(1, 0)), img.At(1, 0))
+	})
+}
+
+func TestGameControllerButtonBindDecodesLittleEndianInts(t *testing.T) {
+	// bind type, then the union of button/axis/hat and the hat mask
+	raw := [12]byte{
+		sdl.CONTROLLER_BINDTYPE_HAT, 0, 0, 0,
+		0x2C, 0x01, 0, 0,
+		0x08, 0, 0, 0,
+	}
+	bind := (*sdl.GameControllerButtonBind)(unsafe.Pointer(&raw[0]))
+	check.Eq(t, bind.Type(), sdl.CONTROLLER_BINDTYPE_HAT)
+	check.Eq(t, bind.Hat(), 300)
+	check.Eq(t, bind.HatMask(), sdl.HAT_LEFT)
+
+	raw = [12]byte{
+		sdl.CONTROLLER_BINDTYPE_AXIS, 0, 0, 0,
+		0xFF, 0xFF, 0xFF, 0xFF,
+	}
+	check.Eq(t, bind.Type(), sdl.CONTROLLER_BINDTYPE_AXIS)
+	check.Eq(t, bind.Axis(), -1)
+
+	raw = [12]byte{
+		sdl.CONTROLLER_BINDTYPE_BUTTON, 0, 0, 0,
+		7, 0, 0, 0,
+	}
+	check.Eq(t, bind.Button(), 7)
+}
+
+func TestLogCategoryAndPriorityStrings(t *testing.T) {
+	check.Eq(t, sdl.LOG_CATEGORY_VIDEO.String(), "video")
+	check.Eq(t, sdl.LOG_CATEGORY_RESERVED2.String(), "reserved2")
+	check.Eq(t, sdl.LOG_CATEGORY_CUSTOM.String(), "custom")
+	check.Eq(t, (sdl.LOG_CATEGORY_CUSTOM + 3).String(), "custom+3")
+	check.Eq(t, sdl.LogCategory(-1).String(), "LogCategory(-1)")
+	check.Eq(t, sdl.LOG_PRIORITY_WARN.String(), "warn")
+	check.Eq(t, sdl.LogPriority(0).String(), "LogPriority(0)")
+}
+
+func TestLogSetOutputWriter(t *testing.T) {
+	test(func() {
+		var buf strings.Builder
+		sdl.LogSetOutputWriter(&buf)
+		defer sdl.LogResetOutputFunction()
+		sdl.LogWarn(sdl.LOG_CATEGORY_VIDEO, "%d windows", 2)
+		check.Eq(t, buf.String(), "[video] WARN: 2 windows\n")
+
+		sdl.LogResetOutputFunction()
+		f, _ := sdl.LogGetOutputFunction()
+		check.Eq(t, f == nil, true)
+		sdl.LogWarn(sdl.LOG_CATEGORY_VIDEO, "not written to buf")
+		check.Eq(t, buf.String(), "[video] WARN: 2 windows\n")
+	})
+}
+
+func TestCallChecksCatchNilReceiversAndUseAfterFree(t *testing.T) {
+	test(func() {
+		sdl.EnableCallChecks(true)
+		defer sdl.EnableCallChecks(false)
+
+		callCheckError := func(f func()) (err *sdl.CallCheckError) {
+			defer func() {
+				err, _ = recover().(*sdl.CallCheckError)
+			}()
+			f()
+			return nil
+		}
+
+		var nilTexture *sdl.Texture
+		err := callCheckError(func() { nilTexture.Destroy() })
+		check.Neq(t, err, nil)
+		check.Eq(t, err.Func, "SDL_DestroyTexture")
+		check.Eq(t, err.Method, "Texture.Destroy")
+		check.Eq(t, strings.Contains(err.Caller, "sdl_windows_test.go"), true)
+
+		surface, e := sdl.CreateRGBSurfaceWithFormat(0, 4, 4, 32, sdl.PIXELFORMAT_RGBA8888)
+		check.Eq(t, e, nil)
+		surface.Free()
+		err = callCheckError(func() { surface.FillRect(nil, 0) })
+		check.Neq(t, err, nil)
+		check.Eq(t, err.Reason, "argument 1 was already freed by SDL_FreeSurface")
+	})
+}
+
+func TestSurfaceHasColorKey(t *testing.T) {
+	test(func() {
+		surface, err := sdl.CreateRGBSurfaceWithFormat(0, 4, 4, 32, sdl.PIXELFORMAT_RGBA8888)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		check.Eq(t, surface.HasColorKey(), false)
+		check.Eq(t, surface.SetColorKey(true, 0), nil)
+		check.Eq(t, surface.HasColorKey(), true)
+	})
+}
+
+func TestWindowCenterOnDisplay(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+		window, err := sdl.CreateWindow("", 0, 0, 100, 50, sdl.WINDOW_HIDDEN|sdl.WINDOW_BORDERLESS)
+		check.Eq(t, err, nil)
+		defer window.Destroy()
+
+		bounds, err := sdl.GetDisplayBounds(0)
+		check.Eq(t, err, nil)
+		check.Eq(t, window.CenterOn(0), nil)
+		x, y := window.GetPosition()
+		check.Eq(t, x, bounds.X+int32(math.Round(float64(bounds.W-100)/2)))
+		check.Eq(t, y, bounds.Y+int32(math.Round(float64(bounds.H-50)/2)))
+
+		check.Eq(t, window.MoveToDisplay(0), nil)
+		x2, y2 := window.GetPosition()
+		check.Eq(t, x2, x)
+		check.Eq(t, y2, y)
+	})
+}
+
+func TestOnQuitCanCancelQuitEvents(t *testing.T) {
+	test(func() {
+		check.Eq(t, sdl.Init(sdl.INIT_EVENTS), nil)
+		defer sdl.Quit()
+
+		quitEvents := func() int {
+			n := 0
+			for e := sdl.PollEvent(); e != nil; e = sdl.PollEvent() {
+				if _, ok := e.(*sdl.QuitEvent); ok {
+					n++
+				}
+			}
+			return n
+		}
+		pushQuit := func() {
+			var e sdl.QuitEvent
+			e.Type = sdl.QUIT
+			_, err := sdl.PushEvent(&e)
+			check.Eq(t, err, nil)
+		}
+
+		calls := 0
+		allowQuit := false
+		sdl.OnQuit(func() bool {
+			calls++
+			return allowQuit
+		})
+		defer sdl.OnQuit(nil)
+
+		pushQuit()
+		check.Eq(t, quitEvents(), 0)
+		check.Eq(t, calls, 1)
+
+		allowQuit = true
+		pushQuit()
+		check.Eq(t, quitEvents(), 1)
+		check.Eq(t, calls, 2)
+	})
+}
+
+func TestFPSManagerLimitsFrameRate(t *testing.T) {
+	test(func() {
+		fps := sdl.NewFPSManager()
+		check.Eq(t, fps.GetRate(), sdl.FPS_DEFAULT)
+		check.Neq(t, fps.SetRate(0), nil)
+		check.Neq(t, fps.SetRate(sdl.FPS_UPPER_LIMIT+1), nil)
+		check.Eq(t, fps.SetRate(100), nil)
+
+		start := time.Now()
+		for i := 0; i < 10; i++ {
+			fps.Delay()
+		}
+		check.Eq(t, time.Since(start) >= 95*time.Millisecond, true)
+		check.Eq(t, fps.GetFrameCount(), 10)
+		check.Eq(t, fps.AverageFPS() <= 101, true)
+	})
+}
+
+func TestZeroFPSManagerUsesDefaultRate(t *testing.T) {
+	test(func() {
+		var fps sdl.FPSManager
+		check.Eq(t, fps.GetRate(), sdl.FPS_DEFAULT)
+		check.Eq(t, fps.AverageFPS(), 0.0)
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			fps.Delay()
+		}
+		// 3 frames at 30 FPS take 100ms.
+		check.Eq(t, time.Since(start) >= 95*time.Millisecond, true)
+		check.Eq(t, fps.GetFrameCount(), 3)
+		check.Eq(t, fps.AverageFPS() <= sdl.FPS_DEFAULT+1, true)
+	})
+}
+
+func TestCondWaitContextReturnsWhenContextIsDone(t *testing.T) {
+	test(func() {
+		mutex, err := sdl.CreateMutex()
+		check.Eq(t, err, nil)
+		defer mutex.Destroy()
+		cond := sdl.CreateCond()
+		defer cond.Destroy()
+
+		var locker sync.Locker = mutex.Locker()
+		locker.Lock()
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+		check.Eq(t, cond.WaitContext(ctx, mutex), context.DeadlineExceeded)
+		locker.Unlock()
+	})
+}
+
+func TestSemaphoreChan(t *testing.T) {
+	test(func() {
+		sem, err := sdl.CreateSemaphore(0)
+		check.Eq(t, err, nil)
+		defer sem.Destroy()
+
+		ok, err := sem.TryWaitTimeout(10 * time.Millisecond)
+		check.Eq(t, err, nil)
+		check.Eq(t, ok, false)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		c := sem.Chan(ctx)
+		check.Eq(t, sem.Post(), nil)
+		select {
+		case <-c:
+		case <-time.After(time.Second):
+			t.Fatal("semaphore channel did not receive")
+		}
+		cancel()
+		_, open := <-c
+		check.Eq(t, open, false)
+		check.Eq(t, sem.Value(), uint32(0))
+	})
+}
+
+func TestStopwatch(t *testing.T) {
+	test(func() {
+		var s sdl.Stopwatch
+		check.Eq(t, s.Running(), false)
+		check.Eq(t, s.Elapsed(), time.Duration(0))
+
+		s.Start()
+		time.Sleep(20 * time.Millisecond)
+		s.Stop()
+		elapsed := s.Elapsed()
+		check.Eq(t, elapsed >= 15*time.Millisecond, true)
+		time.Sleep(20 * time.Millisecond)
+		check.Eq(t, s.Elapsed(), elapsed)
+
+		check.Eq(t, s.Lap(), elapsed)
+		check.Eq(t, s.Running(), true)
+		check.Eq(t, s.Elapsed() < elapsed, true)
+
+		s.Reset()
+		check.Eq(t, s.Running(), false)
+		check.Eq(t, s.Elapsed(), time.Duration(0))
+	})
+}
+
+func TestProfilerSumsScopes(t *testing.T) {
+	test(func() {
+		var p sdl.Profiler
+		for i := 0; i < 3; i++ {
+			end := p.Scope("slow")
+			time.Sleep(5 * time.Millisecond)
+			end()
+		}
+		p.Scope("fast")()
+
+		results := p.Results()
+		check.Eq(t, len(results), 2)
+		check.Eq(t, results[0].Name, "slow")
+		check.Eq(t, results[0].Count, 3)
+		check.Eq(t, results[0].Total >= 15*time.Millisecond, true)
+		check.Eq(t, results[0].Max <= results[0].Total, true)
+		check.Eq(t, results[1].Name, "fast")
+		check.Eq(t, results[1].Count, 1)
+
+		p.Reset()
+		check.Eq(t, len(p.Results()), 0)
+	})
+}
+
+func TestRendererWithTargetRestoresTargetOnPanic(t *testing.T) {
+	test(func() {
+		surface, err := sdl.CreateRGBSurfaceWithFormat(0, 4, 4, 32, sdl.PIXELFORMAT_RGBA8888)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		renderer, err := sdl.CreateSoftwareRenderer(surface)
+		check.Eq(t, err, nil)
+		defer renderer.Destroy()
+		texture, err := renderer.CreateTexture(sdl.PIXELFORMAT_RGBA8888, sdl.TEXTUREACCESS_TARGET, 2, 2)
+		check.Eq(t, err, nil)
+		defer texture.Destroy()
+
+		var target *sdl.Texture
+		check.Eq(t, renderer.WithTarget(texture, func() {
+			target = renderer.GetRenderTarget()
+		}), nil)
+		check.Eq(t, target, texture)
+		check.Eq(t, renderer.GetRenderTarget(), (*sdl.Texture)(nil))
+
+		func() {
+			defer func() { recover() }()
+			renderer.WithTarget(texture, func() { panic("fail") })
+		}()
+		check.Eq(t, renderer.GetRenderTarget(), (*sdl.Texture)(nil))
+		check.Neq(t, renderer.PopTarget(), nil)
+	})
+}
+
+func TestLetterboxMapsBothWays(t *testing.T) {
+	pillarbox := sdl.NewLetterbox(1000, 500, 200, 200)
+	check.Eq(t, pillarbox.Scale, float32(2.5))
+	check.Eq(t, pillarbox.Viewport, sdl.Rect{X: 250, Y: 0, W: 500, H: 500})
+
+	letterbox := sdl.NewLetterbox(400, 600, 200, 100)
+	check.Eq(t, letterbox.Scale, float32(2))
+	check.Eq(t, letterbox.Viewport, sdl.Rect{X: 0, Y: 200, W: 400, H: 200})
+
+	x, y, inside := letterbox.ToLogical(100, 300)
+	check.Eq(t, x, float32(50))
+	check.Eq(t, y, float32(50))
+	check.Eq(t, inside, true)
+	_, _, inside = letterbox.ToLogical(100, 100)
+	check.Eq(t, inside, false)
+
+	outX, outY := letterbox.ToOutput(50, 50)
+	check.Eq(t, outX, int32(100))
+	check.Eq(t, outY, int32(300))
+}
+
+func TestAudioDevicesMatchIndexFunctions(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_AUDIO); err != nil {
+			t.Skip("audio not available:", err)
+		}
+		defer sdl.Quit()
+
+		devices := sdl.AudioDevices(false)
+		check.Eq(t, len(devices), sdl.GetNumAudioDevices(false))
+		for i, d := range devices {
+			check.Eq(t, d.Index, i)
+			check.Eq(t, d.Name, sdl.GetAudioDeviceName(i, false))
+		}
+	})
+}
+
+func TestAudioDeviceIDMethods(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_AUDIO); err != nil {
+			t.Skip("audio not available:", err)
+		}
+		defer sdl.Quit()
+		desired := sdl.AudioSpec{Freq: 44100, Format: sdl.AUDIO_S16, Channels: 2, Samples: 1024}
+		dev, err := sdl.OpenAudioDevice("", false, &desired, nil, 0)
+		if err != nil {
+			t.Skip("no audio device:", err)
+		}
+		check.Eq(t, sdl.OpenAudioDevices(), []sdl.AudioDeviceID{dev})
+
+		check.Eq(t, dev.Status(), sdl.AUDIO_PAUSED)
+		dev.Unpause()
+		check.Eq(t, dev.Status(), sdl.AUDIO_PLAYING)
+		sdl.PauseAllAudioDevices(true)
+		check.Eq(t, dev.Status(), sdl.AUDIO_PAUSED)
+
+		check.Eq(t, dev.Queue(make([]byte, 400)), nil)
+		check.Eq(t, dev.QueuedSize(), uint32(400))
+		dev.ClearQueued()
+		check.Eq(t, dev.QueuedSize(), uint32(0))
+
+		dev.Close()
+		check.Eq(t, len(sdl.OpenAudioDevices()), 0)
+	})
+}
+
+func TestOpenAudioDevicesAreForgottenWhenAudioQuits(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_AUDIO); err != nil {
+			t.Skip("audio not available:", err)
+		}
+		defer sdl.Quit()
+		desired := sdl.AudioSpec{Freq: 44100, Format: sdl.AUDIO_S16, Channels: 2, Samples: 1024}
+		if err := sdl.OpenAudio(&desired, nil); err != nil {
+			t.Skip("no audio device:", err)
+		}
+		check.Eq(t, sdl.OpenAudioDevices(), []sdl.AudioDeviceID{1})
+		sdl.CloseAudio()
+		check.Eq(t, len(sdl.OpenAudioDevices()), 0)
+
+		check.Eq(t, sdl.OpenAudio(&desired, nil), nil)
+		dev, err := sdl.OpenAudioDevice("", false, &desired, nil, 0)
+		check.Eq(t, err, nil)
+		check.Neq(t, dev, sdl.AudioDeviceID(1))
+		check.Eq(t, len(sdl.OpenAudioDevices()), 2)
+		sdl.QuitSubSystem(sdl.INIT_AUDIO)
+		check.Eq(t, len(sdl.OpenAudioDevices()), 0)
+	})
+}
+
+func TestSaveWAVCanBeLoaded(t *testing.T) {
+	test(func() {
+		dir, err := ioutil.TempDir("", "sdl_wav")
+		check.Eq(t, err, nil)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "test.wav")
+
+		spec := sdl.AudioSpec{Freq: 22050, Format: sdl.AUDIO_S16MSB, Channels: 2}
+		data := []byte{0x01, 0x02, 0x03, 0x04, 0xFF, 0xFE, 0x80, 0x00}
+		check.Eq(t, sdl.SaveWAV(path, spec, data), nil)
+
+		loaded, loadedSpec := sdl.LoadWAV(path)
+		check.Eq(t, loadedSpec.Freq, int32(22050))
+		check.Eq(t, loadedSpec.Format, sdl.AudioFormat(sdl.AUDIO_S16LSB))
+		check.Eq(t, loadedSpec.Channels, uint8(2))
+		check.Eq(t, loaded, []byte{0x02, 0x01, 0x04, 0x03, 0xFE, 0xFF, 0x00, 0x80})
+		sdl.FreeWAV(loaded)
+
+		check.Neq(t, sdl.WriteWAV(ioutil.Discard, spec, data[:3]), nil)
+	})
+}
+
+func TestWatchJoystickBattery(t *testing.T) {
+	test(func() {
+		check.Eq(t, sdl.Init(sdl.INIT_EVENTS), nil)
+		defer sdl.Quit()
+
+		events, stop := sdl.WatchJoystickBattery(1)
+		defer stop()
+
+		// SDL copies a whole event union so build the event in one.
+		var buf sdl.CEvent
+		e := (*sdl.JoyBatteryEvent)(unsafe.Pointer(&buf))
+		e.Type = sdl.JOYBATTERYUPDATED
+		e.Which = 5
+		e.Level = sdl.JOYSTICK_POWER_LOW
+		_, err := sdl.PushEvent(e)
+		check.Eq(t, err, nil)
+
+		select {
+		case got := <-events:
+			check.Eq(t, got.Which, sdl.JoystickID(5))
+			check.Eq(t, got.Level, sdl.JoystickPowerLevel(sdl.JOYSTICK_POWER_LOW))
+		default:
+			t.Error("no battery event was sent")
+		}
+	})
+}
+
+func TestUserEventWakesUpWaitEvent(t *testing.T) {
+	test(func() {
+		check.Eq(t, sdl.Init(sdl.INIT_EVENTS), nil)
+		defer sdl.Quit()
+
+		typ := sdl.RegisterEvents(1)
+		check.Neq(t, typ, uint32(0xFFFFFFFF))
+		go func() {
+			e := sdl.NewUserEvent(typ, 7)
+			e.SetData(1, 2)
+			sdl.PushEvent(e)
+		}()
+
+		for {
+			e := sdl.WaitEventTimeout(5000)
+			check.Neq(t, e, nil)
+			if e == nil {
+				return
+			}
+			if user, ok := e.(*sdl.UserEvent); ok {
+				check.Eq(t, user.Type, typ)
+				check.Eq(t, user.Code, int32(7))
+				data1, data2 := user.Data()
+				check.Eq(t, data1, uintptr(1))
+				check.Eq(t, data2, uintptr(2))
+				return
+			}
+		}
+	})
+}
+
+func TestWakeEventLoop(t *testing.T) {
+	test(func() {
+		check.Eq(t, sdl.Init(sdl.INIT_EVENTS), nil)
+		defer sdl.Quit()
+
+		check.Eq(t, sdl.WakeEventLoop(), nil)
+		for e := sdl.PollEvent(); e != nil; e = sdl.PollEvent() {
+			_, isWake := e.(*sdl.WakeEvent)
+			check.Eq(t, isWake, false)
+		}
+
+		go sdl.WakeEventLoop()
+		for {
+			e := sdl.WaitEventTimeout(5000)
+			check.Neq(t, e, nil)
+			if _, ok := e.(*sdl.WakeEvent); ok || e == nil {
+				return
+			}
+		}
+	})
+}
+
+func TestGestureEventLayoutsMatchC(t *testing.T) {
+	// SDL_TouchID, SDL_FingerID and SDL_GestureID are 64 bit and 8 byte
+	// aligned in C, even on 32 bit Windows where Go only aligns them to 4.
+	var finger sdl.TouchFingerEvent
+	check.Eq(t, unsafe.Offsetof(finger.TouchID), uintptr(8))
+	check.Eq(t, unsafe.Offsetof(finger.FingerID), uintptr(16))
+	check.Eq(t, unsafe.Offsetof(finger.X), uintptr(24))
+	check.Eq(t, unsafe.Offsetof(finger.Pressure), uintptr(40))
+	check.Eq(t, unsafe.Offsetof(finger.WindowID), uintptr(44))
+
+	var multi sdl.MultiGestureEvent
+	check.Eq(t, unsafe.Offsetof(multi.TouchID), uintptr(8))
+	check.Eq(t, unsafe.Offsetof(multi.DTheta), uintptr(16))
+	check.Eq(t, unsafe.Offsetof(multi.X), uintptr(24))
+	check.Eq(t, unsafe.Offsetof(multi.NumFingers), uintptr(32))
+
+	var dollar sdl.DollarGestureEvent
+	check.Eq(t, unsafe.Offsetof(dollar.TouchID), uintptr(8))
+	check.Eq(t, unsafe.Offsetof(dollar.GestureID), uintptr(16))
+	check.Eq(t, unsafe.Offsetof(dollar.NumFingers), uintptr(24))
+	check.Eq(t, unsafe.Offsetof(dollar.Y), uintptr(36))
+
+	for _, size := range []uintptr{
+		unsafe.Sizeof(finger),
+		unsafe.Sizeof(multi),
+		unsafe.Sizeof(dollar),
+	} {
+		check.Eq(t, size <= unsafe.Sizeof(sdl.CEvent{}), true)
+	}
+}
+
+func TestGestureHelpers(t *testing.T) {
+	multi := sdl.MultiGestureEvent{DTheta: math.Pi / 2, DDist: -0.25}
+	check.Eq(t, multi.RotationDegrees(), float32(90))
+	check.Eq(t, multi.ZoomFactor(), float32(0.75))
+
+	finger := sdl.TouchFingerEvent{Pressure: 0.25}
+	check.Eq(t, finger.PressureCurve(0.5), float32(0.5))
+	check.Eq(t, finger.PressureCurve(1), float32(0.25))
+	finger.Pressure = 1.5
+	check.Eq(t, finger.PressureCurve(2), float32(1))
+}
+
+func TestCreateRendererWithOptions(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+		window, err := sdl.CreateWindow("", 0, 0, 16, 16, sdl.WINDOW_HIDDEN)
+		check.Eq(t, err, nil)
+		defer window.Destroy()
+
+		_, err = sdl.CreateRendererWithOptions(window, sdl.RendererOptions{Driver: "no such driver"})
+		check.Neq(t, err, nil)
+
+		renderer, err := sdl.CreateRendererWithOptions(window, sdl.RendererOptions{
+			Driver:        "SOFTWARE",
+			TargetTexture: true,
+		})
+		check.Eq(t, err, nil)
+		defer renderer.Destroy()
+		info, err := renderer.GetInfo()
+		check.Eq(t, err, nil)
+		check.Eq(t, info.Name, "software")
+	})
+}
+
+func TestNewSoftwareRendererRendersIntoImage(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(4, 2)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+
+		check.Eq(t, renderer.SetDrawColor(0, 0, 255, 255), nil)
+		check.Eq(t, renderer.Clear(), nil)
+		check.Eq(t, renderer.SetDrawColor(255, 0, 0, 255), nil)
+		check.Eq(t, renderer.DrawPoint(3, 1), nil)
+		renderer.Present()
+
+		img, err := surface.ToRGBA()
+		check.Eq(t, err, nil)
+		check.Eq(t, img.Bounds(), image.Rect(0, 0, 4, 2))
+		check.Eq(t, img.RGBAAt(0, 0), color.RGBA{B: 255, A: 255})
+		check.Eq(t, img.RGBAAt(3, 1), color.RGBA{R: 255, A: 255})
+	})
+}
+
+func TestCopyFTakesSourceRectInTexturePixels(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(2, 2)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+
+		red := color.RGBA{R: 255, A: 255}
+		green := color.RGBA{G: 255, A: 255}
+		blue := color.RGBA{B: 255, A: 255}
+		strip := image.NewRGBA(image.Rect(0, 0, 3, 1))
+		strip.SetRGBA(0, 0, red)
+		strip.SetRGBA(1, 0, green)
+		strip.SetRGBA(2, 0, blue)
+		stripSurface, err := sdl.CreateRGBSurfaceWithFormatFrom(
+			unsafe.Pointer(&strip.Pix[0]), 3, 1, 32, int32(strip.Stride), sdl.PIXELFORMAT_ABGR8888,
+		)
+		check.Eq(t, err, nil)
+		defer stripSurface.Free()
+		texture, err := renderer.CreateTextureFromSurface(stripSurface)
+		check.Eq(t, err, nil)
+		defer texture.Destroy()
+
+		check.Eq(t, renderer.SetDrawColor(0, 0, 0, 255), nil)
+		check.Eq(t, renderer.Clear(), nil)
+		check.Eq(t, renderer.CopyF(
+			texture,
+			&sdl.Rect{X: 1, Y: 0, W: 1, H: 1},
+			&sdl.FRect{X: 0, Y: 0, W: 2, H: 1},
+		), nil)
+		check.Eq(t, renderer.CopyExF(
+			texture,
+			&sdl.Rect{X: 2, Y: 0, W: 1, H: 1},
+			&sdl.FRect{X: 0, Y: 1, W: 2, H: 1},
+			0, nil, sdl.FLIP_NONE,
+		), nil)
+		renderer.Present()
+
+		img, err := surface.ToRGBA()
+		check.Eq(t, err, nil)
+		check.Eq(t, img.RGBAAt(0, 0), green)
+		check.Eq(t, img.RGBAAt(1, 0), green)
+		check.Eq(t, img.RGBAAt(0, 1), blue)
+		check.Eq(t, img.RGBAAt(1, 1), blue)
+	})
+}
+
+func TestRendererGetters(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(64, 32)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+
+		info, err := renderer.GetInfo()
+		check.Eq(t, err, nil)
+		check.Eq(t, info.Name, "software")
+
+		w, h, err := renderer.GetOutputSize()
+		check.Eq(t, err, nil)
+		check.Eq(t, [2]int32{w, h}, [2]int32{64, 32})
+
+		check.Eq(t, renderer.SetScale(2, 4), nil)
+		scaleX, scaleY := renderer.GetScale()
+		check.Eq(t, [2]float32{scaleX, scaleY}, [2]float32{2, 4})
+		check.Eq(t, renderer.SetScale(1, 1), nil)
+
+		viewport := sdl.Rect{X: 1, Y: 2, W: 30, H: 20}
+		check.Eq(t, renderer.SetViewport(&viewport), nil)
+		check.Eq(t, renderer.GetViewport(), viewport)
+
+		check.Eq(t, renderer.IsClipEnabled(), false)
+		clip := sdl.Rect{X: 3, Y: 4, W: 5, H: 6}
+		check.Eq(t, renderer.SetClipRect(&clip), nil)
+		check.Eq(t, renderer.IsClipEnabled(), true)
+		check.Eq(t, renderer.GetClipRect(), clip)
+
+		check.Eq(t, renderer.SetLogicalSize(16, 8), nil)
+		w, h = renderer.GetLogicalSize()
+		check.Eq(t, [2]int32{w, h}, [2]int32{16, 8})
+	})
+}
+
+func TestCreateSurfaceFromImageCopiesSubImages(t *testing.T) {
+	test(func() {
+		img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+		img.SetRGBA(1, 2, color.RGBA{R: 255, A: 255})
+		img.SetRGBA(3, 3, color.RGBA{G: 255, A: 255})
+
+		surface, err := sdl.CreateSurfaceFromImage(img.SubImage(image.Rect(1, 2, 4, 4)))
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		check.Eq(t, surface.W, int32(3))
+		check.Eq(t, surface.H, int32(2))
+
+		copied, err := surface.ToRGBA()
+		check.Eq(t, err, nil)
+		check.Eq(t, copied.RGBAAt(0, 0), color.RGBA{R: 255, A: 255})
+		check.Eq(t, copied.RGBAAt(2, 1), color.RGBA{G: 255, A: 255})
+		check.Eq(t, copied.RGBAAt(1, 0), color.RGBA{})
+	})
+}
+
+func TestSurfaceCompositeAndPremultiplyAlpha(t *testing.T) {
+	test(func() {
+		newSurface := func(r, g, b, a uint8) *sdl.Surface {
+			s, err := sdl.CreateRGBSurfaceWithFormat(0, 2, 2, 32, sdl.PIXELFORMAT_ARGB8888)
+			check.Eq(t, err, nil)
+			check.Eq(t, s.FillRect(nil, sdl.MapRGBA(s.Format, r, g, b, a)), nil)
+			return s
+		}
+		pixel := func(s *sdl.Surface) color.RGBA {
+			img, err := s.ToRGBA()
+			check.Eq(t, err, nil)
+			return img.RGBAAt(1, 1)
+		}
+
+		dst := newSurface(255, 0, 0, 255)
+		defer dst.Free()
+		transparent := newSurface(0, 0, 255, 0)
+		defer transparent.Free()
+		check.Eq(t, transparent.SetBlendMode(sdl.BLENDMODE_ADD), nil)
+
+		check.Eq(t, dst.Composite(transparent, sdl.BLENDMODE_BLEND), nil)
+		check.Eq(t, pixel(dst), color.RGBA{R: 255, A: 255})
+		mode, err := transparent.GetBlendMode()
+		check.Eq(t, err, nil)
+		check.Eq(t, mode, sdl.BlendMode(sdl.BLENDMODE_ADD))
+
+		blue := newSurface(0, 0, 255, 255)
+		defer blue.Free()
+		check.Eq(t, dst.Composite(blue, sdl.BLENDMODE_NONE), nil)
+		check.Eq(t, pixel(dst), color.RGBA{B: 255, A: 255})
+
+		var v sdl.Version
+		sdl.GetVersion(&v)
+		if sdl.VERSIONNUM(int(v.Major), int(v.Minor), int(v.Patch)) < sdl.VERSIONNUM(2, 0, 18) {
+			t.Skip("PremultiplyAlpha requires SDL 2.0.18")
+		}
+		white := newSurface(255, 255, 255, 128)
+		defer white.Free()
+		check.Eq(t, white.PremultiplyAlpha(), nil)
+		check.Eq(t, white.Pixels()[:4], []byte{128, 128, 128, 128})
+	})
+}
+
+func TestPixelFunctionsRejectTooSmallBuffers(t *testing.T) {
+	test(func() {
+		argb := uint32(sdl.PIXELFORMAT_ARGB8888)
+		pixels := make([]byte, 2*2*4)
+		check.Eq(t, sdl.PremultiplyAlpha(2, 2, argb, pixels[:15], 8, argb, pixels, 8), sdl.ErrInvalidParameters)
+		check.Eq(t, sdl.PremultiplyAlpha(2, 2, argb, pixels, 8, argb, pixels[:4], 8), sdl.ErrInvalidParameters)
+		check.Eq(t, sdl.PremultiplyAlpha(2, 2, argb, pixels, 4, argb, pixels, 8), sdl.ErrInvalidParameters)
+		check.Eq(t, sdl.PremultiplyAlpha(0, 2, argb, pixels, 8, argb, pixels, 8), sdl.ErrInvalidParameters)
+
+		src, err := sdl.CreateRGBSurfaceWithFormat(0, 4, 4, 32, sdl.PIXELFORMAT_ARGB8888)
+		check.Eq(t, err, nil)
+		defer src.Free()
+		check.Eq(t, src.SoftStretchLinear(nil, nil, nil), sdl.ErrInvalidParameters)
+		check.Eq(t, src.SoftStretchLinear(&sdl.Rect{X: 2, Y: 2, W: 4, H: 4}, src, nil), sdl.ErrInvalidParameters)
+		check.Eq(t, src.SoftStretchLinear(nil, src, &sdl.Rect{X: -1, Y: 0, W: 2, H: 2}), sdl.ErrInvalidParameters)
+	})
+}
+
+func TestShowAndHideScreenKeyboardToggleTextInput(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+
+		sdl.HideScreenKeyboard()
+		check.Eq(t, sdl.IsTextInputActive(), false)
+		sdl.ShowScreenKeyboard(&sdl.Rect{X: 10, Y: 10, W: 100, H: 20})
+		check.Eq(t, sdl.IsTextInputActive(), true)
+		sdl.HideScreenKeyboard()
+		check.Eq(t, sdl.IsTextInputActive(), false)
+	})
+}
+
+func TestDPIWatcherReportsChangesOnce(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+		window, err := sdl.CreateWindow("", 0, 0, 16, 16, sdl.WINDOW_HIDDEN)
+		check.Eq(t, err, nil)
+		defer window.Destroy()
+		id, err := window.GetID()
+		check.Eq(t, err, nil)
+
+		dpi := sdl.NewDPIWatcher()
+		var changes []float32
+		dpi.OnChange = func(w *sdl.Window, scale float32) {
+			check.Eq(t, w, window)
+			changes = append(changes, scale)
+		}
+		moved := &sdl.WindowEvent{
+			Type:     sdl.WINDOWEVENT,
+			WindowID: id,
+			Event:    sdl.WINDOWEVENT_MOVED,
+		}
+		check.Eq(t, dpi.HandleEvent(&sdl.QuitEvent{Type: sdl.QUIT}), false)
+		check.Eq(t, dpi.HandleEvent(moved), true)
+		check.Eq(t, dpi.HandleEvent(moved), false)
+		check.Eq(t, len(changes), 1)
+		check.Eq(t, dpi.Scale(window), changes[0])
+		check.Eq(t, changes[0] > 0, true)
+	})
+}
+
+func TestRenderDriversIncludeSoftware(t *testing.T) {
+	test(func() {
+		drivers := sdl.RenderDrivers()
+		var software *sdl.RendererInfo
+		for i := range drivers {
+			if drivers[i].Name == "software" {
+				software = &drivers[i]
+			}
+		}
+		check.Neq(t, software, nil)
+		if software == nil {
+			return
+		}
+		check.Eq(t, software.FlagNames(), []string{"software", "target texture"})
+		check.Eq(t, len(software.FormatNames()) > 0, true)
+		check.Eq(t, strings.HasPrefix(software.String(), "software (software, target texture), "), true)
+	})
+}
+
+func TestAudioInitWithFallback(t *testing.T) {
+	test(func() {
+		drivers := sdl.AudioDrivers()
+		check.Eq(t, len(drivers), sdl.GetNumAudioDrivers())
+
+		driver, err := sdl.AudioInitWithFallback("no such driver", "dummy")
+		check.Eq(t, err, nil)
+		check.Eq(t, driver, "dummy")
+		sdl.AudioQuit()
+
+		_, err = sdl.AudioInitWithFallback("no such driver")
+		check.Neq(t, err, nil)
+	})
+}
+
+func TestNegotiateAudioSpecKeepsFieldsThatMustNotChange(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_AUDIO); err != nil {
+			t.Skip("audio not available:", err)
+		}
+		defer sdl.Quit()
+
+		desired := sdl.AudioSpec{
+			Freq:     12345,
+			Format:   sdl.AUDIO_S8,
+			Channels: 3,
+			Samples:  512,
+		}
+		check.Eq(t, sdl.NegotiateAudioSpec(desired, 0), desired)
+
+		spec := sdl.NegotiateAudioSpec(desired, sdl.AUDIO_ALLOW_FREQUENCY_CHANGE)
+		check.Eq(t, spec.Freq > 0, true)
+		check.Eq(t, spec.Format, desired.Format)
+		check.Eq(t, spec.Channels, desired.Channels)
+		check.Eq(t, spec.Samples, desired.Samples)
+	})
+}
+
+func TestWindowSetIconFromImage(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+		window, err := sdl.CreateWindow("", 0, 0, 16, 16, sdl.WINDOW_HIDDEN)
+		check.Eq(t, err, nil)
+		defer window.Destroy()
+
+		icon := image.NewRGBA(image.Rect(10, 10, 42, 42))
+		for i := range icon.Pix {
+			icon.Pix[i] = 0x80
+		}
+		check.Eq(t, window.SetIconFromImage(icon), nil)
+	})
+}
+
+func TestWindowTitleRoundTrip(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+		window, err := sdl.CreateWindow("Grüße, 世界 🎮", 0, 0, 16, 16, sdl.WINDOW_HIDDEN)
+		check.Eq(t, err, nil)
+		defer window.Destroy()
+		check.Eq(t, window.Title(), "Grüße, 世界 🎮")
+
+		window.SetTitle("Привет")
+		check.Eq(t, window.GetTitle(), "Привет")
+
+		window.SetTitle("bad \xff byte\x00 and more")
+		check.Eq(t, window.Title(), "bad � byte")
+	})
+}
+
+func TestWindowStateQueries(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+		window, err := sdl.CreateWindow("", 0, 0, 16, 16, sdl.WINDOW_HIDDEN)
+		check.Eq(t, err, nil)
+		defer window.Destroy()
+
+		check.Eq(t, window.IsShown(), false)
+		check.Eq(t, window.IsFullscreen(), false)
+		check.Eq(t, window.IsMaximized(), false)
+		check.Eq(t, window.IsMinimized(), false)
+		window.Show()
+		check.Eq(t, window.IsShown(), true)
+		window.Hide()
+		check.Eq(t, window.IsShown(), false)
+	})
+}
+
+func TestToggleFullscreenRestoresWindowedGeometry(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+		window, err := sdl.CreateWindow("", 100, 120, 200, 150, 0)
+		check.Eq(t, err, nil)
+		defer window.Destroy()
+
+		check.Eq(t, window.ToggleFullscreen(true), nil)
+		check.Eq(t, window.IsFullscreen(), true)
+		check.Eq(t, window.ToggleFullscreen(true), nil)
+		check.Eq(t, window.IsFullscreen(), false)
+		x, y := window.GetPosition()
+		w, h := window.GetSize()
+		check.Eq(t, [4]int32{x, y, w, h}, [4]int32{100, 120, 200, 150})
+	})
+}
+
+func TestChooseDisplayMode(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+
+		modes, err := sdl.DisplayModes(0)
+		check.Eq(t, err, nil)
+		if len(modes) == 0 {
+			t.Skip("no display modes")
+		}
+		largest := modes[0]
+
+		mode, err := sdl.ChooseDisplayMode(0, largest.W, largest.H, largest.RefreshRate)
+		check.Eq(t, err, nil)
+		check.Eq(t, [3]int32{mode.W, mode.H, mode.RefreshRate}, [3]int32{largest.W, largest.H, largest.RefreshRate})
+
+		mode, err = sdl.ChooseDisplayMode(0, largest.W+1000, largest.H+1000, 0)
+		check.Eq(t, err, nil)
+		check.Eq(t, [2]int32{mode.W, mode.H}, [2]int32{largest.W, largest.H})
+	})
+}
+
+func TestHideCursorScopedRestoresVisibility(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+
+		shown := func() int {
+			state, err := sdl.ShowCursor(sdl.QUERY)
+			check.Eq(t, err, nil)
+			return state
+		}
+		check.Eq(t, shown(), sdl.ENABLE)
+		func() {
+			defer sdl.HideCursorScoped()()
+			check.Eq(t, shown(), sdl.DISABLE)
+			restore := sdl.HideCursorScoped()
+			restore()
+			restore()
+			check.Eq(t, shown(), sdl.DISABLE)
+		}()
+		check.Eq(t, shown(), sdl.ENABLE)
+	})
+}
+
+func TestNormalizeAxisAppliesDeadZone(t *testing.T) {
+	check.Eq(t, sdl.NormalizeAxis(0, 0.2), float32(0))
+	check.Eq(t, sdl.NormalizeAxis(32767, 0.2), float32(1))
+	check.Eq(t, sdl.NormalizeAxis(-32768, 0.2), float32(-1))
+	check.Eq(t, sdl.NormalizeAxis(6000, 0.2), float32(0))
+	check.Eq(t, sdl.NormalizeAxis(-6000, 0.2), float32(0))
+	half := sdl.NormalizeAxis(32767*6/10, 0.2)
+	check.Eq(t, math.Abs(float64(half)-0.5) < 0.001, true)
+	check.Eq(t, sdl.NormalizeAxis(-32767*6/10, 0.2), -half)
+	check.Eq(t, sdl.NormalizeAxis(100, 0), float32(100)/32767)
+}
+
+func TestStickStateUsesRadialDeadZone(t *testing.T) {
+	check.Eq(t, sdl.NewStickState(5000, 5000, 0.25), sdl.StickState{})
+
+	// Per axis the values are in the dead zone but the stick's distance from
+	// the center is not.
+	diagonal := sdl.NewStickState(7000, 7000, 0.25)
+	check.Eq(t, diagonal.X > 0, true)
+	check.Eq(t, diagonal.X, diagonal.Y)
+
+	corner := sdl.NewStickState(32767, -32768, 0.25)
+	check.Eq(t, math.Abs(float64(corner.Magnitude())-1) < 0.0001, true)
+	check.Eq(t, math.Abs(float64(corner.Angle())+math.Pi/4) < 0.001, true)
+
+	right := sdl.NewStickState(32767, 0, 0.25)
+	check.Eq(t, right, sdl.StickState{X: 1, Y: 0})
+}
+
+func TestTextEventsDecodeUTF8(t *testing.T) {
+	var input sdl.TextInputEvent
+	copy(input.Text[:], "é😀\x00garbage")
+	check.Eq(t, input.GetText(), "é😀")
+	check.Eq(t, input.Runes(), []rune{'é', '😀'})
+
+	copy(input.Text[:], "a\xffb\x00")
+	check.Eq(t, input.GetText(), "a�b")
+
+	full := sdl.TextInputEvent{}
+	for i := range full.Text {
+		full.Text[i] = 'x'
+	}
+	check.Eq(t, full.GetText(), strings.Repeat("x", len(full.Text)))
+
+	editing := sdl.TextEditingEvent{Start: 1, Length: 2}
+	copy(editing.Text[:], "añ😀z")
+	check.Eq(t, editing.Runes(), []rune{'a', 'ñ', '😀', 'z'})
+	before, selected, after := editing.Selection()
+	check.Eq(t, before, "a")
+	check.Eq(t, selected, "ñ😀")
+	check.Eq(t, after, "z")
+
+	editing.Start, editing.Length = 3, 10
+	before, selected, after = editing.Selection()
+	check.Eq(t, before, "añ😀")
+	check.Eq(t, selected, "z")
+	check.Eq(t, after, "")
+}
+
+func TestLayoutWatcherCachesKeyLabels(t *testing.T) {
+	layout := sdl.NewLayoutWatcher()
+	for _, code := range []sdl.Scancode{sdl.SCANCODE_A, sdl.SCANCODE_SPACE, sdl.SCANCODE_LSHIFT} {
+		key := sdl.GetKeyFromScancode(code)
+		check.Eq(t, layout.Key(code), key)
+		check.Eq(t, layout.ScancodeToLabel(code), sdl.GetKeyName(key))
+	}
+	check.Eq(t, layout.ScancodeToLabel(sdl.SCANCODE_SPACE), "Space")
+	check.Eq(t, layout.ScancodeToLabel(sdl.NUM_SCANCODES), "")
+	check.Eq(t, layout.Key(sdl.NUM_SCANCODES), sdl.Keycode(sdl.K_UNKNOWN))
+
+	changes := 0
+	layout.OnChange = func() { changes++ }
+	check.Eq(t, layout.HandleEvent(&sdl.CommonEvent{Type: sdl.KEYMAPCHANGED}), true)
+	check.Eq(t, layout.HandleEvent(&sdl.QuitEvent{Type: sdl.QUIT}), false)
+	check.Eq(t, changes, 1)
+}
+
+func TestCursorDisplayIndexAndPlaceNearCursor(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+
+		n, err := sdl.GetNumVideoDisplays()
+		check.Eq(t, err, nil)
+		index, err := sdl.CursorDisplayIndex()
+		check.Eq(t, err, nil)
+		check.Eq(t, 0 <= index && index < n, true)
+
+		usable, err := sdl.GetDisplayUsableBounds(index)
+		check.Eq(t, err, nil)
+		x, y, err := sdl.PlaceNearCursor(100, 50, 16, 16)
+		check.Eq(t, err, nil)
+		check.Eq(t, usable.X <= x && x+100 <= usable.X+usable.W, true)
+		check.Eq(t, usable.Y <= y && y+50 <= usable.Y+usable.H, true)
+	})
+}
+
+func TestInitHeadlessWorksWithoutVideo(t *testing.T) {
+	test(func() {
+		if err := sdl.InitHeadless(); err != nil {
+			t.Skip("audio not available:", err)
+		}
+		defer sdl.Quit()
+
+		check.Eq(t, sdl.WasInit(sdl.INIT_VIDEO), uint32(0))
+		want := uint32(sdl.INIT_TIMER | sdl.INIT_EVENTS | sdl.INIT_JOYSTICK)
+		check.Eq(t, sdl.WasInit(want), want)
+		check.Eq(t, sdl.NumJoysticks() >= 0, true)
+
+		for sdl.PollEvent() != nil {
+		}
+		_, err := sdl.PushEvent(&sdl.UserEvent{Type: sdl.USEREVENT, Code: 7})
+		check.Eq(t, err, nil)
+		sdl.PumpEvents()
+		e, ok := sdl.PollEvent().(*sdl.UserEvent)
+		check.Eq(t, ok, true)
+		check.Eq(t, e.Code, int32(7))
+	})
+}
+
+func TestInitSubSystemScopedCountsReferences(t *testing.T) {
+	test(func() {
+		defer sdl.Quit()
+
+		releaseA, err := sdl.InitSubSystemScoped(sdl.INIT_EVENTS | sdl.INIT_TIMER)
+		check.Eq(t, err, nil)
+		releaseB, err := sdl.InitSubSystemScoped(sdl.INIT_EVENTS)
+		check.Eq(t, err, nil)
+		check.Eq(t, sdl.WasInit(sdl.INIT_EVENTS|sdl.INIT_TIMER), uint32(sdl.INIT_EVENTS|sdl.INIT_TIMER))
+
+		releaseA()
+		releaseA()
+		check.Eq(t, sdl.WasInit(sdl.INIT_TIMER), uint32(0))
+		check.Eq(t, sdl.WasInit(sdl.INIT_EVENTS), uint32(sdl.INIT_EVENTS))
+		releaseB()
+		check.Eq(t, sdl.WasInit(sdl.INIT_EVENTS), uint32(0))
+
+		// Subsystems initialized outside of InitSubSystemScoped keep running.
+		check.Eq(t, sdl.InitSubSystem(sdl.INIT_TIMER), nil)
+		release, err := sdl.InitSubSystemScoped(sdl.INIT_TIMER)
+		check.Eq(t, err, nil)
+		release()
+		check.Eq(t, sdl.WasInit(sdl.INIT_TIMER), uint32(sdl.INIT_TIMER))
+	})
+}
+
+func TestInitWithConfigSetsHints(t *testing.T) {
+	test(func() {
+		defer sdl.ClearHints()
+		err := sdl.InitWithConfig(sdl.Config{
+			Flags:            sdl.INIT_EVENTS,
+			VSync:            true,
+			ScaleQuality:     sdl.SCALE_QUALITY_LINEAR,
+			AllowScreensaver: true,
+			NoCloseOnAltF4:   true,
+			Hints:            map[string]string{sdl.HINT_TIMER_RESOLUTION: "0"},
+		})
+		check.Eq(t, err, nil)
+		defer sdl.Quit()
+
+		check.Eq(t, sdl.WasInit(sdl.INIT_EVENTS), uint32(sdl.INIT_EVENTS))
+		check.Eq(t, sdl.GetHint(sdl.HINT_RENDER_VSYNC), "1")
+		check.Eq(t, sdl.GetHint(sdl.HINT_RENDER_SCALE_QUALITY), "linear")
+		check.Eq(t, sdl.GetHint(sdl.HINT_VIDEO_ALLOW_SCREENSAVER), "1")
+		check.Eq(t, sdl.GetHint(sdl.HINT_WINDOWS_NO_CLOSE_ON_ALT_F4), "1")
+		check.Eq(t, sdl.GetHint(sdl.HINT_TIMER_RESOLUTION), "0")
+		check.Eq(t, sdl.GetHint(sdl.HINT_RENDER_DRIVER), "")
+	})
+}
+
+func TestBitmapFontDrawsCellsFromSpriteSheet(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(16, 8)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+
+		// The sheet has 2x2 cells for '?', '@' and 'A' in one row, each
+		// filled with one color.
+		colors := []color.NRGBA{
+			{B: 255, A: 255},
+			{G: 255, A: 255},
+			{R: 255, A: 255},
+		}
+		sheet := image.NewNRGBA(image.Rect(0, 0, 6, 2))
+		for i, c := range colors {
+			for y := 0; y < 2; y++ {
+				sheet.SetNRGBA(2*i, y, c)
+				sheet.SetNRGBA(2*i+1, y, c)
+			}
+		}
+		font, err := sdl.NewBitmapFont(renderer, sheet, 2, 2, '?')
+		check.Eq(t, err, nil)
+		defer font.Destroy()
+
+		w, h := font.Measure("AB\nABC")
+		check.Eq(t, w, int32(6))
+		check.Eq(t, h, int32(4))
+		font.LineSpacing = 1
+		w, h = font.Measure("AB\nABC")
+		check.Eq(t, w, int32(6))
+		check.Eq(t, h, int32(5))
+
+		check.Eq(t, renderer.SetDrawColor(0, 0, 0, 255), nil)
+		check.Eq(t, renderer.Clear(), nil)
+		check.Eq(t, font.Draw("A@\nZ?", 1, 1), nil)
+		renderer.Present()
+
+		img, err := surface.ToRGBA()
+		check.Eq(t, err, nil)
+		check.Eq(t, img.RGBAAt(0, 0), color.RGBA{A: 255})
+		check.Eq(t, img.RGBAAt(1, 1), color.RGBA{R: 255, A: 255})
+		check.Eq(t, img.RGBAAt(4, 2), color.RGBA{G: 255, A: 255})
+		// 'Z' is not in the font and falls back to '?'.
+		check.Eq(t, img.RGBAAt(1, 4), color.RGBA{B: 255, A: 255})
+		check.Eq(t, img.RGBAAt(3, 5), color.RGBA{B: 255, A: 255})
+
+		_, err = sdl.NewBitmapFont(renderer, sheet, 4, 4, '?')
+		check.Neq(t, err, nil)
+	})
+}
+
+func TestLoadBitmapFontMakesBMPBackgroundTransparent(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(4, 2)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+
+		sheet, err := sdl.CreateRGBSurfaceWithFormat(0, 4, 2, 24, sdl.PIXELFORMAT_RGB24)
+		check.Eq(t, err, nil)
+		defer sheet.Free()
+		check.Eq(t, sheet.FillRect(nil, sdl.MapRGB(sheet.Format, 255, 0, 255)), nil)
+		check.Eq(t, sheet.FillRect(&sdl.Rect{X: 3, Y: 1, W: 1, H: 1}, sdl.MapRGB(sheet.Format, 0, 255, 0)), nil)
+		dir, err := ioutil.TempDir("", "bitmap_font")
+		check.Eq(t, err, nil)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "font.bmp")
+		check.Eq(t, sheet.SaveBMP(path), nil)
+
+		font, err := sdl.LoadBitmapFont(renderer, path, 2, 2, 'a')
+		check.Eq(t, err, nil)
+		defer font.Destroy()
+		check.Eq(t, renderer.SetDrawColor(0, 0, 255, 255), nil)
+		check.Eq(t, renderer.Clear(), nil)
+		check.Eq(t, font.Draw("b", 0, 0), nil)
+		renderer.Present()
+
+		img, err := surface.ToRGBA()
+		check.Eq(t, err, nil)
+		check.Eq(t, img.RGBAAt(0, 0), color.RGBA{B: 255, A: 255})
+		check.Eq(t, img.RGBAAt(1, 1), color.RGBA{G: 255, A: 255})
+	})
+}
+
+func TestDrawNinePatchKeepsCornersUnscaled(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(10, 8)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+
+		// The patch is 3x3 pixels with 1 pixel borders: red corners, green
+		// edges and a blue center.
+		red := color.RGBA{R: 255, A: 255}
+		green := color.RGBA{G: 255, A: 255}
+		blue := color.RGBA{B: 255, A: 255}
+		patch := image.NewRGBA(image.Rect(0, 0, 3, 3))
+		for y := 0; y < 3; y++ {
+			for x := 0; x < 3; x++ {
+				switch {
+				case x != 1 && y != 1:
+					patch.SetRGBA(x, y, red)
+				case x == 1 && y == 1:
+					patch.SetRGBA(x, y, blue)
+				default:
+					patch.SetRGBA(x, y, green)
+				}
+			}
+		}
+		patchSurface, err := sdl.CreateRGBSurfaceWithFormatFrom(
+			unsafe.Pointer(&patch.Pix[0]), 3, 3, 32, int32(patch.Stride), sdl.PIXELFORMAT_ABGR8888,
+		)
+		check.Eq(t, err, nil)
+		defer patchSurface.Free()
+		texture, err := renderer.CreateTextureFromSurface(patchSurface)
+		check.Eq(t, err, nil)
+		defer texture.Destroy()
+
+		check.Eq(t, renderer.SetDrawColor(0, 0, 0, 255), nil)
+		check.Eq(t, renderer.Clear(), nil)
+		dst := sdl.Rect{X: 1, Y: 1, W: 8, H: 6}
+		borders := sdl.Borders{Left: 1, Top: 1, Right: 1, Bottom: 1}
+		check.Eq(t, renderer.DrawNinePatch(texture, nil, borders, &dst), nil)
+		renderer.Present()
+
+		img, err := surface.ToRGBA()
+		check.Eq(t, err, nil)
+		check.Eq(t, img.RGBAAt(0, 0), color.RGBA{A: 255})
+		check.Eq(t, img.RGBAAt(1, 1), red)
+		check.Eq(t, img.RGBAAt(8, 6), red)
+		check.Eq(t, img.RGBAAt(2, 1), green)
+		check.Eq(t, img.RGBAAt(7, 1), green)
+		check.Eq(t, img.RGBAAt(1, 5), green)
+		check.Eq(t, img.RGBAAt(2, 2), blue)
+		check.Eq(t, img.RGBAAt(7, 5), blue)
+		check.Eq(t, img.RGBAAt(9, 7), color.RGBA{A: 255})
+	})
+}
+
+func TestDrawNinePatchWithoutDestinationFillsTheOutput(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(6, 4)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+
+		red := color.RGBA{R: 255, A: 255}
+		patch := image.NewRGBA(image.Rect(0, 0, 3, 3))
+		for i := 0; i < len(patch.Pix); i += 4 {
+			patch.Pix[i], patch.Pix[i+3] = 255, 255
+		}
+		patchSurface, err := sdl.CreateRGBSurfaceWithFormatFrom(
+			unsafe.Pointer(&patch.Pix[0]), 3, 3, 32, int32(patch.Stride), sdl.PIXELFORMAT_ABGR8888,
+		)
+		check.Eq(t, err, nil)
+		defer patchSurface.Free()
+		texture, err := renderer.CreateTextureFromSurface(patchSurface)
+		check.Eq(t, err, nil)
+		defer texture.Destroy()
+
+		check.Eq(t, renderer.SetDrawColor(0, 0, 0, 255), nil)
+		check.Eq(t, renderer.Clear(), nil)
+		borders := sdl.Borders{Left: 1, Top: 1, Right: 1, Bottom: 1}
+		check.Eq(t, renderer.DrawNinePatch(texture, nil, borders, nil), nil)
+		renderer.Present()
+
+		img, err := surface.ToRGBA()
+		check.Eq(t, err, nil)
+		check.Eq(t, img.RGBAAt(0, 0), red)
+		check.Eq(t, img.RGBAAt(3, 2), red)
+		check.Eq(t, img.RGBAAt(5, 3), red)
+	})
+}
+
+func TestRendererDrawsCirclesAndPolygons(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(21, 21)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+
+		black := color.RGBA{A: 255}
+		white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+		clear := func() {
+			check.Eq(t, renderer.SetDrawColor(0, 0, 0, 255), nil)
+			check.Eq(t, renderer.Clear(), nil)
+			check.Eq(t, renderer.SetDrawColor(255, 255, 255, 255), nil)
+		}
+		render := func() *image.RGBA {
+			renderer.Present()
+			img, err := surface.ToRGBA()
+			check.Eq(t, err, nil)
+			return img
+		}
+
+		clear()
+		check.Eq(t, renderer.DrawCircle(10, 10, 8), nil)
+		outline := render()
+		check.Eq(t, outline.RGBAAt(18, 10), white)
+		check.Eq(t, outline.RGBAAt(2, 10), white)
+		check.Eq(t, outline.RGBAAt(10, 2), white)
+		check.Eq(t, outline.RGBAAt(10, 18), white)
+		check.Eq(t, outline.RGBAAt(10, 10), black)
+		check.Eq(t, outline.RGBAAt(2, 2), black)
+
+		// Every point of the outline is drawn once, points on the axes and
+		// diagonals are not blended twice.
+		clear()
+		check.Eq(t, renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND), nil)
+		check.Eq(t, renderer.SetDrawColor(255, 255, 255, 128), nil)
+		for _, radius := range []int32{0, 3, 8} {
+			check.Eq(t, renderer.DrawCircle(10, 10, radius), nil)
+		}
+		check.Eq(t, renderer.SetDrawBlendMode(sdl.BLENDMODE_NONE), nil)
+		translucent := render()
+		gray := translucent.RGBAAt(18, 10)
+		check.Neq(t, gray, black)
+		check.Neq(t, gray, white)
+		for y := 0; y < 21; y++ {
+			for x := 0; x < 21; x++ {
+				if c := translucent.RGBAAt(x, y); c != black && c != gray {
+					t.Errorf("pixel %d,%d was blended more than once: %v", x, y, c)
+					return
+				}
+			}
+		}
+		check.Eq(t, translucent.RGBAAt(10, 10), gray)
+		check.Eq(t, translucent.RGBAAt(13, 10), gray)
+		check.Eq(t, translucent.RGBAAt(10, 2), gray)
+
+		clear()
+		check.Eq(t, renderer.FillCircle(10, 10, 8), nil)
+		filled := render()
+		for y := 0; y < 21; y++ {
+			for x := 0; x < 21; x++ {
+				// Every outline pixel is filled, the fill does not reach
+				// beyond the radius.
+				if outline.RGBAAt(x, y) == white {
+					check.Eq(t, filled.RGBAAt(x, y), white)
+				}
+				dx, dy := x-10, y-10
+				if dx*dx+dy*dy > 9*9 {
+					check.Eq(t, filled.RGBAAt(x, y), black)
+				}
+			}
+		}
+		check.Eq(t, filled.RGBAAt(10, 10), white)
+		check.Eq(t, filled.RGBAAt(15, 15), white)
+
+		clear()
+		check.Eq(t, renderer.DrawPolygon([]sdl.Point{{X: 1, Y: 1}, {X: 15, Y: 1}, {X: 1, Y: 15}}), nil)
+		triangle := render()
+		check.Eq(t, triangle.RGBAAt(8, 1), white)
+		check.Eq(t, triangle.RGBAAt(1, 8), white)
+		check.Eq(t, triangle.RGBAAt(8, 8), white)
+		check.Eq(t, triangle.RGBAAt(4, 4), black)
+	})
+}
+
+func TestCamera2DTransformsBetweenWorldAndScreen(t *testing.T) {
+	near := func(a, b sdl.FPoint) bool {
+		return math.Abs(float64(a.X-b.X)) < 0.001 && math.Abs(float64(a.Y-b.Y)) < 0.001
+	}
+
+	camera := sdl.NewCamera2D(640, 480)
+	check.Eq(t, camera.WorldToScreen(sdl.FPoint{}), sdl.FPoint{X: 320, Y: 240})
+
+	camera.Position = sdl.FPoint{X: 100, Y: 50}
+	camera.Zoom = 2
+	check.Eq(t, camera.WorldToScreen(sdl.FPoint{X: 110, Y: 50}), sdl.FPoint{X: 340, Y: 240})
+	check.Eq(t, camera.ScreenToWorld(sdl.FPoint{X: 340, Y: 240}), sdl.FPoint{X: 110, Y: 50})
+	check.Eq(t, camera.VisibleBounds(640, 480), sdl.FRect{X: -60, Y: -70, W: 320, H: 240})
+
+	// A clockwise rotation by 90 degrees moves world points right of the
+	// camera below it on the screen.
+	camera.Rotation = 90
+	check.Eq(t, near(camera.WorldToScreen(sdl.FPoint{X: 110, Y: 50}), sdl.FPoint{X: 320, Y: 260}), true)
+	p := sdl.FPoint{X: 17, Y: -3}
+	check.Eq(t, near(camera.ScreenToWorld(camera.WorldToScreen(p)), p), true)
+}
+
+func TestCameraRendererCopiesInWorldCoordinates(t *testing.T) {
+	test(func() {
+		renderer, surface, err := sdl.NewSoftwareRenderer(10, 10)
+		check.Eq(t, err, nil)
+		defer surface.Free()
+		defer renderer.Destroy()
+
+		pixel, err := sdl.CreateRGBSurfaceWithFormat(0, 1, 1, 32, sdl.PIXELFORMAT_ARGB8888)
+		check.Eq(t, err, nil)
+		defer pixel.Free()
+		check.Eq(t, pixel.FillRect(nil, 0xFFFF0000), nil)
+		texture, err := renderer.CreateTextureFromSurface(pixel)
+		check.Eq(t, err, nil)
+		defer texture.Destroy()
+
+		camera := &sdl.Camera2D{
+			Position: sdl.FPoint{X: 100, Y: 100},
+			Offset:   sdl.FPoint{X: 4, Y: 4},
+			Zoom:     2,
+		}
+		world := sdl.CameraRenderer{Renderer: renderer, Camera: camera}
+		check.Eq(t, renderer.SetDrawColor(0, 0, 0, 255), nil)
+		check.Eq(t, renderer.Clear(), nil)
+		check.Eq(t, world.Copy(texture, nil, &sdl.FRect{X: 101, Y: 100, W: 1, H: 2}), nil)
+		renderer.Present()
+
+		img, err := surface.ToRGBA()
+		check.Eq(t, err, nil)
+		red := color.RGBA{R: 255, A: 255}
+		black := color.RGBA{A: 255}
+		check.Eq(t, img.RGBAAt(6, 4), red)
+		check.Eq(t, img.RGBAAt(7, 7), red)
+		check.Eq(t, img.RGBAAt(5, 4), black)
+		check.Eq(t, img.RGBAAt(8, 4), black)
+		check.Eq(t, img.RGBAAt(6, 8), black)
+
+		check.Eq(t, world.Copy(texture, nil, nil), sdl.ErrInvalidParameters)
+		check.Eq(t, world.CopyEx(texture, nil, nil, 45, nil, sdl.FLIP_NONE), sdl.ErrInvalidParameters)
+	})
+}
+
+func TestAnimationAdvancesFramesByMode(t *testing.T) {
+	frames := sdl.GridFrames(0, 0, 16, 16, 2, 3)
+	check.Eq(t, frames, []sdl.Rect{
+		{X: 0, Y: 0, W: 16, H: 16},
+		{X: 16, Y: 0, W: 16, H: 16},
+		{X: 0, Y: 16, W: 16, H: 16},
+	})
+
+	frameIndices := func(a *sdl.Animation, steps int) []int {
+		var indices []int
+		for i := 0; i < steps; i++ {
+			indices = append(indices, a.FrameIndex())
+			a.Update(100 * time.Millisecond)
+		}
+		return indices
+	}
+
+	once := sdl.NewAnimation(frames, 100*time.Millisecond, sdl.ANIMATION_ONCE)
+	check.Eq(t, frameIndices(once, 5), []int{0, 1, 2, 2, 2})
+	check.Eq(t, once.Done(), true)
+	check.Eq(t, once.CurrentFrame(), frames[2])
+	once.Reset()
+	check.Eq(t, once.Done(), false)
+	check.Eq(t, once.CurrentFrame(), frames[0])
+
+	loop := sdl.NewAnimation(frames, 100*time.Millisecond, sdl.ANIMATION_LOOP)
+	check.Eq(t, frameIndices(loop, 7), []int{0, 1, 2, 0, 1, 2, 0})
+	check.Eq(t, loop.Done(), false)
+
+	pingPong := sdl.NewAnimation(frames, 100*time.Millisecond, sdl.ANIMATION_PING_PONG)
+	check.Eq(t, frameIndices(pingPong, 7), []int{0, 1, 2, 1, 0, 1, 2})
+
+	// The last duration is used for all remaining frames.
+	uneven := &sdl.Animation{
+		Frames:    frames,
+		Durations: []time.Duration{300 * time.Millisecond, 100 * time.Millisecond},
+		Mode:      sdl.ANIMATION_LOOP,
+	}
+	check.Eq(t, uneven.Duration(), 500*time.Millisecond)
+	check.Eq(t, frameIndices(uneven, 6), []int{0, 0, 0, 1, 2, 0})
+
+	check.Eq(t, (&sdl.Animation{}).FrameIndex(), -1)
+	check.Eq(t, (&sdl.Animation{}).CurrentFrame(), sdl.Rect{})
+}
+
+func TestAnimatorRestartsAnimationOnSwitch(t *testing.T) {
+	frames := sdl.GridFrames(0, 0, 8, 8, 4, 4)
+	animator := sdl.NewAnimator(nil)
+	animator.Add("idle", sdl.NewAnimation(frames[:2], time.Second, sdl.ANIMATION_LOOP))
+	animator.Add("walk", sdl.NewAnimation(frames[2:], time.Second, sdl.ANIMATION_LOOP))
+	check.Eq(t, animator.Current(), "idle")
+
+	animator.Update(time.Second)
+	check.Eq(t, animator.Animation().CurrentFrame(), frames[1])
+	// Playing the running animation again does not restart it.
+	check.Eq(t, animator.Play("idle"), nil)
+	check.Eq(t, animator.Animation().CurrentFrame(), frames[1])
+
+	check.Eq(t, animator.Play("walk"), nil)
+	animator.Update(time.Second)
+	check.Eq(t, animator.Play("idle"), nil)
+	check.Eq(t, animator.Animation().CurrentFrame(), frames[0])
+
+	check.Neq(t, animator.Play("jump"), nil)
+	check.Eq(t, animator.Current(), "idle")
+}
+
+func TestMissingFunctionsReturnProcError(t *testing.T) {
+	var v sdl.Version
+	sdl.GetVersion(&v)
+	if sdl.VERSIONNUM(int(v.Major), int(v.Minor), int(v.Patch)) >= sdl.VERSIONNUM(2, 0, 14) {
+		t.Skip("the loaded SDL2.dll has the functions")
+	}
+
+	var procErr *sdl.ProcError
+	err := sdl.OpenURL("https://www.libsdl.org")
+	check.Eq(t, errors.As(err, &procErr), true)
+	check.Eq(t, procErr.Name, "SDL_OpenURL")
+
+	_, err = sdl.GetPreferredLocales()
+	check.Eq(t, errors.As(err, &procErr), true)
+	check.Eq(t, procErr.Name, "SDL_GetPreferredLocales")
+}
+
+func TestWindowWithoutMouseRectReportsNone(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+		window, err := sdl.CreateWindow("", 0, 0, 10, 10, sdl.WINDOW_HIDDEN)
+		check.Eq(t, err, nil)
+		defer window.Destroy()
+
+		// This must not panic with SDL versions before 2.0.18 either.
+		rect, ok := window.GetMouseRect()
+		check.Eq(t, ok, false)
+		check.Eq(t, rect, sdl.Rect{})
+	})
+}
+
+func TestCaptureMouseScopedReleasesOnce(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+		window, err := sdl.CreateWindow("", 0, 0, 10, 10, sdl.WINDOW_SHOWN)
+		check.Eq(t, err, nil)
+		defer window.Destroy()
+		window.Raise()
+		sdl.PumpEvents()
+		captured := func() bool {
+			return window.GetFlags()&sdl.WINDOW_MOUSE_CAPTURE != 0
+		}
+
+		release, err := sdl.CaptureMouseScoped()
+		if err != nil {
+			t.Skip("the mouse cannot be captured without input focus:", err)
+		}
+		check.Eq(t, captured(), true)
+		release()
+		check.Eq(t, captured(), false)
+
+		// A second release does not end a later capture.
+		check.Eq(t, sdl.CaptureMouse(true), nil)
+		release()
+		check.Eq(t, captured(), true)
+		check.Eq(t, sdl.CaptureMouse(false), nil)
+	})
+}
+
+func TestRelativeMouseModeScopedRestoresPreviousMode(t *testing.T) {
+	test(func() {
+		if err := sdl.Init(sdl.INIT_VIDEO); err != nil {
+			t.Skip("video not available:", err)
+		}
+		defer sdl.Quit()
+		window, err := sdl.CreateWindow("", 0, 0, 10, 10, sdl.WINDOW_SHOWN)
+		check.Eq(t, err, nil)
+		defer window.Destroy()
+		window.Raise()
+		sdl.PumpEvents()
+
+		check.Eq(t, sdl.GetRelativeMouseMode(), false)
+		restore, err := sdl.RelativeMouseModeScoped()
+		if err != nil {
+			t.Skip("relative mouse mode not available:", err)
+		}
+		check.Eq(t, sdl.GetRelativeMouseMode(), true)
+
+		// Nested calls restore the relative mode of the outer call.
+		restoreInner, err := sdl.RelativeMouseModeScoped()
+		check.Eq(t, err, nil)
+		restoreInner()
+		check.Eq(t, sdl.GetRelativeMouseMode(), true)
+
+		restore()
+		check.Eq(t, sdl.GetRelativeMouseMode(), false)
+
+		// A second restore does not change the mode anymore.
+		check.Eq(t, sdl.SetRelativeMouseMode(true), 0)
+		restore()
+		restoreInner()
+		check.Eq(t, sdl.GetRelativeMouseMode(), true)
+		check.Eq(t, sdl.SetRelativeMouseMode(false), 0)
+	})
+}
+
+func TestPushedDropEventKeepsItsFileName(t *testing.T) {
+	test(func() {
+		check.Eq(t, sdl.Init(sdl.INIT_EVENTS), nil)
+		defer sdl.Quit()
+		sdl.FlushEvents(sdl.FIRSTEVENT, sdl.LASTEVENT)
+
+		drop := sdl.DropEvent{Type: sdl.DROPFILE, File: `C:\dropped.txt`, WindowID: 3}
+		filtered, err := sdl.PushEvent(&drop)
+		check.Eq(t, err, nil)
+		check.Eq(t, filtered, false)
+
+		e := sdl.PollEvent()
+		check.Eq(t, e, &sdl.DropEvent{
+			Type:      sdl.DROPFILE,
+			Timestamp: drop.Timestamp,
+			File:      `C:\dropped.txt`,
+			WindowID:  3,
+		})
+	})
+}
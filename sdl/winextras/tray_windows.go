@@ -0,0 +1,365 @@
+//+build windows
+
+/*
+Package winextras adds Windows specific features to SDL windows that SDL does
+not provide itself, currently a system tray icon with a context menu and
+balloon notifications.
+
+The tray icon sends its mouse messages to the SDL window. SDL passes them on as
+SYSWMEVENT events which have to be given to Tray.HandleEvent:
+
+	for e := sdl.PollEvent(); e != nil; e = sdl.PollEvent() {
+		if tray.HandleEvent(e) {
+			continue
+		}
+		// handle other events
+	}
+
+Like all window functions, use a Tray only from the main thread.
+*/
+package winextras
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"syscall"
+	"unsafe"
+
+	"github.com/gonutz/go-sdl2/sdl"
+)
+
+var (
+	user32  = syscall.NewLazyDLL("user32.dll")
+	shell32 = syscall.NewLazyDLL("shell32.dll")
+	gdi32   = syscall.NewLazyDLL("gdi32.dll")
+
+	shellNotifyIcon    = shell32.NewProc("Shell_NotifyIconW")
+	loadIcon           = user32.NewProc("LoadIconW")
+	createIconIndirect = user32.NewProc("CreateIconIndirect")
+	destroyIcon        = user32.NewProc("DestroyIcon")
+	createPopupMenu    = user32.NewProc("CreatePopupMenu")
+	appendMenu         = user32.NewProc("AppendMenuW")
+	trackPopupMenu     = user32.NewProc("TrackPopupMenu")
+	destroyMenu        = user32.NewProc("DestroyMenu")
+	getCursorPos       = user32.NewProc("GetCursorPos")
+	setForegroundWnd   = user32.NewProc("SetForegroundWindow")
+	postMessage        = user32.NewProc("PostMessageW")
+	createBitmap       = gdi32.NewProc("CreateBitmap")
+	deleteObject       = gdi32.NewProc("DeleteObject")
+)
+
+const (
+	nimAdd    = 0
+	nimModify = 1
+	nimDelete = 2
+
+	nifMessage = 0x01
+	nifIcon    = 0x02
+	nifTip     = 0x04
+	nifInfo    = 0x10
+
+	niifInfo = 0x01
+
+	wmNull        = 0x0000
+	wmLButtonUp   = 0x0202
+	wmRButtonUp   = 0x0205
+	wmTrayMessage = 0x8000 + 0x5D1 // WM_APP + an arbitrary offset
+
+	mfString    = 0x0000
+	mfGrayed    = 0x0001
+	mfSeparator = 0x0800
+
+	tpmRightButton = 0x0002
+	tpmNoNotify    = 0x0080
+	tpmReturnCmd   = 0x0100
+
+	idiApplication = 32512
+)
+
+// notifyIconData is the NOTIFYICONDATAW structure.
+type notifyIconData struct {
+	size            uint32
+	hwnd            uintptr
+	id              uint32
+	flags           uint32
+	callbackMessage uint32
+	icon            uintptr
+	tip             [128]uint16
+	state           uint32
+	stateMask       uint32
+	info            [256]uint16
+	timeout         uint32
+	infoTitle       [64]uint16
+	infoFlags       uint32
+	guidItem        [4]uint32
+	balloonIcon     uintptr
+}
+
+// iconInfo is the ICONINFO structure.
+type iconInfo struct {
+	isIcon   int32
+	hotspotX uint32
+	hotspotY uint32
+	mask     uintptr
+	color    uintptr
+}
+
+// MenuItem is an entry in the context menu of a tray icon.
+type MenuItem struct {
+	// Label is the text of the menu entry. An empty label makes the item a
+	// separator line.
+	Label string
+	// Disabled items are shown grayed out and cannot be clicked.
+	Disabled bool
+	// OnClick is called when the item is selected.
+	OnClick func()
+}
+
+// Tray is an icon in the Windows system tray, belonging to an SDL window.
+type Tray struct {
+	// OnClick is called when the icon is clicked with the left mouse button.
+	OnClick func()
+
+	data       notifyIconData
+	menu       []MenuItem
+	customIcon uintptr
+}
+
+// lastTrayID is used to give every tray icon a unique ID.
+var lastTrayID uint32
+
+// NewTray adds an icon to the system tray. It shows the application's
+// default icon until SetIcon is called. NewTray enables SYSWMEVENT events
+// because the tray icon's mouse messages arrive as these. The tooltip is
+// truncated to 127 UTF-16 characters and must not contain NUL characters.
+func NewTray(window *sdl.Window, tooltip string) (*Tray, error) {
+	var tip [128]uint16
+	if err := copyUTF16(tip[:], tooltip); err != nil {
+		return nil, err
+	}
+	info, err := window.GetWMInfo()
+	if err != nil {
+		return nil, err
+	}
+	icon, _, _ := loadIcon.Call(0, idiApplication)
+
+	lastTrayID++
+	t := &Tray{}
+	t.data.size = uint32(unsafe.Sizeof(t.data))
+	t.data.hwnd = uintptr(info.GetWindowsInfo().Window)
+	t.data.id = lastTrayID
+	t.data.flags = nifMessage | nifIcon | nifTip
+	t.data.callbackMessage = wmTrayMessage
+	t.data.icon = icon
+	t.data.tip = tip
+	if err := t.notify(nimAdd); err != nil {
+		return nil, err
+	}
+	sdl.EventState(sdl.SYSWMEVENT, sdl.ENABLE)
+	return t, nil
+}
+
+// Close removes the icon from the system tray.
+func (t *Tray) Close() error {
+	err := t.notify(nimDelete)
+	if t.customIcon != 0 {
+		destroyIcon.Call(t.customIcon)
+		t.customIcon = 0
+	}
+	return err
+}
+
+// HandleEvent processes mouse messages for the tray icon. It returns true if
+// the event belonged to this tray icon, in which case the caller can ignore
+// it. Right clicks open the menu and call the selected item's OnClick, left
+// clicks call the tray's OnClick.
+func (t *Tray) HandleEvent(e sdl.Event) bool {
+	wm, ok := e.(*sdl.SysWMEvent)
+	if !ok || wm.Msg() == nil {
+		return false
+	}
+	msg := wm.Msg().Windows()
+	// The message is a UINT, only use its lower 32 bits.
+	if msg.Hwnd != t.data.hwnd || uint32(msg.Msg) != wmTrayMessage ||
+		uint32(msg.WParam) != t.data.id {
+		return false
+	}
+	switch uint32(msg.LParam) {
+	case wmLButtonUp:
+		if t.OnClick != nil {
+			t.OnClick()
+		}
+	case wmRButtonUp:
+		t.showMenu()
+	}
+	return true
+}
+
+// Notify shows a balloon notification next to the tray icon. The title is
+// truncated to 63 and the message to 255 UTF-16 characters, neither may
+// contain NUL characters.
+func (t *Tray) Notify(title, message string) error {
+	data := t.data
+	data.flags |= nifInfo
+	data.infoFlags = niifInfo
+	if err := copyUTF16(data.infoTitle[:], title); err != nil {
+		return err
+	}
+	if err := copyUTF16(data.info[:], message); err != nil {
+		return err
+	}
+	ret, _, _ := shellNotifyIcon.Call(nimModify, uintptr(unsafe.Pointer(&data)))
+	if ret == 0 {
+		return errors.New("winextras: Shell_NotifyIcon failed to show the notification")
+	}
+	return nil
+}
+
+// SetIcon replaces the tray icon with the image. Windows scales it to the
+// tray icon size, a 16x16 or 32x32 image works best.
+func (t *Tray) SetIcon(img image.Image) error {
+	icon, err := createIcon(img)
+	if err != nil {
+		return err
+	}
+	old := t.customIcon
+	t.data.icon = icon
+	t.customIcon = icon
+	if old != 0 {
+		destroyIcon.Call(old)
+	}
+	return t.notify(nimModify)
+}
+
+// SetMenu sets the items of the context menu that opens on right clicks. By
+// default there is no menu.
+func (t *Tray) SetMenu(items ...MenuItem) {
+	t.menu = append([]MenuItem(nil), items...)
+}
+
+// SetTooltip sets the text shown when the mouse hovers over the icon. It is
+// truncated to 127 UTF-16 characters and must not contain NUL characters.
+func (t *Tray) SetTooltip(tooltip string) error {
+	var tip [128]uint16
+	if err := copyUTF16(tip[:], tooltip); err != nil {
+		return err
+	}
+	t.data.tip = tip
+	return t.notify(nimModify)
+}
+
+func (t *Tray) notify(message uintptr) error {
+	ret, _, _ := shellNotifyIcon.Call(message, uintptr(unsafe.Pointer(&t.data)))
+	if ret == 0 {
+		return fmt.Errorf("winextras: Shell_NotifyIcon(%d) failed", message)
+	}
+	return nil
+}
+
+func (t *Tray) showMenu() {
+	if len(t.menu) == 0 {
+		return
+	}
+	menu, _, _ := createPopupMenu.Call()
+	if menu == 0 {
+		return
+	}
+	defer destroyMenu.Call(menu)
+	for i, item := range t.menu {
+		if item.Label == "" {
+			appendMenu.Call(menu, mfSeparator, 0, 0)
+			continue
+		}
+		flags := uintptr(mfString)
+		if item.Disabled {
+			flags |= mfGrayed
+		}
+		label, _ := syscall.UTF16PtrFromString(item.Label)
+		// Menu IDs start at 1 because TrackPopupMenu returns 0 if nothing
+		// was selected.
+		appendMenu.Call(menu, flags, uintptr(i+1), uintptr(unsafe.Pointer(label)))
+	}
+
+	var pos struct{ x, y int32 }
+	getCursorPos.Call(uintptr(unsafe.Pointer(&pos)))
+	// The menu only closes on clicks outside of it if the window is in the
+	// foreground.
+	setForegroundWnd.Call(t.data.hwnd)
+	id, _, _ := trackPopupMenu.Call(
+		menu,
+		tpmReturnCmd|tpmNoNotify|tpmRightButton,
+		uintptr(pos.x),
+		uintptr(pos.y),
+		0,
+		t.data.hwnd,
+		0,
+	)
+	postMessage.Call(t.data.hwnd, wmNull, 0, 0)
+	if 1 <= id && int(id) <= len(t.menu) {
+		if onClick := t.menu[id-1].OnClick; onClick != nil {
+			onClick()
+		}
+	}
+}
+
+// createIcon creates a 32 bit icon with alpha channel from the image.
+func createIcon(img image.Image) (uintptr, error) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= 0 || h <= 0 {
+		return 0, errors.New("winextras: icon image is empty")
+	}
+	nrgba := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(nrgba, nrgba.Bounds(), img, b.Min, draw.Src)
+	bgra := make([]byte, w*h*4)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := nrgba.At(x, y).(color.NRGBA)
+			i := (y*w + x) * 4
+			bgra[i+0], bgra[i+1], bgra[i+2], bgra[i+3] = c.B, c.G, c.R, c.A
+		}
+	}
+
+	colorBitmap, _, _ := createBitmap.Call(uintptr(w), uintptr(h), 1, 32, uintptr(unsafe.Pointer(&bgra[0])))
+	if colorBitmap == 0 {
+		return 0, errors.New("winextras: CreateBitmap failed for the icon colors")
+	}
+	defer deleteObject.Call(colorBitmap)
+	// The mask is ignored for 32 bit icons but it must exist.
+	mask := make([]byte, (w+15)/16*2*h)
+	maskBitmap, _, _ := createBitmap.Call(uintptr(w), uintptr(h), 1, 1, uintptr(unsafe.Pointer(&mask[0])))
+	if maskBitmap == 0 {
+		return 0, errors.New("winextras: CreateBitmap failed for the icon mask")
+	}
+	defer deleteObject.Call(maskBitmap)
+
+	info := iconInfo{isIcon: 1, mask: maskBitmap, color: colorBitmap}
+	icon, _, _ := createIconIndirect.Call(uintptr(unsafe.Pointer(&info)))
+	if icon == 0 {
+		return 0, errors.New("winextras: CreateIconIndirect failed")
+	}
+	return icon, nil
+}
+
+// copyUTF16 copies s into the fixed size, zero-terminated buffer, truncating
+// it if necessary. Strings containing NUL cannot be terminated correctly and
+// are rejected.
+func copyUTF16(dst []uint16, s string) error {
+	u, err := syscall.UTF16FromString(s)
+	if err != nil {
+		return fmt.Errorf("winextras: invalid text %q: %v", s, err)
+	}
+	if len(u) > len(dst) {
+		u = u[:len(dst)]
+		u[len(u)-1] = 0
+		// Do not keep half of a surrogate pair.
+		if last := len(u) - 2; last >= 0 && 0xD800 <= u[last] && u[last] < 0xDC00 {
+			u[last] = 0
+		}
+	}
+	copy(dst, u)
+	return nil
+}
@@ -0,0 +1,34 @@
+//+build windows
+
+package winextras
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestCopyUTF16TerminatesText(t *testing.T) {
+	var buf [8]uint16
+	check.Eq(t, copyUTF16(buf[:], "abc"), nil)
+	check.Eq(t, syscall.UTF16ToString(buf[:]), "abc")
+	check.Eq(t, buf[3], uint16(0))
+}
+
+func TestCopyUTF16TruncatesLongText(t *testing.T) {
+	var buf [4]uint16
+	check.Eq(t, copyUTF16(buf[:], "abcdef"), nil)
+	check.Eq(t, buf, [4]uint16{'a', 'b', 'c', 0})
+
+	// The emoji is a surrogate pair, half of it is not kept.
+	check.Eq(t, copyUTF16(buf[:], "ab\U0001F600"), nil)
+	check.Eq(t, buf, [4]uint16{'a', 'b', 0, 0})
+}
+
+func TestCopyUTF16RejectsNUL(t *testing.T) {
+	buf := [4]uint16{'x', 'y', 'z', 0}
+	check.Neq(t, copyUTF16(buf[:], "a\x00b"), nil)
+	// The buffer is left unchanged.
+	check.Eq(t, buf, [4]uint16{'x', 'y', 'z', 0})
+}